@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/config"
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/heartmarshall/digital-forest/backend/internal/migrate"
+	"github.com/heartmarshall/digital-forest/backend/internal/repository/postgres"
+	apiKeysUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/admin/api_keys"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+)
+
+const usage = `usage:
+  app                                                   start the HTTP server
+  app apikey create -name <name> -scope <scope> [-scope <scope>...]
+  app apikey list
+  app apikey revoke <id>
+  app fingerprints backfill [-batch <n>]
+  app migrate up|down|status`
+
+// runCommand выполняет подкоманду обслуживания вместо запуска сервера.
+func runCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	switch args[0] {
+	case "apikey":
+		return runAPIKeyCommand(ctx, cfg, args[1:], os.Stdout)
+	case "fingerprints":
+		return runFingerprintsCommand(ctx, cfg, args[1:], os.Stdout)
+	case "migrate":
+		return runMigrateCommand(ctx, cfg, args[1:], os.Stdout)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command\n%s", usage)
+	}
+}
+
+// runAPIKeyCommand управляет ключами административного API: create, list и revoke.
+// Токен нового ключа печатается один раз - в базе хранится только его хэш.
+func runAPIKeyCommand(ctx context.Context, cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	dbPool, err := connectDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dbPool.Close()
+	keys := apiKeysUseCase.NewAPIKeysUseCase(postgres.NewAPIKeyRepo(dbPool))
+
+	switch args[0] {
+	case "create":
+		var (
+			name   string
+			scopes []string
+		)
+		fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+		fs.StringVar(&name, "name", "", "who or what uses the key, e.g. ci or alice")
+		fs.Func("scope", "scope to grant, repeatable: "+scopeList(), func(s string) error {
+			scopes = append(scopes, strings.Split(s, ",")...)
+			return nil
+		})
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		key, token, err := keys.Create(ctx, name, scopes)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "created key %d (%s) with scopes %s\n", key.ID, key.Name, joinScopes(key.Scopes))
+		fmt.Fprintf(out, "token (shown only once): %s\n", token)
+		return nil
+
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tLAST USED\tSTATUS")
+		for _, k := range list {
+			status := "active"
+			if k.Revoked() {
+				status = "revoked " + formatTime(k.RevokedAt)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				k.ID, k.Name, k.Prefix, joinScopes(k.Scopes), formatTime(k.CreatedAt), formatTime(k.LastUsedAt), status)
+		}
+		return w.Flush()
+
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid key id %q", args[1])
+		}
+		if err := keys.Revoke(ctx, id); err != nil {
+			if errors.Is(err, apikey.ErrNotFound) {
+				return fmt.Errorf("no active key with id %d", id)
+			}
+			return err
+		}
+		fmt.Fprintf(out, "revoked key %d\n", id)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown apikey command %q\n%s", args[0], usage)
+	}
+}
+
+// runFingerprintsCommand обслуживает отпечатки изображений. backfill вычисляет их для растений,
+// посаженных до появления поиска дубликатов; запускать повторно безопасно.
+func runFingerprintsCommand(ctx context.Context, cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "backfill" {
+		return fmt.Errorf("unknown fingerprints command\n%s", usage)
+	}
+
+	var batch int
+	fs := flag.NewFlagSet("fingerprints backfill", flag.ContinueOnError)
+	fs.IntVar(&batch, "batch", duplicatesUseCase.DefaultBackfillBatch, "plants per query")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	dbPool, err := connectDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dbPool.Close()
+
+	result, err := duplicatesUseCase.NewDuplicatesUseCase(postgres.NewPlantRepo(dbPool)).Backfill(ctx, batch)
+	fmt.Fprintf(out, "fingerprinted %d plants, skipped %d with unreadable images\n", result.Updated, result.Skipped)
+	return err
+}
+
+// runMigrateCommand управляет схемой базы встроенными миграциями: up применяет все новые,
+// down откатывает последнюю примененную, status показывает состояние каждой.
+func runMigrateCommand(ctx context.Context, cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(usage)
+	}
+
+	dbPool, err := connectDB(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dbPool.Close()
+
+	migrator, err := migrate.New(dbPool)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %s\n", m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "database is up to date")
+		}
+		return err
+
+	case "down":
+		m, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled back %s\n", m.Name)
+		return nil
+
+	case "status":
+		list, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, m := range list {
+			applied := "pending"
+			if m.Applied {
+				applied = formatTime(m.AppliedAt)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, applied)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], usage)
+	}
+}
+
+func scopeList() string {
+	return joinScopes(apikey.Scopes)
+}
+
+func joinScopes(scopes []apikey.Scope) string {
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ",")
+}
+
+// formatTime печатает время в UTC; нулевое время - прочерк.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
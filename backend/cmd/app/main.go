@@ -2,21 +2,51 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/heartmarshall/digital-forest/backend/internal/config"
+	plantDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+	"github.com/heartmarshall/digital-forest/backend/internal/health"
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+	"github.com/heartmarshall/digital-forest/backend/internal/metrics"
+	"github.com/heartmarshall/digital-forest/backend/internal/migrate"
+	"github.com/heartmarshall/digital-forest/backend/internal/moderation"
+	"github.com/heartmarshall/digital-forest/backend/internal/oidc"
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
 	"github.com/heartmarshall/digital-forest/backend/internal/repository/postgres"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
 	transportHTTP "github.com/heartmarshall/digital-forest/backend/internal/transport/http"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	apiKeysUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/admin/api_keys"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
 	createUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/create"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+	getByIDUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_by_id"
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
 	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	moderateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/moderate"
+	removeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/remove"
+	reportUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/report"
+	streamUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/stream"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	oidcLoginUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/oidc_login"
+	"github.com/heartmarshall/digital-forest/backend/migrations"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
 )
 
 func main() {
@@ -28,39 +58,220 @@ func main() {
 	// 1. Инициализация конфигурации
 	cfg, err := config.New()
 	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+		fatal("failed to load config", err)
 	}
 
-	// 2. Подключение к базе данных
-	// Собираем DSN (Data Source Name) из отдельных полей конфигурации.
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Postgres.User,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DBName,
-		cfg.Postgres.SSLMode,
-	)
+	appLogger, err := logger.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("invalid log config", err)
+	}
+	slog.SetDefault(appLogger)
+
+	// Подкоманды обслуживания работают с той же конфигурацией и базой, но не запускают сервер.
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, cfg, os.Args[1:]); err != nil {
+			fatal("command failed", err, slog.String("command", os.Args[1]))
+		}
+		return
+	}
 
-	dbPool, err := pgxpool.New(ctx, dsn)
+	// Трассировка настраивается до подключения к базе, чтобы в spans попали и первые запросы.
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+		fatal("invalid tracing config", err)
 	}
-	defer dbPool.Close()
 
-	// Проверяем, что соединение с БД действительно установлено.
-	if err := dbPool.Ping(ctx); err != nil {
-		log.Fatalf("database ping failed: %v", err)
+	// 2. Подключение к базе данных
+	dbPool, err := connectDB(ctx, cfg)
+	if err != nil {
+		fatal("failed to connect to database", err)
 	}
+	defer dbPool.Close()
 
-	log.Println("database connection successful")
+	slog.Info("database connection successful")
+
+	if cfg.Postgres.MigrateOnStart {
+		if err := migrateUp(ctx, dbPool); err != nil {
+			fatal("failed to apply migrations", err)
+		}
+	}
 
 	// 3. Сборка всех зависимостей (Dependency Injection)
 	// Идем "изнутри наружу": Repository -> UseCase -> Handler -> Router
-	plantRepo := postgres.NewPlantRepo(dbPool)
-	createUC := createUseCase.NewCreateUseCase(plantRepo)
-	getRandomUC := getRandomUseCase.NewGetRandomUseCase(plantRepo)
-	router := transportHTTP.NewRouter(createUC, getRandomUC) // Роутер создается с зависимостями от use cases
+	sampling, err := postgres.ParseSamplingStrategy(cfg.Postgres.Sampling)
+	if err != nil {
+		fatal("invalid postgres config", err)
+	}
+
+	plantRepo := postgres.NewPlantRepo(dbPool, postgres.WithSamplingStrategy(sampling))
+	userRepo := postgres.NewUserRepo(dbPool)
+	// Шина новых растений, которую /v1/plants/stream раздает подписчикам.
+	// Публикует в нее либо CreateUseCase этого экземпляра, либо listener уведомлений Postgres,
+	// который видит растения со всех реплик. Одновременно оба источника не включаются, чтобы не было дублей.
+	plantBus := events.NewPlantBus(cfg.Stream.Buffer)
+	listenerDone := make(chan struct{})
+	healthChecks := []health.Option{
+		health.WithTimeout(cfg.Health.Timeout),
+		health.WithCheck("database", health.Ping(dbPool, cfg.Health.MaxDBLatency)),
+	}
+
+	var createOpts []createUseCase.Option
+	switch cfg.Stream.Source {
+	case "", "local":
+		close(listenerDone)
+		createOpts = append(createOpts, createUseCase.WithPublisher(plantBus))
+	case "postgres":
+		listener := postgres.NewPlantListener(dbPool, plantBus)
+		healthChecks = append(healthChecks, health.WithCheck("plant_listener", health.Worker(listener.Health)))
+		go func() {
+			defer close(listenerDone)
+			listener.Run(ctx)
+		}()
+	default:
+		fatal("invalid stream config", fmt.Errorf("unknown source %q", cfg.Stream.Source))
+	}
+
+	latestMigration, err := postgres.LatestMigration(migrations.FS)
+	if err != nil {
+		fatal("invalid embedded migrations", err)
+	}
+	healthChecks = append(healthChecks,
+		health.WithCheck("migrations", health.MigrationVersion(postgres.NewMigrationRepo(dbPool), latestMigration)))
+	readiness := health.New(healthChecks...)
+
+	duplicateMode, err := plantDomain.ParseDuplicateMode(cfg.Duplicates.Mode)
+	if err != nil {
+		fatal("invalid duplicates config", err)
+	}
+	if cfg.Duplicates.MaxDistance < 0 || cfg.Duplicates.MaxDistance > 32 {
+		fatal("invalid duplicates config", errors.New("max_distance must be from 0 to 32"))
+	}
+	createOpts = append(createOpts, createUseCase.WithDuplicateCheck(duplicateMode, cfg.Duplicates.MaxDistance))
+
+	// Черный список имен авторов проверяется при посадке и при переименовании растения.
+	updateOpts := []updateUseCase.Option{updateUseCase.WithDuplicateCheck(duplicateMode, cfg.Duplicates.MaxDistance)}
+	if cfg.Authors.Blocklist != "" {
+		blocklist, err := moderation.LoadBlocklist(cfg.Authors.Blocklist)
+		if err != nil {
+			fatal("invalid authors config", err)
+		}
+		createOpts = append(createOpts, createUseCase.WithAuthorChecker(blocklist))
+		updateOpts = append(updateOpts, updateUseCase.WithAuthorChecker(blocklist))
+		go reloadOnHangup(ctx, blocklist)
+	}
+
+	if cfg.Reports.Secret == "" {
+		slog.Warn("reports secret is not set, using a random one: repeated reports will not be recognized after a restart or across replicas")
+	}
+	reports := reportUseCase.NewReportUseCase(plantRepo,
+		reportUseCase.WithThreshold(cfg.Reports.Threshold),
+		reportUseCase.WithReporterSecret(cfg.Reports.Secret),
+	)
+
+	// Провайдеры OpenID Connect загружают discovery-документ при старте: с неверным издателем сервис не запустится.
+	var oidcProviders []oidcLoginUseCase.Provider
+	for _, p := range cfg.OIDC.Providers {
+		provider, err := oidc.NewProvider(ctx, oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+		if err != nil {
+			fatal("failed to set up oidc provider", err, slog.String("provider", p.Name))
+		}
+		oidcProviders = append(oidcProviders, provider)
+	}
+
+	limits, err := rateLimitRules(cfg)
+	if err != nil {
+		fatal("invalid rate_limit config", err)
+	}
+	// Корзины в памяти у каждой реплики свои; при нескольких репликах лимиты нужно хранить в Postgres.
+	var limitStore ratelimit.Store
+	switch cfg.RateLimit.Store {
+	case "", "memory":
+		limitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		limitStore = postgres.NewRateLimitStore(dbPool)
+	default:
+		fatal("invalid rate_limit config", fmt.Errorf("unknown store %q", cfg.RateLimit.Store))
+	}
+
+	var challenges *challengeUseCase.ChallengeUseCase
+	if cfg.Challenge.Enabled {
+		challenges, err = newChallengeUseCase(cfg, dbPool, plantRepo)
+		if err != nil {
+			fatal("invalid challenge config", err)
+		}
+	}
+
+	var idempotencyStore idempotency.Store
+	switch cfg.Idempotency.Store {
+	case "", "memory":
+		idempotencyStore = idempotency.NewMemoryStore()
+	case "postgres":
+		idempotencyStore = postgres.NewIdempotencyStore(dbPool)
+	default:
+		fatal("invalid idempotency config", fmt.Errorf("unknown store %q", cfg.Idempotency.Store))
+	}
+
+	routerOpts := []transportHTTP.RouterOption{
+		transportHTTP.WithAfterLoginURL(cfg.OIDC.AfterLoginURL),
+		transportHTTP.WithRateLimits(limitStore, limits),
+		transportHTTP.WithIdempotency(idempotencyStore, cfg.Idempotency.TTL),
+		transportHTTP.WithLogger(appLogger),
+		transportHTTP.WithHealth(readiness),
+	}
+	if cfg.Auth.InsecureCookie {
+		routerOpts = append(routerOpts, transportHTTP.WithInsecureCookies())
+	}
+	if cfg.Tracing.Exporter != "" && cfg.Tracing.Exporter != tracing.ExporterNone {
+		routerOpts = append(routerOpts, transportHTTP.WithTracing())
+	}
+	// Метрики отдаются отдельным сервером на внутреннем порту, а не публичным роутером.
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		if cfg.Metrics.Port == "" || cfg.Metrics.Port == cfg.HTTP.Port {
+			fatal("invalid metrics config", errors.New("port must be set and differ from http.port"))
+		}
+		m := metrics.New()
+		if err := m.Register(metrics.NewPoolCollector(dbPool), metrics.NewPlantCollector(plantRepo)); err != nil {
+			fatal("failed to register metrics", err)
+		}
+		routerOpts = append(routerOpts, transportHTTP.WithMetrics(m))
+		metricsServer = m.Server(":" + cfg.Metrics.Port)
+	}
+
+	router := transportHTTP.NewRouter(transportHTTP.UseCases{ // Роутер создается с зависимостями от use cases
+		Create:    createUseCase.NewCreateUseCase(plantRepo, createOpts...),
+		GetRandom: getRandomUseCase.NewGetRandomUseCase(plantRepo),
+		GetImage:  getImageUseCase.NewGetImageUseCase(plantRepo),
+		GetByID:   getByIDUseCase.NewGetByIDUseCase(plantRepo),
+		List:      listUseCase.NewListUseCase(plantRepo),
+		Stream:    streamUseCase.NewStreamUseCase(plantBus),
+		Moderate:  moderateUseCase.NewModerateUseCase(plantRepo),
+		Report:    reports,
+		Update:    updateUseCase.NewUpdateUseCase(plantRepo, updateOpts...),
+		Remove:    removeUseCase.NewRemoveUseCase(plantRepo),
+		Duplicates: duplicatesUseCase.NewDuplicatesUseCase(plantRepo,
+			duplicatesUseCase.WithMaxDistance(cfg.Duplicates.MaxDistance),
+		),
+		Auth: authUseCase.NewAuthUseCase(userRepo, authUseCase.WithSessionTTL(cfg.Auth.SessionTTL)),
+		OIDCLogin: oidcLoginUseCase.NewOIDCLoginUseCase(userRepo, oidcProviders,
+			oidcLoginUseCase.WithSessionTTL(cfg.Auth.SessionTTL),
+		),
+		APIKeys:   apiKeysUseCase.NewAPIKeysUseCase(postgres.NewAPIKeyRepo(dbPool)),
+		Challenge: challenges,
+	}, routerOpts...)
 
 	// 4. Настройка и запуск HTTP-сервера
 	server := &http.Server{
@@ -68,28 +279,188 @@ func main() {
 		Handler: router,
 	}
 
-	serverErrors := make(chan error, 1)
+	// Shutdown не прерывает активные запросы и не отслеживает WebSocket-соединения,
+	// поэтому потоки завершаем сами: закрытая шина закрывает все подписки.
+	server.RegisterOnShutdown(plantBus.Close)
+
+	serverErrors := make(chan error, 2)
 
 	go func() {
-		log.Printf("starting server on port %s", cfg.HTTP.Port)
+		slog.Info("starting server", slog.String("port", cfg.HTTP.Port))
 		serverErrors <- server.ListenAndServe()
 	}()
+	if metricsServer != nil {
+		go func() {
+			slog.Info("starting metrics server", slog.String("port", cfg.Metrics.Port))
+			serverErrors <- metricsServer.ListenAndServe()
+		}()
+	}
 
 	select {
 	case err := <-serverErrors:
 		if !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("server error: %v", err)
+			fatal("server error", err)
 		}
 	case <-ctx.Done():
-		log.Println("shutdown signal received")
+		slog.Info("shutdown signal received")
 	}
 
+	// Реплика перестает быть готовой до server.Shutdown: пока балансировщик ее убирает, запросы еще обслуживаются.
+	readiness.Drain()
+	time.Sleep(cfg.Health.DrainDelay)
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("server shutdown failed: %v", err)
+		fatal("server shutdown failed", err)
+	}
+	// Сервер метрик останавливается последним, чтобы сборщик видел и завершение основного.
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("metrics server shutdown failed", slog.Any("error", err))
+		}
+	}
+
+	// Listener завершается по отмене ctx; дожидаемся его до закрытия пула соединений.
+	stop()
+	<-listenerDone
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", slog.Any("error", err))
+	}
+
+	slog.Info("service stopped gracefully")
+}
+
+// fatal пишет ошибку err в лог и завершает процесс, как log.Fatal.
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append([]any{slog.Any("error", err)}, args...)...)
+	os.Exit(1)
+}
+
+// reloadOnHangup перечитывает черный список имен авторов по SIGHUP, пока не отменен ctx.
+// Ошибка в файле не останавливает сервис: продолжает действовать прежний список.
+func reloadOnHangup(ctx context.Context, blocklist *moderation.Blocklist) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			count, err := blocklist.Reload()
+			if err != nil {
+				slog.Error("failed to reload author blocklist", slog.Any("error", err))
+				continue
+			}
+			slog.Info("author blocklist reloaded", slog.Int("rules", count))
+		}
+	}
+}
+
+// rateLimitRules проверяет лимиты из конфигурации и раскладывает их по маршрутам.
+func rateLimitRules(cfg *config.Config) (map[string][]appMiddleware.RateLimitRule, error) {
+	limits := make(map[string][]appMiddleware.RateLimitRule, len(cfg.RateLimit.Routes))
+	for route, rules := range cfg.RateLimit.Routes {
+		if !slices.Contains(transportHTTP.RateLimitedRoutes, route) {
+			return nil, fmt.Errorf("unknown route %q", route)
+		}
+		for _, rule := range rules {
+			key, err := appMiddleware.ParseRateLimitKey(rule.Key)
+			if err != nil {
+				return nil, fmt.Errorf("route %s: %w", route, err)
+			}
+			limit := ratelimit.Limit{Requests: rule.Requests, Period: rule.Period, Burst: rule.Burst}
+			if err := limit.Validate(); err != nil {
+				return nil, fmt.Errorf("route %s: %w", route, err)
+			}
+			limits[route] = append(limits[route], appMiddleware.RateLimitRule{By: key, Limit: limit})
+		}
+	}
+	return limits, nil
+}
+
+// newChallengeUseCase собирает проверку proof-of-work по конфигурации.
+func newChallengeUseCase(cfg *config.Config, dbPool *pgxpool.Pool, plantRepo *postgres.PlantRepo) (*challengeUseCase.ChallengeUseCase, error) {
+	c := cfg.Challenge
+	if c.BaseDifficulty < 0 || c.MaxDifficulty < c.BaseDifficulty || c.MaxDifficulty > 32 {
+		return nil, errors.New("difficulty must satisfy 0 <= base_difficulty <= max_difficulty <= 32")
+	}
+	if c.TTL <= 0 || c.Window <= 0 {
+		return nil, errors.New("ttl and window must be positive")
+	}
+
+	var spent hashcash.SpentStore
+	switch c.Store {
+	case "", "memory":
+		spent = hashcash.NewMemorySpentStore()
+	case "postgres":
+		spent = postgres.NewSpentChallengeStore(dbPool)
+	default:
+		return nil, fmt.Errorf("unknown store %q", c.Store)
 	}
 
-	log.Println("service stopped gracefully")
+	secret := []byte(c.Secret)
+	if len(secret) == 0 {
+		slog.Warn("challenge secret is not set, using a random one: challenges will not survive a restart or work across replicas")
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+
+	return challengeUseCase.NewChallengeUseCase(secret, spent, plantRepo,
+		challengeUseCase.WithDifficulty(c.BaseDifficulty, c.MaxDifficulty),
+		challengeUseCase.WithScaling(c.Window, c.Step),
+		challengeUseCase.WithTTL(c.TTL),
+	), nil
+}
+
+// migrateUp применяет встроенные миграции, которых еще нет в базе.
+// Реплики, стартующие одновременно, ждут друг друга на блокировке goose.
+func migrateUp(ctx context.Context, dbPool *pgxpool.Pool) error {
+	migrator, err := migrate.New(dbPool)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		slog.Info("migration applied", slog.Int64("version", m.Version), slog.String("name", m.Name))
+	}
+	return err
+}
+
+// connectDB подключается к базе данных и проверяет соединение.
+func connectDB(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	// Собираем DSN (Data Source Name) из отдельных полей конфигурации.
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+		cfg.Postgres.User,
+		cfg.Postgres.Password,
+		cfg.Postgres.Host,
+		cfg.Postgres.Port,
+		cfg.Postgres.DBName,
+		cfg.Postgres.SSLMode,
+	)
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+	// Каждый SQL-запрос становится span, если трассировка включена (см. tracing.Setup).
+	poolConfig.ConnConfig.Tracer = postgres.QueryTracer{}
+
+	dbPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Проверяем, что соединение с БД действительно установлено.
+	if err := dbPool.Ping(ctx); err != nil {
+		dbPool.Close()
+		return nil, fmt.Errorf("database ping failed: %w", err)
+	}
+	return dbPool, nil
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
 	"github.com/heartmarshall/digital-forest/backend/internal/repository/postgres"
 	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
 	createUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/create"
@@ -52,28 +53,32 @@ func TestE2E_PlantWorkflow(t *testing.T) {
 		ctx := context.Background()
 
 		// Step 1: Create a plant
-		plant, err := createUC.Create(ctx, "e2e_author", "e2e_image_data")
+		imageData := testutil.GenerateImageData(1)
+		plant, err := createUC.Create(ctx, "e2e_author", imageData)
 		require.NoError(t, err)
 		assert.NotZero(t, plant.ID)
 		assert.Equal(t, "e2e_author", plant.Author)
-		assert.Equal(t, "e2e_image_data", plant.ImageData)
+		require.NotNil(t, plant.Grid)
+		assert.Equal(t, 16, plant.Grid.Width)
+		assert.Empty(t, plant.ImageData) // Исходный PNG не хранится, только сетка
 
 		// Step 2: Create more plants
 		for i := 0; i < 5; i++ {
-			_, err := createUC.Create(ctx, fmt.Sprintf("author_%d", i), fmt.Sprintf("data_%d", i))
+			_, err := createUC.Create(ctx, fmt.Sprintf("author_%d", i), testutil.GenerateImageData(10+i))
 			require.NoError(t, err)
 		}
 
 		// Step 3: Get random plants
-		randomPlants, err := getRandomUC.GetRandom(ctx, 3)
+		randomPage, err := getRandomUC.GetRandom(ctx, domain.RandomFilter{Count: 3}, "")
 		require.NoError(t, err)
+		randomPlants := randomPage.Plants
 		assert.Len(t, randomPlants, 3)
 
 		// Verify all plants have valid data
 		for _, p := range randomPlants {
 			assert.NotZero(t, p.ID)
 			assert.NotEmpty(t, p.Author)
-			assert.NotEmpty(t, p.ImageData)
+			assert.NotNil(t, p.Grid)
 			assert.NotZero(t, p.CreatedAt)
 		}
 	})
@@ -81,11 +86,13 @@ func TestE2E_PlantWorkflow(t *testing.T) {
 	t.Run("error handling", func(t *testing.T) {
 		ctx := context.Background()
 
-		// Test with empty author (this should be handled by validation in real app)
-		_, err := createUC.Create(ctx, "", "valid_data")
-		// Note: In the current implementation, this won't fail at use case level
-		// but would fail at validation level in the HTTP handler
-		assert.NoError(t, err) // Current implementation allows empty author
+		// Empty author is rejected by the use case: nothing is left after normalization
+		_, err := createUC.Create(ctx, "", testutil.GenerateImageData(2))
+		assert.ErrorIs(t, err, domain.ErrAuthorEmpty)
+
+		// Invalid image data is rejected by the use case itself
+		_, err = createUC.Create(ctx, "e2e_author", "e2e_image_data")
+		assert.ErrorIs(t, err, domain.ErrImageEncoding)
 	})
 
 	t.Run("performance test", func(t *testing.T) {
@@ -94,15 +101,16 @@ func TestE2E_PlantWorkflow(t *testing.T) {
 		// Create many plants quickly
 		start := time.Now()
 		for i := 0; i < 100; i++ {
-			_, err := createUC.Create(ctx, fmt.Sprintf("perf_author_%d", i), fmt.Sprintf("perf_data_%d", i))
+			_, err := createUC.Create(ctx, fmt.Sprintf("perf_author_%d", i), testutil.GenerateImageData(1000+i))
 			require.NoError(t, err)
 		}
 		creationTime := time.Since(start)
 
 		// Get random plants
 		start = time.Now()
-		plants, err := getRandomUC.GetRandom(ctx, 50)
+		page, err := getRandomUC.GetRandom(ctx, domain.RandomFilter{Count: 50}, "")
 		require.NoError(t, err)
+		plants := page.Plants
 		retrievalTime := time.Since(start)
 
 		assert.Len(t, plants, 50)
@@ -158,19 +166,20 @@ func TestE2E_DataConsistency(t *testing.T) {
 		ctx := context.Background()
 
 		// Create a plant
-		originalPlant, err := createUC.Create(ctx, "integrity_author", "integrity_data")
+		originalPlant, err := createUC.Create(ctx, "integrity_author", testutil.GenerateImageData(1))
 		require.NoError(t, err)
 
 		// Get random plants and verify the created plant is among them
-		randomPlants, err := getRandomUC.GetRandom(ctx, 10)
+		randomPage, err := getRandomUC.GetRandom(ctx, domain.RandomFilter{Count: 10}, "")
 		require.NoError(t, err)
+		randomPlants := randomPage.Plants
 
 		found := false
 		for _, plant := range randomPlants {
 			if plant.ID == originalPlant.ID {
 				found = true
 				assert.Equal(t, originalPlant.Author, plant.Author)
-				assert.Equal(t, originalPlant.ImageData, plant.ImageData)
+				assert.Equal(t, originalPlant.Grid, plant.Grid)
 				break
 			}
 		}
@@ -186,7 +195,7 @@ func TestE2E_DataConsistency(t *testing.T) {
 
 		for i := 0; i < 10; i++ {
 			go func(i int) {
-				_, err := createUC.Create(ctx, fmt.Sprintf("concurrent_author_%d", i), fmt.Sprintf("concurrent_data_%d", i))
+				_, err := createUC.Create(ctx, fmt.Sprintf("concurrent_author_%d", i), testutil.GenerateImageData(100+i))
 				if err != nil {
 					errors <- err
 					return
@@ -209,8 +218,8 @@ func TestE2E_DataConsistency(t *testing.T) {
 		}
 
 		// Verify all plants were created
-		plants, err := getRandomUC.GetRandom(ctx, 15)
+		page, err := getRandomUC.GetRandom(ctx, domain.RandomFilter{Count: 15}, "")
 		require.NoError(t, err)
-		assert.GreaterOrEqual(t, len(plants), 10)
+		assert.GreaterOrEqual(t, len(page.Plants), 10)
 	})
 }
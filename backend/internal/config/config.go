@@ -2,6 +2,7 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -20,7 +21,132 @@ type Config struct {
 		Password string `mapstructure:"password"`
 		DBName   string `mapstructure:"dbname"`
 		SSLMode  string `mapstructure:"sslmode"`
+		// Sampling - стратегия выбора случайных растений: order_by_random, tablesample или id_probe.
+		Sampling string `mapstructure:"sampling"`
+		// MigrateOnStart применяет встроенные миграции при старте сервера.
+		// Если выключено, схему обновляет команда "app migrate up".
+		MigrateOnStart bool `mapstructure:"migrate_on_start"`
 	} `mapstructure:"postgres"`
+	Stream struct {
+		// Buffer - сколько событий может накопиться у подписчика потока, прежде чем он будет отключен.
+		Buffer int `mapstructure:"buffer"`
+		// Source - откуда берутся события о новых растениях:
+		// local - из CreateUseCase этого экземпляра, postgres - из LISTEN/NOTIFY (для нескольких реплик).
+		Source string `mapstructure:"source"`
+	} `mapstructure:"stream"`
+	Reports struct {
+		// Threshold - сколько открытых жалоб скрывает растение до решения модератора; 0 отключает скрытие.
+		Threshold int `mapstructure:"threshold"`
+		// Secret - ключ HMAC для хэширования адресов посетителей, оставивших жалобу, общий для всех реплик.
+		// Если он пуст, ключ создается при старте и повторные жалобы не распознаются после перезапуска.
+		Secret string `mapstructure:"secret"`
+	} `mapstructure:"reports"`
+	Auth struct {
+		// SessionTTL - время жизни сессии после входа, например "336h".
+		SessionTTL time.Duration `mapstructure:"session_ttl"`
+		// InsecureCookie отправляет cookie сессии без Secure. Только для локальной разработки по http.
+		InsecureCookie bool `mapstructure:"insecure_cookie"`
+	} `mapstructure:"auth"`
+	OIDC struct {
+		// Providers - провайдеры OpenID Connect для входа; имя провайдера становится частью адресов /v1/auth/oidc/{name}/...
+		Providers []struct {
+			Name         string `mapstructure:"name"`
+			Issuer       string `mapstructure:"issuer"`
+			ClientID     string `mapstructure:"client_id"`
+			ClientSecret string `mapstructure:"client_secret"`
+			// RedirectURL - адрес /v1/auth/oidc/{name}/callback, зарегистрированный у провайдера.
+			RedirectURL string `mapstructure:"redirect_url"`
+			// Scopes - запрашиваемые scope; по умолчанию openid, profile и email.
+			Scopes []string `mapstructure:"scopes"`
+		} `mapstructure:"providers"`
+		// AfterLoginURL - куда браузер возвращается после входа через провайдера.
+		AfterLoginURL string `mapstructure:"after_login_url"`
+	} `mapstructure:"oidc"`
+	RateLimit struct {
+		// Store - где хранятся корзины токенов: memory - в памяти процесса,
+		// postgres - в общей таблице (для нескольких реплик).
+		Store string `mapstructure:"store"`
+		// Routes - лимиты по имени маршрута (create_plant, report_plant, auth_login, auth_register).
+		// Запрос должен уложиться во все лимиты своего маршрута.
+		Routes map[string][]struct {
+			// Key - признак, по которому считаются запросы: ip, session или author.
+			Key      string        `mapstructure:"key"`
+			Requests int           `mapstructure:"requests"`
+			Period   time.Duration `mapstructure:"period"`
+			// Burst - сколько запросов можно сделать подряд; по умолчанию равно Requests.
+			Burst int `mapstructure:"burst"`
+		} `mapstructure:"routes"`
+	} `mapstructure:"rate_limit"`
+	Challenge struct {
+		// Enabled требует от анонимной посадки решенной задачи proof-of-work из GET /v1/challenges.
+		Enabled bool `mapstructure:"enabled"`
+		// Secret - ключ HMAC для подписи задач, общий для всех реплик.
+		// Если он пуст, ключ создается при старте и задачи не переживают перезапуск.
+		Secret string `mapstructure:"secret"`
+		// Store - где хранятся использованные задачи: memory или postgres (для нескольких реплик).
+		Store string `mapstructure:"store"`
+		// TTL - сколько действует выданная задача.
+		TTL time.Duration `mapstructure:"ttl"`
+		// BaseDifficulty и MaxDifficulty - сложность в ведущих нулевых битах при обычной нагрузке и ее предел.
+		BaseDifficulty int `mapstructure:"base_difficulty"`
+		MaxDifficulty  int `mapstructure:"max_difficulty"`
+		// Window и Step: когда за Window посажено Step растений, сложность растет на бит,
+		// и еще на бит при каждом удвоении. Step 0 отключает рост.
+		Window time.Duration `mapstructure:"window"`
+		Step   int           `mapstructure:"step"`
+	} `mapstructure:"challenge"`
+	Idempotency struct {
+		// TTL - сколько хранится ответ на POST /v1/plants с заголовком Idempotency-Key; 0 выключает заголовок.
+		TTL time.Duration `mapstructure:"ttl"`
+		// Store - где хранятся ответы: memory или postgres (для нескольких реплик).
+		Store string `mapstructure:"store"`
+	} `mapstructure:"idempotency"`
+	Duplicates struct {
+		// Mode - что делать с растением, похожим на уже посаженное: off, flag (отправить модератору) или reject.
+		Mode string `mapstructure:"mode"`
+		// MaxDistance - на сколько бит из 64 могут отличаться перцептивные хэши похожих изображений.
+		// 0 оставляет только совпадающие изображения и изображения с одинаковыми хэшами.
+		MaxDistance int `mapstructure:"max_distance"`
+	} `mapstructure:"duplicates"`
+	Authors struct {
+		// Blocklist - файл черного списка имен авторов; пустая строка отключает проверку.
+		// Файл перечитывается по SIGHUP.
+		Blocklist string `mapstructure:"blocklist"`
+	} `mapstructure:"authors"`
+	Metrics struct {
+		// Enabled включает метрики Prometheus на GET /metrics.
+		Enabled bool `mapstructure:"enabled"`
+		// Port - порт отдельного внутреннего сервера метрик; на основном порту HTTP они не отдаются.
+		Port string `mapstructure:"port"`
+	} `mapstructure:"metrics"`
+	Tracing struct {
+		// Exporter - куда отправляются spans OpenTelemetry: none (трассировка выключена),
+		// stdout (печать в stdout, для отладки) или otlp (коллектор по OTLP/HTTP).
+		Exporter string `mapstructure:"exporter"`
+		// Endpoint - адрес коллектора, например "otel-collector:4318"; пусто - из OTEL_EXPORTER_OTLP_ENDPOINT.
+		Endpoint string `mapstructure:"endpoint"`
+		// Insecure отправляет spans коллектору по http вместо https.
+		Insecure bool `mapstructure:"insecure"`
+		// ServiceName - имя сервиса в spans.
+		ServiceName string `mapstructure:"service_name"`
+		// SampleRatio - доля записываемых трасс от 0 до 1; запросы с traceparent следуют решению клиента.
+		SampleRatio float64 `mapstructure:"sample_ratio"`
+	} `mapstructure:"tracing"`
+	Log struct {
+		// Level - минимальный уровень записей: debug, info, warn или error.
+		Level string `mapstructure:"level"`
+		// Format - формат записей в stderr: json (для сбора логов) или text (для чтения в терминале).
+		Format string `mapstructure:"format"`
+	} `mapstructure:"log"`
+	Health struct {
+		// Timeout - сколько GET /readyz ждет все проверки.
+		Timeout time.Duration `mapstructure:"timeout"`
+		// MaxDBLatency - время ответа базы на ping, после которого сервис считается неготовым; 0 - без ограничения.
+		MaxDBLatency time.Duration `mapstructure:"max_db_latency"`
+		// DrainDelay - сколько после сигнала остановки /readyz отвечает 503 до server.Shutdown,
+		// чтобы балансировщик успел убрать реплику.
+		DrainDelay time.Duration `mapstructure:"drain_delay"`
+	} `mapstructure:"health"`
 }
 
 // New создает новый экземпляр Config, читая данные из config/config.yaml.
@@ -0,0 +1,150 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+var (
+	// ErrNotFound - ключа нет, он отозван или токен неверный.
+	ErrNotFound = cerror.New(cerror.NotFound, "api key not found")
+	// ErrInvalidName - пустое или слишком длинное имя ключа.
+	ErrInvalidName = cerror.New(cerror.Invalid, "invalid api key name")
+	// ErrInvalidScope - неизвестное право доступа.
+	ErrInvalidScope = cerror.New(cerror.Invalid, "invalid api key scope")
+)
+
+// Scope - право доступа, выданное ключу.
+type Scope string
+
+const (
+	// ScopePlantsModerate - модерация растений и разбор жалоб (/v1/admin).
+	ScopePlantsModerate Scope = "plants:moderate"
+	// ScopePlantsImport - массовая загрузка растений.
+	ScopePlantsImport Scope = "plants:import"
+	// ScopeStatsRead - чтение статистики.
+	ScopeStatsRead Scope = "stats:read"
+)
+
+// Scopes - все известные права в порядке вывода.
+var Scopes = []Scope{ScopePlantsModerate, ScopePlantsImport, ScopeStatsRead}
+
+// TokenPrefix - начало каждого токена. По нему ключ легко опознать в логах и сканерах утечек.
+const TokenPrefix = "dfk_"
+
+const (
+	// maxNameLength - ограничение длины имени ключа в символах.
+	maxNameLength = 64
+	// idBytes - длина открытой части токена, которая хранится как есть и показывается в списке ключей.
+	idBytes = 6
+	// secretBytes - длина секретной части токена.
+	secretBytes = 32
+)
+
+// APIKey - ключ доступа к административному API.
+// Сам токен показывается один раз при создании, а в базе хранится только его хэш Hash.
+type APIKey struct {
+	ID   int
+	Name string
+	// Prefix - открытое начало токена, по которому ключ можно узнать в списке.
+	Prefix     string
+	Hash       string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt time.Time // нулевое значение - ключ еще не использовался
+	RevokedAt  time.Time // нулевое значение - ключ действует
+}
+
+// Revoked сообщает, отозван ли ключ.
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// HasScope сообщает, выдано ли ключу право s.
+func (k APIKey) HasScope(s Scope) bool {
+	return slices.Contains(k.Scopes, s)
+}
+
+// ParseScopes проверяет права из командной строки или базы.
+// Повторы убираются, результат упорядочен как Scopes.
+func ParseScopes(raw []string) ([]Scope, error) {
+	seen := make(map[Scope]bool, len(raw))
+	for _, s := range raw {
+		scope := Scope(strings.TrimSpace(s))
+		if !slices.Contains(Scopes, scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, s)
+		}
+		seen[scope] = true
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+
+	result := make([]Scope, 0, len(seen))
+	for _, scope := range Scopes {
+		if seen[scope] {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+// New создает ключ и возвращает его вместе с токеном вида dfk_<prefix>_<secret>.
+func New(name string, scopes []Scope, now time.Time) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return APIKey{}, "", ErrInvalidName
+	}
+	if len(scopes) == 0 {
+		return APIKey{}, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+
+	id := make([]byte, idBytes)
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(id); err != nil {
+		return APIKey{}, "", fmt.Errorf("apikey.New - id: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, "", fmt.Errorf("apikey.New - secret: %w", err)
+	}
+
+	prefix := TokenPrefix + hex.EncodeToString(id)
+	token := prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      HashToken(token),
+		Scopes:    scopes,
+		CreatedAt: now,
+	}, token, nil
+}
+
+// HashToken возвращает хэш токена для хранения и поиска в базе.
+// В токене 256 случайных бит, поэтому медленный хэш не нужен.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type keyKey struct{}
+
+// WithKey сохраняет проверенный ключ запроса в контексте.
+func WithKey(ctx context.Context, k APIKey) context.Context {
+	return context.WithValue(ctx, keyKey{}, k)
+}
+
+// FromContext возвращает ключ запроса, если он был предъявлен.
+func FromContext(ctx context.Context) (APIKey, bool) {
+	k, ok := ctx.Value(keyKey{}).(APIKey)
+	return k, ok
+}
@@ -0,0 +1,86 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	scopes := []Scope{ScopePlantsModerate}
+
+	// Act
+	key, token, err := New(" ci ", scopes, now)
+	require.NoError(t, err)
+	_, otherToken, err := New("ci", scopes, now)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "ci", key.Name)
+	assert.Equal(t, now, key.CreatedAt)
+	assert.Equal(t, scopes, key.Scopes)
+	assert.False(t, key.Revoked())
+	assert.True(t, strings.HasPrefix(token, key.Prefix+"_"))
+	assert.True(t, strings.HasPrefix(key.Prefix, TokenPrefix))
+	assert.Equal(t, HashToken(token), key.Hash)
+	assert.NotContains(t, key.Hash, token)
+	assert.NotEqual(t, token, otherToken)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		keyName       string
+		scopes        []Scope
+		expectedError error
+	}{
+		{name: "empty name", keyName: "  ", scopes: []Scope{ScopeStatsRead}, expectedError: ErrInvalidName},
+		{name: "long name", keyName: strings.Repeat("я", 65), scopes: []Scope{ScopeStatsRead}, expectedError: ErrInvalidName},
+		{name: "no scopes", keyName: "ci", expectedError: ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := New(tt.keyName, tt.scopes, time.Now())
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      []string
+		expected []Scope
+		wantErr  bool
+	}{
+		{name: "ordered and deduplicated", raw: []string{"stats:read", "plants:moderate", "stats:read"}, expected: []Scope{ScopePlantsModerate, ScopeStatsRead}},
+		{name: "all scopes", raw: []string{"plants:import", "stats:read", "plants:moderate"}, expected: Scopes},
+		{name: "unknown scope", raw: []string{"plants:delete"}, wantErr: true},
+		{name: "empty", raw: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, err := ParseScopes(tt.raw)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidScope)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, scopes)
+		})
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := APIKey{Scopes: []Scope{ScopeStatsRead}}
+
+	assert.True(t, key.HasScope(ScopeStatsRead))
+	assert.False(t, key.HasScope(ScopePlantsModerate))
+}
@@ -0,0 +1,105 @@
+package plant
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Ошибки имени автора. Клиент получает их как ошибки поля author, как при валидации запроса.
+var (
+	// ErrAuthorEmpty - после нормализации от имени автора ничего не осталось.
+	ErrAuthorEmpty = cerror.New(cerror.Invalid, "author name is empty").
+			WithField("author", "field 'author' is required")
+	// ErrAuthorTooLong - нормализованное имя автора длиннее MaxAuthorLength.
+	ErrAuthorTooLong = cerror.New(cerror.Invalid, "author name is too long").
+				WithField("author", fmt.Sprintf("field 'author' is too long (max: %d)", MaxAuthorLength))
+	// ErrAuthorBlocked - имя автора попало в черный список.
+	ErrAuthorBlocked = cerror.New(cerror.Invalid, "author name is not allowed").
+				WithField("author", "field 'author' is not allowed")
+)
+
+// MaxAuthorLength - максимальная длина имени автора в символах (колонка author VARCHAR(255)).
+const MaxAuthorLength = 255
+
+// NormalizeAuthor приводит имя автора к виду, в котором оно хранится и показывается:
+// NFKC (полноширинные буквы, лигатуры и т.п. становятся обычными), без управляющих и невидимых
+// символов форматирования, без пробелов по краям и с одиночными пробелами внутри.
+func NormalizeAuthor(name string) (string, error) {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFKC.String(name) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			// Пробелы нулевой ширины, мягкие переносы и переключатели направления текста.
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteRune(r)
+	}
+
+	normalized := b.String()
+	if normalized == "" {
+		return "", ErrAuthorEmpty
+	}
+	// NFKC может удлинить строку, поэтому длина проверяется и здесь, а не только в валидации запроса.
+	if utf8.RuneCountInString(normalized) > MaxAuthorLength {
+		return "", ErrAuthorTooLong
+	}
+	return normalized, nil
+}
+
+// homoglyphs сводит похожие на вид символы к одному: кириллицу и греческие буквы - к латинским двойникам,
+// цифры и знаки - к буквам, которые они изображают. Ключи - строчные символы.
+var homoglyphs = map[rune]rune{
+	// Кириллица
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ь': 'b', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd',
+	'ԛ': 'q', 'ԝ': 'w',
+	// Греческий
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// Цифры и знаки
+	'0': 'o', '1': 'l', '|': 'l', '!': 'i', '3': 'з', '4': 'ч', '6': 'б', '@': 'a', '$': 's',
+}
+
+// AuthorSkeleton возвращает скелет имени для сравнения с черным списком: строчные буквы,
+// двойники сведены к одному символу (см. homoglyphs), пробелы и знаки препинания убраны.
+// Так "Аdmin" с кириллической А, "a.d.m.i.n" и "@DMIN" дают один и тот же скелет.
+func AuthorSkeleton(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if folded, ok := homoglyphs[r]; ok {
+			r = folded
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FoldHomoglyphs сводит к латинским двойникам только буквы за пределами ASCII, не трогая остальное.
+// Так можно привести к виду скелета регулярное выражение, не сломав его синтаксис (\d, {0,3} и т.п.).
+func FoldHomoglyphs(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf {
+			return r
+		}
+		if folded, ok := homoglyphs[unicode.ToLower(r)]; ok {
+			return folded
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,88 @@
+package plant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAuthor(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedError error
+	}{
+		{name: "plain", input: "Лесник", expected: "Лесник"},
+		{name: "trimmed", input: "  forest keeper\t\n", expected: "forest keeper"},
+		{name: "inner whitespace collapsed", input: "лесной    житель", expected: "лесной житель"},
+		{name: "fullwidth letters", input: "ｆｏｒｅｓｔ", expected: "forest"},
+		{name: "ligature", input: "ﬁr tree", expected: "fir tree"},
+		{name: "decomposed й is composed", input: "\u0438\u0306ожик", expected: "йожик"},
+		{name: "zero width and bidi characters", input: "ad\u200bmin\u202e", expected: "admin"},
+		{name: "control characters", input: "tree\x00\x07", expected: "tree"},
+		{name: "only invisible characters", input: "\u200b\u200d \u2060", expectedError: ErrAuthorEmpty},
+		{name: "empty", input: "", expectedError: ErrAuthorEmpty},
+		{name: "max length", input: strings.Repeat("я", MaxAuthorLength), expected: strings.Repeat("я", MaxAuthorLength)},
+		{name: "too long after NFKC", input: strings.Repeat("㎏", 200), expectedError: ErrAuthorTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			normalized, err := NormalizeAuthor(tt.input)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestAuthorSkeleton(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		same  string
+	}{
+		{name: "case", input: "ADMIN", same: "admin"},
+		{name: "cyrillic lookalikes in latin word", input: "аdmіn", same: "admin"},
+		{name: "latin lookalikes in cyrillic word", input: "xyй", same: "хуй"},
+		{name: "greek lookalikes", input: "αdmιn", same: "admin"},
+		{name: "separators removed", input: "a.d m_i-n", same: "admin"},
+		{name: "digits and symbols", input: "@dm!n", same: "admin"},
+		{name: "zero", input: "r00t", same: "root"},
+		{name: "three as ze", input: "пи3да", same: "пизда"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, AuthorSkeleton(tt.same), AuthorSkeleton(tt.input))
+		})
+	}
+
+	// Буквы без двойников остаются различимыми
+	assert.NotEqual(t, AuthorSkeleton("мой"), AuthorSkeleton("мои"))
+}
+
+func TestFoldHomoglyphs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "х+у+[йя]", expected: "x+y+[йя]"},
+		{input: `АДМИН\d{0,3}`, expected: `aДmИh\d{0,3}`},
+		{input: "[a-z]+", expected: "[a-z]+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FoldHomoglyphs(tt.input))
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package plant
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"math/bits"
+	"slices"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrDuplicate - изображение совпадает с уже посаженным растением или почти не отличается от него.
+var ErrDuplicate = cerror.New(cerror.Conflict, "plant duplicates an existing plant")
+
+// Fingerprint - отпечаток изображения растения. ContentHash совпадает только у одинаковых
+// изображений, AHash и DHash - перцептивные хэши, у похожих изображений они отличаются в нескольких битах.
+// Прозрачные поля вокруг рисунка не учитываются: тот же спрайт, сдвинутый по холсту, дает тот же отпечаток.
+type Fingerprint struct {
+	ContentHash string
+	AHash       uint64
+	DHash       uint64
+}
+
+// IsZero сообщает, что отпечаток не вычислялся (растения, посаженные до его появления).
+func (f Fingerprint) IsZero() bool {
+	return f.ContentHash == ""
+}
+
+// Distance - расстояние Хэмминга между отпечатками: большее из расстояний по AHash и по DHash.
+// Так изображения считаются похожими, только если похожи и яркость, и контуры.
+func (f Fingerprint) Distance(other Fingerprint) int {
+	return max(bits.OnesCount64(f.AHash^other.AHash), bits.OnesCount64(f.DHash^other.DHash))
+}
+
+// Fingerprint вычисляет отпечаток сетки.
+// Перцептивные хэши считаются по яркости клеток, наложенных на белый фон,
+// после уменьшения рисунка до 8x8 (aHash) и 9x8 (dHash) с усреднением по площади.
+func (g Grid) Fingerprint() Fingerprint {
+	x0, y0, w, h := g.bounds()
+
+	sum := sha256.New()
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(w))
+	binary.BigEndian.PutUint32(header[4:], uint32(h))
+	sum.Write(header[:])
+
+	luma := make([]float64, 0, w*h)
+	for y := y0; y < y0+h; y++ {
+		for x := x0; x < x0+w; x++ {
+			c := g.color(x, y)
+			// Хэшируем сами цвета, а не индексы: порядок палитры у одинаковых рисунков может отличаться.
+			sum.Write([]byte{c.R, c.G, c.B, c.A})
+			luma = append(luma, luminance(c))
+		}
+	}
+
+	return Fingerprint{
+		ContentHash: hex.EncodeToString(sum.Sum(nil)),
+		AHash:       averageHash(resample(luma, w, h, 8, 8)),
+		DHash:       differenceHash(resample(luma, w, h, 9, 8)),
+	}
+}
+
+// color возвращает цвет клетки; все полностью прозрачные цвета равны.
+func (g Grid) color(x, y int) color.NRGBA {
+	c := g.Palette[g.Pixels[y*g.Width+x]]
+	if c.A == 0 {
+		return color.NRGBA{}
+	}
+	return c
+}
+
+// bounds возвращает прямоугольник, в котором лежат все непрозрачные клетки.
+// У полностью прозрачной сетки это вся сетка.
+func (g Grid) bounds() (x0, y0, w, h int) {
+	minX, minY, maxX, maxY := g.Width, g.Height, -1, -1
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if g.color(x, y).A == 0 {
+				continue
+			}
+			minX, minY = min(minX, x), min(minY, y)
+			maxX, maxY = max(maxX, x), max(maxY, y)
+		}
+	}
+	if maxX < 0 {
+		return 0, 0, g.Width, g.Height
+	}
+	return minX, minY, maxX - minX + 1, maxY - minY + 1
+}
+
+// luminance - яркость цвета, наложенного на белый фон (ITU-R BT.601).
+func luminance(c color.NRGBA) float64 {
+	alpha := float64(c.A) / 255
+	over := func(v uint8) float64 { return float64(v)*alpha + 255*(1-alpha) }
+	return 0.299*over(c.R) + 0.587*over(c.G) + 0.114*over(c.B)
+}
+
+// resample меняет размер картинки w x h на tw x th, усредняя исходные клетки с весом по площади пересечения.
+// Работает и на уменьшение, и на увеличение маленьких рисунков.
+func resample(src []float64, w, h, tw, th int) []float64 {
+	dst := make([]float64, tw*th)
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := float64(ty*h)/float64(th), float64((ty+1)*h)/float64(th)
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := float64(tx*w)/float64(tw), float64((tx+1)*w)/float64(tw)
+
+			var total, area float64
+			for sy := int(y0); sy < h && float64(sy) < y1; sy++ {
+				dy := min(y1, float64(sy+1)) - max(y0, float64(sy))
+				for sx := int(x0); sx < w && float64(sx) < x1; sx++ {
+					dx := min(x1, float64(sx+1)) - max(x0, float64(sx))
+					total += src[sy*w+sx] * dx * dy
+					area += dx * dy
+				}
+			}
+			dst[ty*tw+tx] = total / area
+		}
+	}
+	return dst
+}
+
+// averageHash ставит бит для каждой клетки 8x8, которая ярче среднего.
+func averageHash(cells []float64) uint64 {
+	var mean float64
+	for _, v := range cells {
+		mean += v
+	}
+	mean /= float64(len(cells))
+
+	var hash uint64
+	for i, v := range cells {
+		if v > mean {
+			hash |= 1 << (63 - i)
+		}
+	}
+	return hash
+}
+
+// differenceHash ставит бит, если клетка темнее соседа справа, по строкам картинки 9x8.
+func differenceHash(cells []float64) uint64 {
+	var hash uint64
+	i := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if cells[y*9+x] < cells[y*9+x+1] {
+				hash |= 1 << (63 - i)
+			}
+			i++
+		}
+	}
+	return hash
+}
+
+// DefaultDuplicateDistance - на сколько бит могут отличаться отпечатки похожих растений, если порог не задан.
+const DefaultDuplicateDistance = 5
+
+// DuplicateMode - что делать с растением, похожим на уже посаженное.
+type DuplicateMode string
+
+const (
+	// DuplicateOff - не проверять.
+	DuplicateOff DuplicateMode = "off"
+	// DuplicateFlag - посадить, но отправить на проверку модератору (StatusPending).
+	DuplicateFlag DuplicateMode = "flag"
+	// DuplicateReject - отказать с ErrDuplicate.
+	DuplicateReject DuplicateMode = "reject"
+)
+
+// ParseDuplicateMode проверяет название режима из конфигурации. Пустая строка означает DuplicateOff.
+func ParseDuplicateMode(s string) (DuplicateMode, error) {
+	switch mode := DuplicateMode(s); mode {
+	case "":
+		return DuplicateOff, nil
+	case DuplicateOff, DuplicateFlag, DuplicateReject:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown duplicate mode %q", s)
+	}
+}
+
+// Duplicate - ближайшее к новому изображению растение.
+type Duplicate struct {
+	PlantID  int
+	Distance int
+	// Exact - изображения совпадают полностью (одинаковый ContentHash).
+	Exact bool
+}
+
+// FingerprintedPlant - растение с отпечатком, без изображения.
+type FingerprintedPlant struct {
+	ID           int
+	Author       string
+	Status       Status
+	ImageVersion int
+	CreatedAt    time.Time
+	Fingerprint  Fingerprint
+}
+
+// DuplicateCluster - группа похожих растений, от старых к новым.
+type DuplicateCluster struct {
+	Plants []FingerprintedPlant
+	// Exact - у всех растений группы одинаковое изображение.
+	Exact bool
+}
+
+// ClusterDuplicates объединяет растения, отпечатки которых отличаются не больше чем на maxDistance.
+// Похожесть транзитивна (single linkage): если A похоже на B, а B на C, все трое попадут в одну группу,
+// даже если A и C далеки. Возвращаются только группы из двух и более растений, самые большие первыми.
+// Сравниваются все пары, поэтому время растет квадратично с числом растений.
+func ClusterDuplicates(plants []FingerprintedPlant, maxDistance int) []DuplicateCluster {
+	parent := make([]int, len(plants))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range plants {
+		for j := i + 1; j < len(plants); j++ {
+			if plants[i].Fingerprint.Distance(plants[j].Fingerprint) <= maxDistance {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]FingerprintedPlant)
+	for i, p := range plants {
+		root := find(i)
+		groups[root] = append(groups[root], p)
+	}
+
+	clusters := make([]DuplicateCluster, 0)
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		slices.SortFunc(members, func(a, b FingerprintedPlant) int {
+			return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+		})
+		exact := true
+		for _, p := range members[1:] {
+			exact = exact && p.Fingerprint.ContentHash == members[0].Fingerprint.ContentHash
+		}
+		clusters = append(clusters, DuplicateCluster{Plants: members, Exact: exact})
+	}
+
+	slices.SortFunc(clusters, func(a, b DuplicateCluster) int {
+		return cmp.Or(cmp.Compare(len(b.Plants), len(a.Plants)), cmp.Compare(a.Plants[0].ID, b.Plants[0].ID))
+	})
+	return clusters
+}
@@ -0,0 +1,168 @@
+package plant
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// treeGrid рисует на прозрачном холсте width x height дерево: крону в виде ромба и ствол,
+// левый верхний угол рисунка - в (dx, dy).
+func treeGrid(width, height, dx, dy int) Grid {
+	g := Grid{
+		Width:   width,
+		Height:  height,
+		Palette: []color.NRGBA{{}, {34, 139, 34, 255}, {101, 67, 33, 255}},
+		Pixels:  make([]uint8, width*height),
+	}
+	set := func(x, y int, idx uint8) { g.Pixels[(dy+y)*width+dx+x] = idx }
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if abs(x-4)+abs(y-4) <= 4 {
+				set(x, y, 1)
+			}
+		}
+	}
+	for y := 9; y < 13; y++ {
+		set(4, y, 2)
+	}
+	return g
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestGrid_Fingerprint(t *testing.T) {
+	base := treeGrid(16, 16, 3, 1)
+
+	t.Run("same picture elsewhere on the canvas", func(t *testing.T) {
+		// Act
+		moved := treeGrid(32, 32, 20, 10).Fingerprint()
+
+		// Assert
+		assert.Equal(t, base.Fingerprint(), moved)
+	})
+
+	t.Run("palette order does not matter", func(t *testing.T) {
+		// Arrange
+		reordered := base
+		reordered.Palette = []color.NRGBA{{101, 67, 33, 255}, {34, 139, 34, 255}, {}}
+		reordered.Pixels = make([]uint8, len(base.Pixels))
+		for i, idx := range base.Pixels {
+			reordered.Pixels[i] = 2 - idx
+		}
+
+		// Act & Assert
+		assert.Equal(t, base.Fingerprint(), reordered.Fingerprint())
+	})
+
+	t.Run("small edit is near", func(t *testing.T) {
+		// Arrange
+		edited := treeGrid(16, 16, 3, 1)
+		edited.Palette[2] = color.NRGBA{90, 60, 30, 255}
+		edited.Pixels[4*16+3+4] = 2
+
+		// Act
+		fp := edited.Fingerprint()
+
+		// Assert
+		assert.NotEqual(t, base.Fingerprint().ContentHash, fp.ContentHash)
+		assert.LessOrEqual(t, base.Fingerprint().Distance(fp), 6)
+	})
+
+	t.Run("different picture is far", func(t *testing.T) {
+		// Arrange
+		other := testGrid(16, 16, 2)
+
+		// Act & Assert
+		assert.Greater(t, base.Fingerprint().Distance(other.Fingerprint()), 10)
+	})
+
+	t.Run("fully transparent grid", func(t *testing.T) {
+		// Arrange
+		empty := Grid{Width: 4, Height: 4, Palette: []color.NRGBA{{}}, Pixels: make([]uint8, 16)}
+
+		// Act
+		fp := empty.Fingerprint()
+
+		// Assert
+		assert.False(t, fp.IsZero())
+		assert.Zero(t, fp.AHash)
+		assert.Zero(t, fp.DHash)
+	})
+}
+
+func TestParseDuplicateMode(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      DuplicateMode
+		expectedError bool
+	}{
+		{input: "", expected: DuplicateOff},
+		{input: "off", expected: DuplicateOff},
+		{input: "flag", expected: DuplicateFlag},
+		{input: "reject", expected: DuplicateReject},
+		{input: "block", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mode, err := ParseDuplicateMode(tt.input)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestClusterDuplicates(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	plant := func(id int, hash string, ahash uint64) FingerprintedPlant {
+		return FingerprintedPlant{
+			ID:          id,
+			CreatedAt:   now.Add(time.Duration(id) * time.Minute),
+			Fingerprint: Fingerprint{ContentHash: hash, AHash: ahash, DHash: ahash},
+		}
+	}
+	plants := []FingerprintedPlant{
+		plant(5, "c", 0b111),
+		plant(1, "a", 0),
+		plant(2, "a", 0),
+		plant(3, "b", 0b1),
+		plant(4, "d", 0xFFFF_0000),
+		plant(6, "e", 0xFFFF_0000_0000),
+		plant(7, "e", 0xFFFF_0000_0000),
+	}
+
+	// Act
+	clusters := ClusterDuplicates(plants, 2)
+
+	// Assert
+	require.Len(t, clusters, 2)
+
+	// 1 и 2 совпадают, 3 отличается от них на бит, 5 - на два бита от 3 (цепочка)
+	ids := func(c DuplicateCluster) []int {
+		result := make([]int, len(c.Plants))
+		for i, p := range c.Plants {
+			result[i] = p.ID
+		}
+		return result
+	}
+	assert.Equal(t, []int{1, 2, 3, 5}, ids(clusters[0]))
+	assert.False(t, clusters[0].Exact)
+	assert.Equal(t, []int{6, 7}, ids(clusters[1]))
+	assert.True(t, clusters[1].Exact)
+
+	assert.Empty(t, ClusterDuplicates(plants, -1))
+}
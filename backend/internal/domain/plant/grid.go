@@ -0,0 +1,243 @@
+package plant
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrGridInvalid - пиксельная сетка растения некорректна
+// (неверные размеры, палитра или индексы пикселей).
+var ErrGridInvalid = cerror.New(cerror.Unprocessable, "pixel grid is not valid")
+
+// gridFormatVersion - версия бинарного формата сетки, см. MarshalBinary.
+const gridFormatVersion = 1
+
+// maxPaletteSize - индексы пикселей хранятся в одном байте.
+const maxPaletteSize = 256
+
+// Grid - каноническое представление растения: сетка клеток, каждая из которых
+// ссылается на цвет палитры. Пиксели хранятся построчно, слева направо.
+type Grid struct {
+	Width   int
+	Height  int
+	Palette []color.NRGBA
+	Pixels  []uint8
+}
+
+// GridFromImage строит сетку по изображению, выровненному по клеткам cellSize x cellSize.
+// Цвет клетки берется из ее левого верхнего пикселя, палитра собирается в порядке появления цветов.
+// Изображение должно быть предварительно проверено через DecodeImage.
+func GridFromImage(img image.Image, cellSize int) (Grid, error) {
+	if cellSize < 1 {
+		cellSize = 1
+	}
+
+	bounds := img.Bounds()
+	grid := Grid{
+		Width:  bounds.Dx() / cellSize,
+		Height: bounds.Dy() / cellSize,
+	}
+	grid.Pixels = make([]uint8, 0, grid.Width*grid.Height)
+
+	indexes := make(map[color.NRGBA]uint8)
+	for cy := 0; cy < grid.Height; cy++ {
+		for cx := 0; cx < grid.Width; cx++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+cx*cellSize, bounds.Min.Y+cy*cellSize)).(color.NRGBA)
+			if c.A == 0 {
+				// Все полностью прозрачные цвета считаем одним цветом.
+				c = color.NRGBA{}
+			}
+
+			idx, ok := indexes[c]
+			if !ok {
+				if len(grid.Palette) == maxPaletteSize {
+					return Grid{}, fmt.Errorf("%w: more than %d colors", ErrGridInvalid, maxPaletteSize)
+				}
+				idx = uint8(len(grid.Palette))
+				indexes[c] = idx
+				grid.Palette = append(grid.Palette, c)
+			}
+			grid.Pixels = append(grid.Pixels, idx)
+		}
+	}
+
+	return grid, nil
+}
+
+// Validate проверяет согласованность сетки и ее соответствие ограничениям на изображение.
+// Максимальный размер сетки - это максимальный размер изображения, деленный на размер клетки.
+func (g Grid) Validate(limits ImageLimits) error {
+	cellSize := limits.CellSize
+	if cellSize < 1 {
+		cellSize = 1
+	}
+	maxWidth, maxHeight := limits.MaxWidth/cellSize, limits.MaxHeight/cellSize
+
+	if g.Width <= 0 || g.Height <= 0 || g.Width > maxWidth || g.Height > maxHeight {
+		return fmt.Errorf("%w: size %dx%d, max is %dx%d", ErrGridInvalid, g.Width, g.Height, maxWidth, maxHeight)
+	}
+	if len(g.Palette) == 0 || len(g.Palette) > maxPaletteSize {
+		return fmt.Errorf("%w: palette must have from 1 to %d colors", ErrGridInvalid, maxPaletteSize)
+	}
+	if len(g.Pixels) != g.Width*g.Height {
+		return fmt.Errorf("%w: expected %d pixels, got %d", ErrGridInvalid, g.Width*g.Height, len(g.Pixels))
+	}
+	for i, p := range g.Pixels {
+		if int(p) >= len(g.Palette) {
+			return fmt.Errorf("%w: pixel %d refers to missing palette color %d", ErrGridInvalid, i, p)
+		}
+	}
+	return nil
+}
+
+// Image рисует сетку, увеличивая каждую клетку до scale x scale пикселей (nearest neighbour).
+func (g Grid) Image(scale int) *image.Paletted {
+	if scale < 1 {
+		scale = 1
+	}
+
+	palette := make(color.Palette, len(g.Palette))
+	for i, c := range g.Palette {
+		palette[i] = c
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, g.Width*scale, g.Height*scale), palette)
+	for y := 0; y < g.Height*scale; y++ {
+		row := g.Pixels[(y/scale)*g.Width : (y/scale+1)*g.Width]
+		line := img.Pix[y*img.Stride : y*img.Stride+g.Width*scale]
+		for x := range line {
+			line[x] = row[x/scale]
+		}
+	}
+	return img
+}
+
+// PNG кодирует сетку в PNG с клетками scale x scale пикселей.
+func (g Grid) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, g.Image(scale)); err != nil {
+		return nil, fmt.Errorf("Grid - PNG - png.Encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// bitsPerPixel возвращает число бит на индекс пикселя: 1, 2, 4 или 8.
+func (g Grid) bitsPerPixel() int {
+	bits := 1
+	for (1<<bits) < len(g.Palette) && bits < 8 {
+		bits *= 2
+	}
+	return bits
+}
+
+// MarshalBinary кодирует сетку в компактный бинарный формат для хранения:
+//
+//	[версия][ширина][высота][размер палитры - 1][палитра RGBA...][индексы пикселей...]
+//
+// Индексы упакованы по 1, 2, 4 или 8 бит в зависимости от размера палитры.
+func (g Grid) MarshalBinary() ([]byte, error) {
+	if g.Width <= 0 || g.Width > 255 || g.Height <= 0 || g.Height > 255 {
+		return nil, fmt.Errorf("%w: size %dx%d cannot be encoded", ErrGridInvalid, g.Width, g.Height)
+	}
+	if len(g.Palette) == 0 || len(g.Palette) > maxPaletteSize || len(g.Pixels) != g.Width*g.Height {
+		return nil, fmt.Errorf("%w: inconsistent palette or pixels", ErrGridInvalid)
+	}
+
+	bits := g.bitsPerPixel()
+	perByte := 8 / bits
+
+	buf := make([]byte, 0, 4+4*len(g.Palette)+(len(g.Pixels)+perByte-1)/perByte)
+	buf = append(buf, gridFormatVersion, byte(g.Width), byte(g.Height), byte(len(g.Palette)-1))
+	for _, c := range g.Palette {
+		buf = append(buf, c.R, c.G, c.B, c.A)
+	}
+
+	var cur byte
+	for i, p := range g.Pixels {
+		shift := 8 - bits*(i%perByte+1)
+		cur |= p << shift
+		if i%perByte == perByte-1 || i == len(g.Pixels)-1 {
+			buf = append(buf, cur)
+			cur = 0
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary декодирует сетку из формата MarshalBinary.
+func (g *Grid) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("%w: encoded grid is too short", ErrGridInvalid)
+	}
+	if data[0] != gridFormatVersion {
+		return fmt.Errorf("%w: unknown format version %d", ErrGridInvalid, data[0])
+	}
+
+	decoded := Grid{
+		Width:   int(data[1]),
+		Height:  int(data[2]),
+		Palette: make([]color.NRGBA, int(data[3])+1),
+	}
+	data = data[4:]
+
+	if len(data) < 4*len(decoded.Palette) {
+		return fmt.Errorf("%w: encoded palette is truncated", ErrGridInvalid)
+	}
+	for i := range decoded.Palette {
+		decoded.Palette[i] = color.NRGBA{R: data[4*i], G: data[4*i+1], B: data[4*i+2], A: data[4*i+3]}
+	}
+	data = data[4*len(decoded.Palette):]
+
+	bits := decoded.bitsPerPixel()
+	perByte := 8 / bits
+	total := decoded.Width * decoded.Height
+	if len(data) != (total+perByte-1)/perByte {
+		return fmt.Errorf("%w: encoded pixels have wrong length", ErrGridInvalid)
+	}
+
+	mask := byte(1<<bits - 1)
+	decoded.Pixels = make([]uint8, total)
+	for i := range decoded.Pixels {
+		shift := 8 - bits*(i%perByte+1)
+		decoded.Pixels[i] = (data[i/perByte] >> shift) & mask
+	}
+
+	*g = decoded
+	return nil
+}
+
+// ParseColor разбирает цвет в формате "#RRGGBB", "#RRGGBBAA" или "transparent".
+func ParseColor(s string) (color.NRGBA, error) {
+	if strings.EqualFold(s, "transparent") {
+		return color.NRGBA{}, nil
+	}
+	if !strings.HasPrefix(s, "#") || (len(s) != 7 && len(s) != 9) {
+		return color.NRGBA{}, fmt.Errorf("%w: color %q must be #RRGGBB or #RRGGBBAA", ErrGridInvalid, s)
+	}
+
+	raw, err := hex.DecodeString(s[1:])
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: color %q is not hex", ErrGridInvalid, s)
+	}
+
+	c := color.NRGBA{R: raw[0], G: raw[1], B: raw[2], A: 255}
+	if len(raw) == 4 {
+		c.A = raw[3]
+	}
+	return c, nil
+}
+
+// FormatColor возвращает цвет в формате "#RRGGBB" для непрозрачных цветов и "#RRGGBBAA" для остальных.
+func FormatColor(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
@@ -0,0 +1,180 @@
+package plant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGrid возвращает сетку width x height, в которой используется paletteSize цветов.
+func testGrid(width, height, paletteSize int) Grid {
+	g := Grid{Width: width, Height: height}
+	for i := 0; i < paletteSize; i++ {
+		g.Palette = append(g.Palette, color.NRGBA{R: uint8(i), G: uint8(255 - i), B: 7, A: 255})
+	}
+	for i := 0; i < width*height; i++ {
+		g.Pixels = append(g.Pixels, uint8(i%paletteSize))
+	}
+	return g
+}
+
+func TestGrid_BinaryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		grid        Grid
+		expectedLen int
+	}{
+		{name: "1 bit per pixel", grid: testGrid(16, 16, 2), expectedLen: 4 + 2*4 + 32},
+		{name: "2 bits per pixel", grid: testGrid(16, 16, 3), expectedLen: 4 + 3*4 + 64},
+		{name: "4 bits per pixel", grid: testGrid(16, 16, 16), expectedLen: 4 + 16*4 + 128},
+		{name: "8 bits per pixel", grid: testGrid(16, 16, 17), expectedLen: 4 + 17*4 + 256},
+		{name: "odd size", grid: testGrid(3, 5, 4), expectedLen: 4 + 4*4 + 4},
+		{name: "full palette", grid: testGrid(64, 64, 256), expectedLen: 4 + 256*4 + 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			data, err := tt.grid.MarshalBinary()
+			require.NoError(t, err)
+
+			var decoded Grid
+			err = decoded.UnmarshalBinary(data)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Len(t, data, tt.expectedLen)
+			assert.Equal(t, tt.grid, decoded)
+		})
+	}
+}
+
+func TestGrid_UnmarshalBinaryErrors(t *testing.T) {
+	valid, err := testGrid(4, 4, 2).MarshalBinary()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "unknown version", data: append([]byte{9}, valid[1:]...)},
+		{name: "truncated palette", data: valid[:6]},
+		{name: "truncated pixels", data: valid[:len(valid)-1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g Grid
+			assert.ErrorIs(t, g.UnmarshalBinary(tt.data), ErrGridInvalid)
+		})
+	}
+}
+
+func TestGrid_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		grid          Grid
+		expectedError bool
+	}{
+		{name: "valid grid", grid: testGrid(16, 16, 3)},
+		{name: "max size", grid: testGrid(64, 64, 2)},
+		{name: "too wide", grid: testGrid(65, 16, 2), expectedError: true},
+		{name: "zero height", grid: Grid{Width: 1, Palette: []color.NRGBA{{}}}, expectedError: true},
+		{name: "empty palette", grid: Grid{Width: 1, Height: 1, Pixels: []uint8{0}}, expectedError: true},
+		{
+			name:          "pixel count mismatch",
+			grid:          Grid{Width: 2, Height: 2, Palette: []color.NRGBA{{}}, Pixels: []uint8{0}},
+			expectedError: true,
+		},
+		{
+			name:          "pixel outside palette",
+			grid:          Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{}}, Pixels: []uint8{1}},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.grid.Validate(DefaultImageLimits)
+			if tt.expectedError {
+				assert.ErrorIs(t, err, ErrGridInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGridFromImage(t *testing.T) {
+	// Arrange
+	data := encodeCells(t, 16, 16, 10, checkerboard)
+	img, err := DecodeImage(data, DefaultImageLimits)
+	require.NoError(t, err)
+
+	// Act
+	grid, err := GridFromImage(img, 10)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 16, grid.Width)
+	assert.Equal(t, 16, grid.Height)
+	assert.Equal(t, []color.NRGBA{{34, 139, 34, 255}, {}}, grid.Palette)
+	assert.Equal(t, uint8(0), grid.Pixels[0])
+	assert.Equal(t, uint8(1), grid.Pixels[1])
+	assert.Equal(t, uint8(1), grid.Pixels[16])
+
+	// Отрисовка сетки в исходном масштабе дает то же изображение
+	raw, err := grid.PNG(10)
+	require.NoError(t, err)
+	rendered, err := png.Decode(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, img.Bounds(), rendered.Bounds())
+	for _, p := range [][2]int{{0, 0}, {15, 3}, {159, 159}, {10, 0}} {
+		assert.Equal(t,
+			color.NRGBAModel.Convert(img.At(p[0], p[1])),
+			color.NRGBAModel.Convert(rendered.At(p[0], p[1])),
+		)
+	}
+
+	// И эта отрисовка снова проходит проверку изображения
+	_, err = DecodeImage(base64.StdEncoding.EncodeToString(raw), DefaultImageLimits)
+	assert.NoError(t, err)
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      color.NRGBA
+		expectedError bool
+	}{
+		{input: "#228b22", expected: color.NRGBA{34, 139, 34, 255}},
+		{input: "#228B2280", expected: color.NRGBA{34, 139, 34, 128}},
+		{input: "transparent", expected: color.NRGBA{}},
+		{input: "228b22", expectedError: true},
+		{input: "#fff", expectedError: true},
+		{input: "#zzzzzz", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			c, err := ParseColor(tt.input)
+			if tt.expectedError {
+				assert.ErrorIs(t, err, ErrGridInvalid)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, c)
+		})
+	}
+}
+
+func TestFormatColor(t *testing.T) {
+	assert.Equal(t, "#228b22", FormatColor(color.NRGBA{34, 139, 34, 255}))
+	assert.Equal(t, "#00000000", FormatColor(color.NRGBA{}))
+}
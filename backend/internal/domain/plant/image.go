@@ -0,0 +1,123 @@
+package plant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Ошибки проверки изображения растения.
+// Use case оборачивает их подробностями, поэтому сравнивать нужно через errors.Is.
+var (
+	// ErrImageEncoding - данные не являются корректной base64-строкой.
+	ErrImageEncoding = cerror.New(cerror.Invalid, "image data is not valid base64")
+	// ErrImageTooLarge - декодированное изображение превышает допустимый размер в байтах.
+	ErrImageTooLarge = cerror.New(cerror.TooLarge, "image data is too large")
+	// ErrImageFormat - данные не являются корректным PNG.
+	ErrImageFormat = cerror.New(cerror.Unprocessable, "image is not a valid PNG")
+	// ErrImageDimensions - ширина или высота изображения вне допустимых границ.
+	ErrImageDimensions = cerror.New(cerror.Unprocessable, "image dimensions are out of bounds")
+	// ErrImageGrid - изображение не выровнено по пиксельной сетке редактора.
+	ErrImageGrid = cerror.New(cerror.Unprocessable, "image is not aligned to the pixel grid")
+)
+
+// pngSignature - первые 8 байт любого PNG-файла.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ImageLimits описывает ограничения, которым должно соответствовать изображение растения.
+type ImageLimits struct {
+	MaxBytes  int // Максимальный размер декодированного PNG в байтах
+	MaxWidth  int // Максимальная ширина в пикселях
+	MaxHeight int // Максимальная высота в пикселях
+	CellSize  int // Размер одной клетки сетки в пикселях (PIXEL_SCALE на фронтенде)
+}
+
+// DefaultImageLimits - ограничения по умолчанию.
+// Фронтенд рисует сетку 16x16 клеток по 10px, редактор допускает до 64x64 клеток.
+var DefaultImageLimits = ImageLimits{
+	MaxBytes:  256 * 1024,
+	MaxWidth:  640,
+	MaxHeight: 640,
+	CellSize:  10,
+}
+
+// DecodeImage декодирует base64-строку с PNG и проверяет ее на соответствие ограничениям.
+// Возвращает декодированное изображение или одну из ошибок ErrImage*.
+func DecodeImage(data string, limits ImageLimits) (image.Image, error) {
+	// Отсекаем заведомо слишком большие данные еще до декодирования base64.
+	if base64.StdEncoding.DecodedLen(len(data)) > limits.MaxBytes+2 {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrImageTooLarge, limits.MaxBytes)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageEncoding, err)
+	}
+	if len(raw) > limits.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrImageTooLarge, len(raw), limits.MaxBytes)
+	}
+	if !bytes.HasPrefix(raw, pngSignature) {
+		return nil, fmt.Errorf("%w: missing PNG signature", ErrImageFormat)
+	}
+
+	// Сначала читаем только заголовок, чтобы не распаковывать огромные изображения.
+	cfg, err := png.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageFormat, err)
+	}
+	if err := checkDimensions(cfg.Width, cfg.Height, limits); err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageFormat, err)
+	}
+	if err := checkCells(img, limits.CellSize); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// checkDimensions проверяет размеры изображения и их кратность размеру клетки.
+func checkDimensions(width, height int, limits ImageLimits) error {
+	if width <= 0 || height <= 0 || width > limits.MaxWidth || height > limits.MaxHeight {
+		return fmt.Errorf("%w: got %dx%d, max is %dx%d",
+			ErrImageDimensions, width, height, limits.MaxWidth, limits.MaxHeight)
+	}
+	if limits.CellSize > 1 && (width%limits.CellSize != 0 || height%limits.CellSize != 0) {
+		return fmt.Errorf("%w: %dx%d is not a multiple of %dpx cells",
+			ErrImageGrid, width, height, limits.CellSize)
+	}
+	return nil
+}
+
+// checkCells проверяет, что каждая клетка сетки залита одним цветом.
+func checkCells(img image.Image, cellSize int) error {
+	if cellSize <= 1 {
+		return nil
+	}
+
+	bounds := img.Bounds()
+	for cy := bounds.Min.Y; cy < bounds.Max.Y; cy += cellSize {
+		for cx := bounds.Min.X; cx < bounds.Max.X; cx += cellSize {
+			want := img.At(cx, cy)
+			wr, wg, wb, wa := want.RGBA()
+			for y := cy; y < cy+cellSize; y++ {
+				for x := cx; x < cx+cellSize; x++ {
+					r, g, b, a := img.At(x, y).RGBA()
+					if r != wr || g != wg || b != wb || a != wa {
+						return fmt.Errorf("%w: cell at (%d, %d) is not uniform",
+							ErrImageGrid, (cx-bounds.Min.X)/cellSize, (cy-bounds.Min.Y)/cellSize)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package plant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// encodeCells рисует PNG из клеток cellSize x cellSize и возвращает его в base64.
+func encodeCells(t *testing.T, cols, rows, cellSize int, colorAt func(cx, cy int) color.RGBA) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, cols*cellSize, rows*cellSize))
+	for y := 0; y < rows*cellSize; y++ {
+		for x := 0; x < cols*cellSize; x++ {
+			img.SetRGBA(x, y, colorAt(x/cellSize, y/cellSize))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func checkerboard(cx, cy int) color.RGBA {
+	if (cx+cy)%2 == 0 {
+		return color.RGBA{34, 139, 34, 255}
+	}
+	return color.RGBA{0, 0, 0, 0}
+}
+
+func TestDecodeImage(t *testing.T) {
+	limits := DefaultImageLimits
+
+	nonUniform := func(t *testing.T) string {
+		img := image.NewRGBA(image.Rect(0, 0, 160, 160))
+		img.SetRGBA(5, 5, color.RGBA{255, 0, 0, 255})
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("failed to encode png: %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	tests := []struct {
+		name          string
+		data          func(t *testing.T) string
+		limits        ImageLimits
+		expectedError error
+	}{
+		{
+			name: "valid 16x16 grid at 10px",
+			data: func(t *testing.T) string {
+				return encodeCells(t, 16, 16, 10, checkerboard)
+			},
+			limits: limits,
+		},
+		{
+			name:          "not base64",
+			data:          func(t *testing.T) string { return "not base64 at all!" },
+			limits:        limits,
+			expectedError: ErrImageEncoding,
+		},
+		{
+			name: "not a png",
+			data: func(t *testing.T) string {
+				return base64.StdEncoding.EncodeToString([]byte("GIF89a definitely not a png"))
+			},
+			limits:        limits,
+			expectedError: ErrImageFormat,
+		},
+		{
+			name: "truncated png",
+			data: func(t *testing.T) string {
+				raw, _ := base64.StdEncoding.DecodeString(encodeCells(t, 16, 16, 10, checkerboard))
+				return base64.StdEncoding.EncodeToString(raw[:40])
+			},
+			limits:        limits,
+			expectedError: ErrImageFormat,
+		},
+		{
+			name: "too many bytes",
+			data: func(t *testing.T) string {
+				return base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", limits.MaxBytes+1)))
+			},
+			limits:        limits,
+			expectedError: ErrImageTooLarge,
+		},
+		{
+			name: "too wide",
+			data: func(t *testing.T) string {
+				return encodeCells(t, 65, 16, 10, checkerboard)
+			},
+			limits:        limits,
+			expectedError: ErrImageDimensions,
+		},
+		{
+			name: "not a multiple of cell size",
+			data: func(t *testing.T) string {
+				return encodeCells(t, 8, 8, 1, checkerboard)
+			},
+			limits:        limits,
+			expectedError: ErrImageGrid,
+		},
+		{
+			name:          "cell with several colors",
+			data:          nonUniform,
+			limits:        limits,
+			expectedError: ErrImageGrid,
+		},
+		{
+			name: "cell size 1 accepts any aligned image",
+			data: func(t *testing.T) string {
+				return encodeCells(t, 8, 8, 1, checkerboard)
+			},
+			limits: ImageLimits{MaxBytes: 1024, MaxWidth: 8, MaxHeight: 8, CellSize: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			img, err := DecodeImage(tt.data(t), tt.limits)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, img)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, img)
+			}
+		})
+	}
+}
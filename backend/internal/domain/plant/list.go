@@ -0,0 +1,22 @@
+package plant
+
+import "time"
+
+// Cursor - позиция в списке растений, отсортированном по (created_at, id) по убыванию.
+// Следующая страница начинается с растений, которые строго "старше" курсора.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// ListFilter - параметры выборки страницы растений.
+// Нулевые значения полей означают отсутствие соответствующего фильтра.
+type ListFilter struct {
+	Author      string    // Точное совпадение имени автора
+	UserID      int       // Растения пользователя
+	CreatedFrom time.Time // Нижняя граница created_at, включительно
+	CreatedTo   time.Time // Верхняя граница created_at, не включительно
+	After       *Cursor   // Курсор предыдущей страницы
+	Limit       int       // Максимальное количество растений
+	Status      Status    // Статус растений; пустой статус означает StatusVisible
+}
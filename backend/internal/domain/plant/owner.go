@@ -0,0 +1,60 @@
+package plant
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrForbidden - токен владельца не подходит к растению.
+var ErrForbidden = cerror.New(cerror.Forbidden, "ownership token does not match")
+
+// ErrEmptyUpdate - в запросе на изменение растения нет ни одного поля.
+var ErrEmptyUpdate = cerror.New(cerror.Invalid, "nothing to update")
+
+// OwnerActor - имя, под которым в журнал модерации попадает удаление растения его автором.
+const OwnerActor = "owner"
+
+// ownerTokenBytes - длина токена владельца. 256 бит случайности не подобрать перебором,
+// поэтому для хранения достаточно быстрого SHA-256, медленный хэш паролей не нужен.
+const ownerTokenBytes = 32
+
+// NewOwnerToken создает секретный токен владельца растения.
+// Токен отдается автору один раз при создании, в базе хранится только HashOwnerToken(token).
+func NewOwnerToken() (string, error) {
+	raw := make([]byte, ownerTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("NewOwnerToken - rand.Read: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashOwnerToken возвращает хэш токена владельца для хранения в базе.
+func HashOwnerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsOwner проверяет токен владельца за постоянное время.
+// У растений, созданных до появления токенов, хэша нет, и владельцем не считается никто.
+func (p Plant) IsOwner(token string) bool {
+	if p.OwnerTokenHash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(p.OwnerTokenHash), []byte(HashOwnerToken(token))) == 1
+}
+
+// PlantUpdate - изменения, которые автор может внести в свое растение. Nil-поля не меняются.
+type PlantUpdate struct {
+	Author *string
+	Grid   *Grid
+	// Fingerprint - отпечаток новой сетки; сохраняется вместе с Grid.
+	Fingerprint Fingerprint
+	// Status - новый статус, если новое изображение похоже на другое растение; пустой не меняется.
+	Status Status
+}
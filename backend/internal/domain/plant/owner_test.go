@@ -0,0 +1,32 @@
+package plant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOwnerToken(t *testing.T) {
+	first, err := NewOwnerToken()
+	require.NoError(t, err)
+	second, err := NewOwnerToken()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, first, HashOwnerToken(first))
+	assert.Equal(t, HashOwnerToken(first), HashOwnerToken(first))
+}
+
+func TestPlant_IsOwner(t *testing.T) {
+	token, err := NewOwnerToken()
+	require.NoError(t, err)
+	plant := Plant{ID: 1, OwnerTokenHash: HashOwnerToken(token)}
+
+	assert.True(t, plant.IsOwner(token))
+	assert.False(t, plant.IsOwner(token+"x"))
+	assert.False(t, plant.IsOwner(""))
+	assert.False(t, Plant{ID: 2}.IsOwner(token), "legacy plants have no owner")
+	assert.False(t, Plant{ID: 2}.IsOwner(""))
+}
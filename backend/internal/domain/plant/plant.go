@@ -1,11 +1,111 @@
 package plant
 
-import "time"
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"time"
 
-// Plant представляет цифровое растение в лесу
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrNotFound - растение с запрошенным идентификатором не существует.
+var ErrNotFound = cerror.New(cerror.NotFound, "plant not found")
+
+// MaxRenderSide - максимальная сторона изображения, которое сервер готов отрисовать при масштабировании.
+const MaxRenderSide = 4096
+
+// Plant представляет цифровое растение в лесу.
+// Новые растения хранятся в виде пиксельной сетки Grid,
+// у растений, созданных до ее появления, есть только ImageData.
 type Plant struct {
 	ID        int
 	Author    string
 	ImageData string
-	CreatedAt time.Time
+	Grid      *Grid
+	Status    Status
+	// ImageVersion увеличивается при каждой замене изображения и попадает в его URL,
+	// чтобы навсегда закешированная старая картинка не показывалась вместо новой.
+	ImageVersion int
+	// OwnerTokenHash - хэш токена владельца; пуст у растений, созданных до появления токенов.
+	OwnerTokenHash string
+	// OwnerToken - сам токен владельца. Заполняется только у только что созданного растения
+	// и нигде не сохраняется.
+	OwnerToken string
+	// UserID - пользователь, посадивший растение; 0 у анонимных растений.
+	UserID int
+	// Fingerprint - отпечаток сетки для поиска дубликатов; пуст, пока не вычислен (см. Fingerprint.IsZero).
+	Fingerprint Fingerprint
+	CreatedAt   time.Time
+}
+
+// ImageBase64 возвращает изображение растения в виде base64 PNG.
+// Если растение хранится как сетка, PNG рисуется в масштабе редактора (DefaultImageLimits.CellSize).
+func (p Plant) ImageBase64() (string, error) {
+	if p.ImageData != "" || p.Grid == nil {
+		return p.ImageData, nil
+	}
+
+	raw, err := p.Grid.PNG(DefaultImageLimits.CellSize)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// PNG возвращает изображение растения в формате PNG, увеличенное в scale раз (nearest neighbour).
+// Для сетки масштаб 1 означает одну точку на клетку, для старых растений - исходный размер PNG.
+// Если итоговое изображение больше MaxRenderSide, возвращается ErrImageDimensions.
+func (p Plant) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+
+	if p.Grid != nil {
+		if p.Grid.Width*scale > MaxRenderSide || p.Grid.Height*scale > MaxRenderSide {
+			return nil, fmt.Errorf("%w: scale %d is too large", ErrImageDimensions, scale)
+		}
+		return p.Grid.PNG(scale)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(p.ImageData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageEncoding, err)
+	}
+	if scale == 1 {
+		return raw, nil
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageFormat, err)
+	}
+	if cfg.Width*scale > MaxRenderSide || cfg.Height*scale > MaxRenderSide {
+		return nil, fmt.Errorf("%w: scale %d is too large", ErrImageDimensions, scale)
+	}
+
+	src, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImageFormat, err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, upscale(src, scale)); err != nil {
+		return nil, fmt.Errorf("Plant - PNG - png.Encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// upscale увеличивает изображение в scale раз методом ближайшего соседа.
+func upscale(src image.Image, scale int) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+	for y := 0; y < dst.Bounds().Dy(); y++ {
+		for x := 0; x < dst.Bounds().Dx(); x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x/scale, bounds.Min.Y+y/scale))
+		}
+	}
+	return dst
 }
@@ -0,0 +1,13 @@
+package plant
+
+// RandomFilter - параметры выборки случайных растений.
+//
+// Без Seed каждый запрос возвращает новую случайную выборку.
+// С Seed растения выдаются в одном и том же перемешанном порядке, который можно обходить
+// страницами: AfterID - последнее растение предыдущей страницы этого порядка.
+type RandomFilter struct {
+	Count   int    // Максимальное количество растений
+	Seed    *int64 // Зерно детерминированного порядка
+	AfterID int    // Позиция в детерминированном порядке, 0 - с начала
+	Exclude []int  // Растения, которые клиент уже видел
+}
@@ -0,0 +1,59 @@
+package plant
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrAlreadyReported - этот посетитель уже пожаловался на растение, и жалоба еще не рассмотрена.
+var ErrAlreadyReported = cerror.New(cerror.Conflict, "plant already reported by this reporter")
+
+// ErrInvalidReportReason - неизвестная причина жалобы.
+var ErrInvalidReportReason = cerror.New(cerror.Invalid, "unknown report reason").WithField("reason", "field 'reason' is not valid")
+
+// ReportsActor - имя, под которым в журнал модерации попадает автоматическое скрытие по жалобам.
+const ReportsActor = "system:reports"
+
+// ReportReason - причина жалобы посетителя на растение.
+type ReportReason string
+
+const (
+	ReasonSpam      ReportReason = "spam"
+	ReasonOffensive ReportReason = "offensive"
+	ReasonSexual    ReportReason = "sexual"
+	ReasonViolence  ReportReason = "violence"
+	ReasonCopyright ReportReason = "copyright"
+	ReasonOther     ReportReason = "other"
+)
+
+// ParseReportReason проверяет причину жалобы.
+func ParseReportReason(s string) (ReportReason, error) {
+	switch reason := ReportReason(s); reason {
+	case ReasonSpam, ReasonOffensive, ReasonSexual, ReasonViolence, ReasonCopyright, ReasonOther:
+		return reason, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidReportReason, s)
+	}
+}
+
+// Report - жалоба посетителя на растение.
+// Вместо адреса или сессии посетителя хранится только их хэш: его достаточно, чтобы не считать повторные жалобы.
+type Report struct {
+	ID           int
+	PlantID      int
+	Reason       ReportReason
+	ReporterHash string
+	CreatedAt    time.Time
+}
+
+// ReportSummary - открытые жалобы на одно растение в очереди модерации.
+type ReportSummary struct {
+	PlantID     int
+	PlantStatus Status
+	Count       int
+	Reasons     map[ReportReason]int
+	FirstAt     time.Time
+	LastAt      time.Time
+}
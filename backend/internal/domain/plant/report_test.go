@@ -0,0 +1,20 @@
+package plant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReportReason(t *testing.T) {
+	for _, reason := range []ReportReason{ReasonSpam, ReasonOffensive, ReasonSexual, ReasonViolence, ReasonCopyright, ReasonOther} {
+		parsed, err := ParseReportReason(string(reason))
+		assert.NoError(t, err)
+		assert.Equal(t, reason, parsed)
+	}
+
+	for _, s := range []string{"", "SPAM", "boring"} {
+		_, err := ParseReportReason(s)
+		assert.ErrorIs(t, err, ErrInvalidReportReason)
+	}
+}
@@ -0,0 +1,89 @@
+package plant
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrInvalidTransition - действие модерации неприменимо к растению в его текущем статусе.
+var ErrInvalidTransition = cerror.New(cerror.Conflict, "moderation action is not allowed in current status")
+
+// ErrInvalidStatus - неизвестный статус растения.
+var ErrInvalidStatus = cerror.New(cerror.Invalid, "unknown plant status")
+
+// Status - состояние растения в жизненном цикле модерации.
+// Публичным API видны только растения в статусе StatusVisible.
+type Status string
+
+const (
+	// StatusVisible - растение показывается в лесу.
+	StatusVisible Status = "visible"
+	// StatusPending - растение ждет решения модератора и пока не показывается.
+	StatusPending Status = "pending"
+	// StatusHidden - растение скрыто модератором, но может быть восстановлено.
+	StatusHidden Status = "hidden"
+	// StatusDeleted - растение удалено (soft-delete); запись остается, пока ее не удалят окончательно.
+	StatusDeleted Status = "deleted"
+)
+
+// ParseStatus проверяет название статуса.
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(s); status {
+	case StatusVisible, StatusPending, StatusHidden, StatusDeleted:
+		return status, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, s)
+	}
+}
+
+// ModerationAction - действие модератора над растением.
+type ModerationAction string
+
+const (
+	// ActionHide скрывает видимое или ожидающее проверки растение.
+	ActionHide ModerationAction = "hide"
+	// ActionRestore возвращает растение в лес.
+	ActionRestore ModerationAction = "restore"
+	// ActionDelete помечает растение удаленным.
+	ActionDelete ModerationAction = "delete"
+	// ActionPurge удаляет растение из базы окончательно. Запись в журнале модерации сохраняется.
+	ActionPurge ModerationAction = "purge"
+)
+
+// NextStatus возвращает статус, в который действие переводит растение.
+// Для ActionPurge возвращается StatusDeleted: после него растения просто нет.
+// Если действие неприменимо, возвращается ErrInvalidTransition.
+func NextStatus(current Status, action ModerationAction) (Status, error) {
+	var allowed bool
+	var next Status
+
+	switch action {
+	case ActionHide:
+		next, allowed = StatusHidden, current == StatusVisible || current == StatusPending
+	case ActionRestore:
+		next, allowed = StatusVisible, current != StatusVisible
+	case ActionDelete:
+		next, allowed = StatusDeleted, current != StatusDeleted
+	case ActionPurge:
+		next, allowed = StatusDeleted, true
+	}
+
+	if !allowed {
+		return "", fmt.Errorf("%w: cannot %s a plant in status %q", ErrInvalidTransition, action, current)
+	}
+	return next, nil
+}
+
+// ModerationEvent - запись журнала модерации: кто, когда и что сделал с растением.
+type ModerationEvent struct {
+	ID         int
+	PlantID    int
+	Action     ModerationAction
+	Actor      string
+	Reason     string
+	FromStatus Status
+	ToStatus   Status
+	CreatedAt  time.Time
+}
@@ -0,0 +1,58 @@
+package plant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextStatus(t *testing.T) {
+	tests := []struct {
+		current  Status
+		action   ModerationAction
+		expected Status
+		allowed  bool
+	}{
+		{current: StatusVisible, action: ActionHide, expected: StatusHidden, allowed: true},
+		{current: StatusPending, action: ActionHide, expected: StatusHidden, allowed: true},
+		{current: StatusHidden, action: ActionHide},
+		{current: StatusDeleted, action: ActionHide},
+
+		{current: StatusHidden, action: ActionRestore, expected: StatusVisible, allowed: true},
+		{current: StatusPending, action: ActionRestore, expected: StatusVisible, allowed: true},
+		{current: StatusDeleted, action: ActionRestore, expected: StatusVisible, allowed: true},
+		{current: StatusVisible, action: ActionRestore},
+
+		{current: StatusVisible, action: ActionDelete, expected: StatusDeleted, allowed: true},
+		{current: StatusHidden, action: ActionDelete, expected: StatusDeleted, allowed: true},
+		{current: StatusDeleted, action: ActionDelete},
+
+		{current: StatusVisible, action: ActionPurge, expected: StatusDeleted, allowed: true},
+		{current: StatusDeleted, action: ActionPurge, expected: StatusDeleted, allowed: true},
+
+		{current: StatusVisible, action: "water"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action)+" "+string(tt.current), func(t *testing.T) {
+			next, err := NextStatus(tt.current, tt.action)
+			if !tt.allowed {
+				assert.ErrorIs(t, err, ErrInvalidTransition)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, next)
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, s := range []Status{StatusVisible, StatusPending, StatusHidden, StatusDeleted} {
+		parsed, err := ParseStatus(string(s))
+		assert.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	_, err := ParseStatus("archived")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrIdentityNotFound - внешняя учетная запись еще не привязана ни к одному пользователю.
+var ErrIdentityNotFound = cerror.New(cerror.NotFound, "identity not found")
+
+// ErrIdentityLinked - внешняя учетная запись уже привязана к другому пользователю.
+var ErrIdentityLinked = cerror.New(cerror.Conflict, "identity is already linked to another user")
+
+// ErrLoginStateNotFound - вход через провайдера не начинался, уже завершен или истек.
+var ErrLoginStateNotFound = cerror.New(cerror.Invalid, "login state not found")
+
+// LoginStateTTL - сколько времени есть у пользователя, чтобы вернуться от провайдера.
+const LoginStateTTL = 10 * time.Minute
+
+// Identity - учетная запись у внешнего провайдера OpenID Connect, привязанная к пользователю.
+// Учетная запись определяется парой (Issuer, Subject): по спецификации sub уникален и неизменен
+// в пределах провайдера. Email для привязки не используется - его может сменить или подделать сам провайдер.
+type Identity struct {
+	Issuer    string
+	Subject   string
+	UserID    int
+	CreatedAt time.Time
+}
+
+// LoginState - незавершенный вход через провайдера: все, что нужно проверить, когда браузер вернется.
+// Состояние хранится в базе, чтобы вход работал, даже если callback попадет на другую реплику.
+type LoginState struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	// LinkUserID - пользователь, который уже вошел и привязывает еще одну учетную запись; 0 для обычного входа.
+	LinkUserID int
+	ExpiresAt  time.Time
+}
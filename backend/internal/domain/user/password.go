@@ -0,0 +1,86 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Параметры argon2id по рекомендации OWASP: 19 МиБ памяти, 2 прохода, 1 поток.
+// Параметры записываются в сам хэш, поэтому их можно усилить, не ломая старые пароли.
+const (
+	argonMemory  = 19 * 1024
+	argonTime    = 2
+	argonThreads = 1
+	argonSaltLen = 16
+	argonKeyLen  = 32
+)
+
+// HashPassword возвращает хэш argon2id в формате PHC:
+// $argon2id$v=19$m=19456,t=2,p=1$<соль>$<хэш>.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("HashPassword - rand.Read: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// CheckPassword сравнивает пароль с хэшем из HashPassword за постоянное время.
+// Поврежденный хэш считается несовпадением.
+func CheckPassword(hash, password string) bool {
+	var (
+		memory, passes uint32
+		threads        uint8
+		version        int
+	)
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &passes, &threads); err != nil {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, passes, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, actual) == 1
+}
+
+// dummyHash - хэш случайного пароля. Проверка пароля для несуществующего пользователя
+// выполняется по нему, чтобы по времени ответа нельзя было узнать, занято ли имя.
+var dummyHash = sync.OnceValue(func() string {
+	hash, err := HashPassword("dummy password for timing equalization")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+// CheckNoPassword тратит на проверку столько же времени, сколько CheckPassword, и всегда возвращает false.
+func CheckNoPassword(password string) bool {
+	CheckPassword(dummyHash(), password)
+	return false
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestHashPassword(t *testing.T) {
+	// Arrange & Act
+	first, err := HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	second, err := HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(first, "$argon2id$v=19$m=19456,t=2,p=1$"))
+	assert.NotEqual(t, first, second, "salt must be random")
+	assert.True(t, CheckPassword(first, "correct horse battery staple"))
+	assert.True(t, CheckPassword(second, "correct horse battery staple"))
+	assert.False(t, CheckPassword(first, "correct horse battery stapler"))
+	assert.False(t, CheckPassword(first, ""))
+}
+
+func TestCheckPassword_MalformedHash(t *testing.T) {
+	hash, err := HashPassword("secret")
+	require.NoError(t, err)
+	parts := strings.Split(hash, "$")
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "empty", hash: ""},
+		{name: "bcrypt", hash: "$2a$10$abcdefghijklmnopqrstuu"},
+		{name: "wrong version", hash: strings.Replace(hash, "v=19", "v=16", 1)},
+		{name: "broken params", hash: strings.Replace(hash, "m=19456", "m=x", 1)},
+		{name: "broken salt", hash: strings.Join([]string{"", parts[1], parts[2], parts[3], "!!!", parts[5]}, "$")},
+		{name: "empty key", hash: strings.Join([]string{"", parts[1], parts[2], parts[3], parts[4], ""}, "$")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, CheckPassword(tt.hash, "secret"))
+		})
+	}
+}
+
+func TestCheckPassword_StoredParameters(t *testing.T) {
+	// Хэш со старыми, более слабыми параметрами продолжает проверяться после их усиления
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte("secret"), salt, 1, 8*1024, 1, 32)
+	hash := "$argon2id$v=19$m=8192,t=1,p=1$" +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(key)
+
+	assert.True(t, CheckPassword(hash, "secret"))
+	assert.False(t, CheckPassword(hash, "wrong"))
+}
+
+func TestCheckNoPassword(t *testing.T) {
+	assert.False(t, CheckNoPassword("secret"))
+	assert.False(t, CheckNoPassword(""))
+}
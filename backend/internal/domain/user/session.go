@@ -0,0 +1,79 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrSessionNotFound - сессии нет, она истекла или пользователь вышел.
+var ErrSessionNotFound = cerror.New(cerror.NotFound, "session not found")
+
+// DefaultSessionTTL - сколько живет сессия после входа.
+const DefaultSessionTTL = 14 * 24 * time.Hour
+
+// sessionTokenBytes - длина токена сессии и CSRF-токена.
+const sessionTokenBytes = 32
+
+// Session - серверная сессия пользователя.
+// Токен сессии отдается только в cookie, а в базе хранится его хэш TokenHash,
+// так что утечка таблицы sessions не позволяет войти под чужим именем.
+type Session struct {
+	TokenHash string
+	UserID    int
+	// CSRFToken привязан к сессии: небезопасные запросы с cookie сессии должны повторить его
+	// в заголовке X-CSRF-Token, а прочитать его может только страница нашего источника.
+	CSRFToken string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// NewSession создает сессию пользователя и возвращает ее вместе с токеном для cookie.
+func NewSession(userID int, now time.Time, ttl time.Duration) (Session, string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return Session{}, "", fmt.Errorf("NewSession - token: %w", err)
+	}
+	csrf, err := randomToken()
+	if err != nil {
+		return Session{}, "", fmt.Errorf("NewSession - csrf: %w", err)
+	}
+
+	return Session{
+		TokenHash: HashSessionToken(token),
+		UserID:    userID,
+		CSRFToken: csrf,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, token, nil
+}
+
+// HashSessionToken возвращает хэш токена сессии для хранения и поиска в базе.
+// Токен случайный и длинный, поэтому достаточно SHA-256.
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckCSRF сравнивает CSRF-токен из запроса с токеном сессии за постоянное время.
+func (s Session) CheckCSRF(token string) bool {
+	if s.CSRFToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(s.CSRFToken), []byte(token)) == 1
+}
+
+// randomToken возвращает 256 случайных бит в base64url.
+func randomToken() (string, error) {
+	raw := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSession(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// Act
+	session, token, err := NewSession(7, now, time.Hour)
+	require.NoError(t, err)
+	other, otherToken, err := NewSession(7, now, time.Hour)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 7, session.UserID)
+	assert.Equal(t, now.Add(time.Hour), session.ExpiresAt)
+	assert.Len(t, token, 43)
+	assert.Equal(t, HashSessionToken(token), session.TokenHash)
+	assert.NotEqual(t, token, session.TokenHash)
+	assert.NotEqual(t, token, otherToken)
+	assert.NotEqual(t, session.CSRFToken, other.CSRFToken)
+	assert.NotEqual(t, token, session.CSRFToken)
+}
+
+func TestSession_CheckCSRF(t *testing.T) {
+	session, _, err := NewSession(1, time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	assert.True(t, session.CheckCSRF(session.CSRFToken))
+	assert.False(t, session.CheckCSRF(session.CSRFToken+"x"))
+	assert.False(t, session.CheckCSRF(""))
+	assert.False(t, Session{}.CheckCSRF(""))
+}
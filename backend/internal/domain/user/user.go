@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrNotFound - пользователь не существует.
+var ErrNotFound = cerror.New(cerror.NotFound, "user not found")
+
+// ErrUsernameTaken - имя уже занято другим пользователем (без учета регистра).
+var ErrUsernameTaken = cerror.New(cerror.Conflict, "username is already taken")
+
+// ErrInvalidUsername - имя не подходит под UsernamePattern.
+var ErrInvalidUsername = cerror.New(cerror.Invalid, "invalid username").
+	WithField("username", "field 'username' may contain only latin letters, digits, '.', '-' and '_' (3-32 characters)")
+
+// ErrInvalidCredentials - неверное имя или пароль. Какое именно из двух, намеренно не сообщается.
+var ErrInvalidCredentials = cerror.New(cerror.Unauthorized, "invalid username or password")
+
+// usernamePattern - латинские буквы, цифры, точка, дефис и подчеркивание, от 3 до 32 символов.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{3,32}$`)
+
+// User - зарегистрированный пользователь. Растения могут принадлежать пользователю,
+// но анонимные растения по-прежнему разрешены.
+type User struct {
+	ID           int
+	Username     string
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+// NormalizeUsername обрезает пробелы по краям и проверяет имя.
+// Регистр сохраняется для отображения, уникальность проверяется базой без учета регистра.
+func NormalizeUsername(username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if !usernamePattern.MatchString(username) {
+		return "", ErrInvalidUsername
+	}
+	return username, nil
+}
+
+type userKey struct{}
+
+// WithUser сохраняет в контексте пользователя, от имени которого выполняется запрос.
+func WithUser(ctx context.Context, u User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// FromContext возвращает пользователя, сохраненного WithUser. Для анонимных запросов ok равно false.
+func FromContext(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
@@ -0,0 +1,51 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "plain", input: "alice", expected: "alice"},
+		{name: "keeps case", input: "Alice_01", expected: "Alice_01"},
+		{name: "trims spaces", input: "  bob.smith ", expected: "bob.smith"},
+		{name: "too short", input: "ab", wantErr: true},
+		{name: "too long", input: "abcdefghijklmnopqrstuvwxyz0123456", wantErr: true},
+		{name: "inner space", input: "bob smith", wantErr: true},
+		{name: "cyrillic", input: "алиса", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result, err := NormalizeUsername(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidUsername)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestUserContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithUser(context.Background(), User{ID: 7, Username: "alice"})
+	u, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 7, u.ID)
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"errors"
+	"sync"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// ErrBusClosed - шина уже остановлена, новые подписки не принимаются.
+var ErrBusClosed = errors.New("event bus is closed")
+
+// DefaultSubscriberBuffer - сколько событий может накопиться у подписчика, прежде чем он будет отключен.
+const DefaultSubscriberBuffer = 64
+
+// PlantBus - шина событий о новых растениях внутри процесса.
+//
+// Каждое событие рассылается всем подписчикам (fan-out). Publish никогда не блокируется:
+// у каждого подписчика свой буфер, и если подписчик не успевает его разбирать,
+// он отключается с пометкой Lagged. Медленный клиент не тормозит ни создание растений,
+// ни остальных подписчиков, а сам переподключается и дочитывает пропущенное через список растений.
+type PlantBus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	buffer int
+	closed bool
+}
+
+// NewPlantBus - конструктор для шины. buffer - размер буфера каждого подписчика.
+func NewPlantBus(buffer int) *PlantBus {
+	if buffer < 1 {
+		buffer = DefaultSubscriberBuffer
+	}
+	return &PlantBus{
+		subs:   make(map[*Subscription]struct{}),
+		buffer: buffer,
+	}
+}
+
+// Subscription - подписка на новые растения.
+// Канал C закрывается, когда подписка завершена: вызван Close, шина остановлена
+// или подписчик не успевал читать события (см. Lagged).
+type Subscription struct {
+	C <-chan domain.Plant
+
+	ch     chan domain.Plant
+	bus    *PlantBus
+	lagged bool
+}
+
+// Subscribe создает новую подписку. После Close шины возвращается ErrBusClosed.
+func (b *PlantBus) Subscribe() (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+
+	ch := make(chan domain.Plant, b.buffer)
+	sub := &Subscription{C: ch, ch: ch, bus: b}
+	b.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish рассылает растение всем подписчикам, не дожидаясь их.
+// Подписчики с переполненным буфером отключаются.
+func (b *PlantBus) Publish(plant domain.Plant) {
+	var lagging []*Subscription
+
+	// Отправка идет под read-блокировкой, а закрытие каналов - только под write-блокировкой,
+	// поэтому отправить в уже закрытый канал невозможно.
+	b.mu.RLock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- plant:
+		default:
+			lagging = append(lagging, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	if len(lagging) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	for _, sub := range lagging {
+		if _, ok := b.subs[sub]; ok {
+			sub.lagged = true
+			b.remove(sub)
+		}
+	}
+	b.mu.Unlock()
+}
+
+// Subscribers возвращает текущее количество подписчиков.
+func (b *PlantBus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close останавливает шину и завершает все подписки.
+// Вызывается при остановке сервера, чтобы долгоживущие соединения завершились сами.
+func (b *PlantBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// remove удаляет подписку и закрывает ее канал. Вызывается под write-блокировкой.
+func (b *PlantBus) remove(sub *Subscription) {
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Close отменяет подписку. Повторный вызов безопасен.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subs[s]; ok {
+		s.bus.remove(s)
+	}
+}
+
+// Lagged сообщает, была ли подписка отключена из-за того, что подписчик не успевал читать события.
+// Имеет смысл после закрытия канала C.
+func (s *Subscription) Lagged() bool {
+	s.bus.mu.RLock()
+	defer s.bus.mu.RUnlock()
+	return s.lagged
+}
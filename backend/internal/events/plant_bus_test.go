@@ -0,0 +1,105 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlantBus_FanOut(t *testing.T) {
+	// Arrange
+	bus := NewPlantBus(4)
+	first, err := bus.Subscribe()
+	require.NoError(t, err)
+	second, err := bus.Subscribe()
+	require.NoError(t, err)
+
+	// Act
+	bus.Publish(domain.Plant{ID: 1})
+	bus.Publish(domain.Plant{ID: 2})
+
+	// Assert
+	for _, sub := range []*Subscription{first, second} {
+		assert.Equal(t, 1, (<-sub.C).ID)
+		assert.Equal(t, 2, (<-sub.C).ID)
+	}
+	assert.Equal(t, 2, bus.Subscribers())
+}
+
+func TestPlantBus_SlowSubscriberIsDropped(t *testing.T) {
+	// Arrange
+	bus := NewPlantBus(2)
+	slow, err := bus.Subscribe()
+	require.NoError(t, err)
+	fast, err := bus.Subscribe()
+	require.NoError(t, err)
+
+	// Act: медленный подписчик ничего не читает, быстрый читает все
+	for i := 1; i <= 3; i++ {
+		bus.Publish(domain.Plant{ID: i})
+		assert.Equal(t, i, (<-fast.C).ID)
+	}
+
+	// Assert: два события из буфера доступны, затем канал закрыт
+	assert.Equal(t, 1, (<-slow.C).ID)
+	assert.Equal(t, 2, (<-slow.C).ID)
+	_, ok := <-slow.C
+	assert.False(t, ok)
+	assert.True(t, slow.Lagged())
+	assert.False(t, fast.Lagged())
+	assert.Equal(t, 1, bus.Subscribers())
+}
+
+func TestPlantBus_Close(t *testing.T) {
+	// Arrange
+	bus := NewPlantBus(1)
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+
+	// Act
+	bus.Close()
+
+	// Assert
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	assert.False(t, sub.Lagged())
+
+	_, err = bus.Subscribe()
+	assert.ErrorIs(t, err, ErrBusClosed)
+
+	// Публикация и отписка после остановки безопасны
+	bus.Publish(domain.Plant{ID: 1})
+	sub.Close()
+}
+
+func TestPlantBus_ConcurrentPublishAndUnsubscribe(t *testing.T) {
+	bus := NewPlantBus(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				bus.Publish(domain.Plant{ID: j})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sub, err := bus.Subscribe()
+				if err != nil {
+					return
+				}
+				sub.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Close()
+
+	assert.Equal(t, 0, bus.Subscribers())
+}
@@ -0,0 +1,117 @@
+package hashcash
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed - токен не похож на выданный сервером.
+	ErrMalformed = errors.New("malformed challenge")
+	// ErrSignature - подпись токена не сходится: его выдал не этот сервер или он изменен.
+	ErrSignature = errors.New("invalid challenge signature")
+	// ErrReplayed - решение этой задачи уже использовано.
+	ErrReplayed = errors.New("challenge already used")
+)
+
+// MaxSolutionLength - предел длины решения, чтобы проверка не хэшировала произвольно большие строки.
+const MaxSolutionLength = 64
+
+// Puzzle - задача, подписанная сервером. Клиент должен найти такое решение s,
+// что у SHA-256(token + ":" + s) не меньше Difficulty ведущих нулевых бит.
+type Puzzle struct {
+	ID         string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+// Signer выпускает и проверяет токены задач ключом HMAC. Состояние на сервере не хранится:
+// все, что нужно для проверки, лежит в самом токене.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner - конструктор для Signer. У всех реплик должен быть один и тот же ключ.
+func NewSigner(secret []byte) Signer {
+	return Signer{secret: secret}
+}
+
+// Issue выпускает задачу с новым случайным ID. Токен имеет вид id.difficulty.expires.signature.
+func (s Signer) Issue(difficulty int, expiresAt time.Time) (Puzzle, string) {
+	p := Puzzle{ID: rand.Text(), Difficulty: difficulty, ExpiresAt: expiresAt.Truncate(time.Second)}
+	payload := p.ID + "." + strconv.Itoa(p.Difficulty) + "." + strconv.FormatInt(p.ExpiresAt.Unix(), 10)
+	return p, payload + "." + s.sign(payload)
+}
+
+// Parse проверяет подпись токена и возвращает задачу. Срок действия не проверяется.
+func (s Signer) Parse(token string) (Puzzle, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] == "" {
+		return Puzzle{}, ErrMalformed
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.sign(payload))) {
+		return Puzzle{}, ErrSignature
+	}
+
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Puzzle{}, ErrMalformed
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Puzzle{}, ErrMalformed
+	}
+	return Puzzle{ID: parts[0], Difficulty: difficulty, ExpiresAt: time.Unix(expires, 0).UTC()}, nil
+}
+
+func (s Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Check сообщает, решает ли solution задачу token сложности difficulty.
+func Check(token, solution string, difficulty int) bool {
+	if solution == "" || len(solution) > MaxSolutionLength {
+		return false
+	}
+	sum := sha256.Sum256([]byte(token + ":" + solution))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+// Solve перебирает решения, пока не найдет подходящее. Так же решает задачу и клиент.
+func Solve(token string, difficulty int) string {
+	for n := uint64(0); ; n++ {
+		solution := strconv.FormatUint(n, 36)
+		if Check(token, solution, difficulty) {
+			return solution
+		}
+	}
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+// SpentStore помнит использованные задачи до истечения их срока.
+// Spend возвращает ErrReplayed, если задача id уже была использована.
+type SpentStore interface {
+	Spend(ctx context.Context, id string, expiresAt time.Time) error
+}
@@ -0,0 +1,88 @@
+package hashcash
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_IssueAndParse(t *testing.T) {
+	// Arrange
+	signer := NewSigner([]byte("secret"))
+	expiresAt := time.Date(2026, 10, 16, 12, 5, 0, 0, time.UTC)
+
+	// Act
+	issued, token := signer.Issue(12, expiresAt)
+	parsed, err := signer.Parse(token)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, issued, parsed)
+	assert.Equal(t, 12, parsed.Difficulty)
+	assert.Equal(t, expiresAt, parsed.ExpiresAt)
+	assert.NotEmpty(t, parsed.ID)
+
+	_, other := signer.Issue(12, expiresAt)
+	assert.NotEqual(t, token, other)
+}
+
+func TestSigner_Parse_Rejects(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	_, token := signer.Issue(12, time.Now())
+	_, foreign := NewSigner([]byte("other")).Issue(12, time.Now())
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name        string
+		token       string
+		expectedErr error
+	}{
+		{name: "empty", token: "", expectedErr: ErrMalformed},
+		{name: "missing part", token: strings.Join(parts[:3], "."), expectedErr: ErrMalformed},
+		{name: "foreign key", token: foreign, expectedErr: ErrSignature},
+		{
+			name:        "lowered difficulty",
+			token:       strings.Join([]string{parts[0], "1", parts[2], parts[3]}, "."),
+			expectedErr: ErrSignature,
+		},
+		{
+			name:        "extended expiry",
+			token:       strings.Join([]string{parts[0], parts[1], "99999999999", parts[3]}, "."),
+			expectedErr: ErrSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := signer.Parse(tt.token)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSolveAndCheck(t *testing.T) {
+	// Arrange
+	token := "puzzle"
+
+	// Act
+	solution := Solve(token, 12)
+
+	// Assert
+	assert.True(t, Check(token, solution, 12))
+	assert.True(t, Check(token, solution, 0))
+	assert.False(t, Check(token, "", 0))
+	assert.False(t, Check(token, strings.Repeat("a", MaxSolutionLength+1), 0))
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	assert.Equal(t, 0, leadingZeroBits([]byte{0x80}))
+	assert.Equal(t, 7, leadingZeroBits([]byte{0x01, 0xff}))
+	assert.Equal(t, 12, leadingZeroBits([]byte{0x00, 0x08}))
+	assert.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
@@ -0,0 +1,55 @@
+package hashcash
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто MemorySpentStore забывает истекшие задачи.
+const sweepInterval = time.Minute
+
+// MemorySpentStore помнит использованные задачи в памяти процесса.
+// Повтор на другой реплике он не заметит: для нескольких реплик нужно общее хранилище.
+type MemorySpentStore struct {
+	mu        sync.Mutex
+	spent     map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemorySpentStore - конструктор для MemorySpentStore.
+func NewMemorySpentStore() *MemorySpentStore {
+	return &MemorySpentStore{
+		spent: make(map[string]time.Time),
+		now:   time.Now,
+	}
+}
+
+// Spend отмечает задачу использованной.
+func (s *MemorySpentStore) Spend(ctx context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if _, ok := s.spent[id]; ok {
+		return ErrReplayed
+	}
+	s.spent[id] = expiresAt
+	return nil
+}
+
+// sweep удаляет истекшие задачи: их токены и так больше не примут.
+func (s *MemorySpentStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for id, expiresAt := range s.spent {
+		if !expiresAt.After(now) {
+			delete(s.spent, id)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package hashcash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemorySpentStore_Spend(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := NewMemorySpentStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Act & Assert: задачу можно использовать один раз
+	assert.NoError(t, store.Spend(ctx, "a", now.Add(time.Minute)))
+	assert.ErrorIs(t, store.Spend(ctx, "a", now.Add(time.Minute)), ErrReplayed)
+	assert.NoError(t, store.Spend(ctx, "b", now.Add(5*time.Minute)))
+
+	// Истекшие задачи забываются
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, store.Spend(ctx, "c", now.Add(time.Minute)))
+	assert.Len(t, store.spent, 2)
+}
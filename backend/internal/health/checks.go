@@ -0,0 +1,54 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Pinger - соединение, доступность которого проверяет Ping, например pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping проверяет, что p отвечает, и отвечает быстрее maxLatency. Нулевой maxLatency не ограничивает время ответа.
+func Ping(p Pinger, maxLatency time.Duration) Check {
+	return func(ctx context.Context) error {
+		start := time.Now()
+		if err := p.Ping(ctx); err != nil {
+			return err
+		}
+		if elapsed := time.Since(start); maxLatency > 0 && elapsed > maxLatency {
+			return fmt.Errorf("ping took %s, limit is %s", elapsed.Round(time.Millisecond), maxLatency)
+		}
+		return nil
+	}
+}
+
+// VersionReader возвращает версию последней примененной миграции (см. postgres.MigrationRepo).
+type VersionReader interface {
+	Version(ctx context.Context) (int64, error)
+}
+
+// MigrationVersion проверяет, что схема базы не отстает от версии, на которую рассчитан код.
+// Более новая схема допустима: во время выкатки первая реплика новой версии обновляет схему,
+// и старые реплики должны оставаться в работе до своей замены.
+func MigrationVersion(v VersionReader, expected int64) Check {
+	return func(ctx context.Context) error {
+		version, err := v.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if version < expected {
+			return fmt.Errorf("database schema is at version %d, expected at least %d", version, expected)
+		}
+		return nil
+	}
+}
+
+// Worker проверяет фоновый процесс по его методу Health, например postgres.PlantListener.Health.
+func Worker(health func() error) Check {
+	return func(context.Context) error {
+		return health()
+	}
+}
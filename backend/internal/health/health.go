@@ -0,0 +1,142 @@
+// Package health проверяет готовность сервиса принимать запросы: доступность базы, версию ее схемы
+// и состояние фоновых процессов. Результат отдает GET /readyz.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout - сколько ждать все проверки, если время не задано.
+const DefaultTimeout = 2 * time.Second
+
+// Status - состояние сервиса или отдельного компонента.
+type Status string
+
+const (
+	// StatusOK - компонент работает.
+	StatusOK Status = "ok"
+	// StatusFail - компонент не работает или не ответил вовремя.
+	StatusFail Status = "fail"
+	// StatusDraining - сервис останавливается и новых запросов не принимает.
+	StatusDraining Status = "draining"
+)
+
+// Check проверяет один компонент и возвращает причину, по которой он не готов.
+type Check func(ctx context.Context) error
+
+// Component - результат проверки одного компонента.
+type Component struct {
+	Status Status `json:"status"`
+	// LatencyMS - сколько длилась проверка, в миллисекундах.
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report - результат проверки готовности: StatusOK, только если готовы все компоненты.
+type Report struct {
+	Status     Status               `json:"status"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker проверяет готовность сервиса набором проверок.
+type Checker struct {
+	checks   []namedCheck
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// Option настраивает Checker.
+type Option func(*Checker)
+
+// WithCheck добавляет проверку компонента name.
+func WithCheck(name string, check Check) Option {
+	return func(c *Checker) {
+		c.checks = append(c.checks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithTimeout задает, сколько ждать все проверки. Не ответивший вовремя компонент считается неготовым.
+// Неположительное значение оставляет DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Checker) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// New - конструктор для Checker.
+func New(opts ...Option) *Checker {
+	c := &Checker{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Drain переводит сервис в состояние StatusDraining: с этого момента Ready сообщает, что сервис не готов.
+// Вызывается при остановке до server.Shutdown, чтобы балансировщик успел перестать присылать запросы.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready выполняет все проверки параллельно и собирает их результаты.
+// Во время остановки проверки не выполняются.
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Status: StatusDraining}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]Component, len(c.checks))
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, nc.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Components: make(map[string]Component, len(c.checks))}
+	for i, nc := range c.checks {
+		report.Components[nc.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run выполняет проверку и замеряет ее длительность. Проверка, не уложившаяся в ctx, считается неудачной,
+// даже если сама она ctx не соблюдает.
+func run(ctx context.Context, check Check) Component {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("no answer: %w", ctx.Err())
+	}
+
+	c := Component{Status: StatusOK, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		c.Status, c.Error = StatusFail, err.Error()
+	}
+	return c
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond) // Проверка не соблюдает ctx
+		return nil
+	}
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		drain          bool
+		expectedStatus Status
+		expectedErrors map[string]string
+	}{
+		{
+			name:           "all ok",
+			opts:           []Option{WithCheck("database", ok), WithCheck("plant_listener", ok)},
+			expectedStatus: StatusOK,
+			expectedErrors: map[string]string{"database": "", "plant_listener": ""},
+		},
+		{
+			name:           "one failing",
+			opts:           []Option{WithCheck("database", ok), WithCheck("plant_listener", failing)},
+			expectedStatus: StatusFail,
+			expectedErrors: map[string]string{"database": "", "plant_listener": "connection refused"},
+		},
+		{
+			name:           "timeout",
+			opts:           []Option{WithTimeout(20 * time.Millisecond), WithCheck("database", hanging)},
+			expectedStatus: StatusFail,
+			expectedErrors: map[string]string{"database": "no answer: context deadline exceeded"},
+		},
+		{
+			name:           "no checks",
+			expectedStatus: StatusOK,
+			expectedErrors: map[string]string{},
+		},
+		{
+			name:           "draining",
+			opts:           []Option{WithCheck("database", failing)},
+			drain:          true,
+			expectedStatus: StatusDraining,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			c := New(tt.opts...)
+			if tt.drain {
+				c.Drain()
+			}
+
+			// Act
+			report := c.Ready(context.Background())
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			require.Len(t, report.Components, len(tt.expectedErrors))
+			for name, expectedErr := range tt.expectedErrors {
+				component := report.Components[name]
+				assert.Equal(t, expectedErr, component.Error, name)
+				if expectedErr == "" {
+					assert.Equal(t, StatusOK, component.Status, name)
+				} else {
+					assert.Equal(t, StatusFail, component.Status, name)
+				}
+			}
+		})
+	}
+}
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+type versionReader struct {
+	version int64
+	err     error
+}
+
+func (v versionReader) Version(context.Context) (int64, error) { return v.version, v.err }
+
+func TestChecks(t *testing.T) {
+	slowPing := pingerFunc(func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		check       Check
+		expectedErr string
+	}{
+		{name: "ping ok", check: Ping(pingerFunc(func(context.Context) error { return nil }), time.Second)},
+		{name: "ping error", check: Ping(pingerFunc(func(context.Context) error { return errors.New("refused") }), time.Second), expectedErr: "refused"},
+		{name: "ping too slow", check: Ping(slowPing, time.Millisecond), expectedErr: "ping took"},
+		{name: "ping without limit", check: Ping(slowPing, 0)},
+		{name: "migrations up to date", check: MigrationVersion(versionReader{version: 20261016200000}, 20261016200000)},
+		{name: "migrations behind", check: MigrationVersion(versionReader{version: 20261016190000}, 20261016200000), expectedErr: "database schema is at version 20261016190000, expected at least 20261016200000"},
+		{name: "migrations ahead", check: MigrationVersion(versionReader{version: 20261016210000}, 20261016200000)},
+		{name: "migrations unreadable", check: MigrationVersion(versionReader{err: errors.New("timeout")}, 1), expectedErr: "timeout"},
+		{name: "worker ok", check: Worker(func() error { return nil })},
+		{name: "worker down", check: Worker(func() error { return errors.New("not listening") }), expectedErr: "not listening"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.check(context.Background())
+
+			// Assert
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrCorrupted - сохраненный ответ не расшифровывается ключом запроса.
+var ErrCorrupted = errors.New("stored response cannot be opened")
+
+// PendingTimeout - сколько незавершенная запись удерживает ключ. Если обработчик не завершил ее за это время
+// (реплика упала посреди запроса), ключ снова можно использовать.
+const PendingTimeout = 2 * time.Minute
+
+// Record - запись о запросе с ключом идемпотентности.
+// Status равен нулю, пока первый запрос еще выполняется.
+type Record struct {
+	Fingerprint string
+	Status      int
+	// Body - ответ, зашифрованный Seal.
+	Body []byte
+}
+
+// Store хранит записи по хэшу ключа в течение ttl.
+type Store interface {
+	// Begin занимает ключ: если записи нет, она истекла или брошена, создается незавершенная запись
+	// и возвращается true. Иначе возвращается существующая запись.
+	Begin(ctx context.Context, keyHash, fingerprint string, ttl time.Duration) (Record, bool, error)
+	// Complete сохраняет ответ первого запроса.
+	Complete(ctx context.Context, keyHash string, status int, body []byte) error
+	// Release удаляет незавершенную запись, чтобы запрос можно было повторить.
+	Release(ctx context.Context, keyHash string) error
+}
+
+// HashKey возвращает хэш ключа для хранилища. Scope отделяет ключи разных маршрутов и пользователей.
+// Сам ключ не сохраняется: он нужен, чтобы расшифровать ответ.
+func HashKey(scope, key string) string {
+	sum := sha256.Sum256([]byte("digital-forest/idempotency/lookup\x00" + scope + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal шифрует ответ ключом, полученным из ключа идемпотентности (AES-256-GCM).
+// Ответ на посадку содержит ownerToken, а база хранит только хэш токена, поэтому открытый ответ в ней
+// хранить нельзя. Расшифровать сохраненный ответ может только тот, кто знает ключ, - то есть автор запроса.
+func Seal(key string, body []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	return aead.Seal(nonce, nonce, body, nil), nil
+}
+
+// Open расшифровывает ответ, зашифрованный Seal тем же ключом.
+func Open(key string, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	body, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return body, nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte("digital-forest/idempotency/seal\x00" + key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	// Arrange
+	body := []byte(`{"id":1,"ownerToken":"secret"}`)
+
+	// Act
+	sealed, err := Seal("key-0123456789abcdef", body)
+	require.NoError(t, err)
+	opened, err := Open("key-0123456789abcdef", sealed)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, body, opened)
+	assert.NotContains(t, string(sealed), "secret")
+
+	_, err = Open("another-key-0123456789", sealed)
+	assert.ErrorIs(t, err, ErrCorrupted)
+	_, err = Open("key-0123456789abcdef", sealed[:4])
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestHashKey(t *testing.T) {
+	assert.Equal(t, HashKey("create_plant:anon", "key"), HashKey("create_plant:anon", "key"))
+	assert.NotEqual(t, HashKey("create_plant:anon", "key"), HashKey("create_plant:user:1", "key"))
+	assert.Len(t, HashKey("create_plant:anon", "key"), 64)
+}
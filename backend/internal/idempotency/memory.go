@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто MemoryStore удаляет истекшие записи.
+const sweepInterval = time.Minute
+
+// memoryRecord - запись вместе со сроками жизни.
+type memoryRecord struct {
+	Record
+	startedAt time.Time
+	expiresAt time.Time
+}
+
+// MemoryStore хранит записи в памяти процесса. Повтор, попавший на другую реплику, она не узнает.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore - конструктор для MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*memoryRecord),
+		now:     time.Now,
+	}
+}
+
+// Begin занимает ключ или возвращает существующую запись.
+func (s *MemoryStore) Begin(ctx context.Context, keyHash, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[keyHash]; ok && now.Before(r.expiresAt) && !r.abandoned(now) {
+		return r.Record, false, nil
+	}
+	s.records[keyHash] = &memoryRecord{
+		Record:    Record{Fingerprint: fingerprint},
+		startedAt: now,
+		expiresAt: now.Add(ttl),
+	}
+	return Record{Fingerprint: fingerprint}, true, nil
+}
+
+// Complete сохраняет ответ первого запроса.
+func (s *MemoryStore) Complete(ctx context.Context, keyHash string, status int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[keyHash]; ok {
+		r.Status, r.Body = status, body
+	}
+	return nil
+}
+
+// Release удаляет незавершенную запись.
+func (s *MemoryStore) Release(ctx context.Context, keyHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[keyHash]; ok && r.Status == 0 {
+		delete(s.records, keyHash)
+	}
+	return nil
+}
+
+// abandoned сообщает, что запрос так и не завершил запись.
+func (r *memoryRecord) abandoned(now time.Time) bool {
+	return r.Status == 0 && now.Sub(r.startedAt) >= PendingTimeout
+}
+
+// sweep удаляет истекшие записи.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for keyHash, r := range s.records {
+		if !now.Before(r.expiresAt) {
+			delete(s.records, keyHash)
+		}
+	}
+}
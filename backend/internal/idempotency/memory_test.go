@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+	ttl := time.Hour
+
+	// Act & Assert: первый запрос занимает ключ, повтор видит незавершенную запись
+	_, started, err := store.Begin(ctx, "a", "fp", ttl)
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	record, started, err := store.Begin(ctx, "a", "fp", ttl)
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Zero(t, record.Status)
+
+	// После завершения повтор получает ответ
+	require.NoError(t, store.Complete(ctx, "a", 201, []byte("body")))
+	record, started, err = store.Begin(ctx, "a", "other", ttl)
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Equal(t, Record{Fingerprint: "fp", Status: 201, Body: []byte("body")}, record)
+
+	// Release не трогает завершенную запись
+	require.NoError(t, store.Release(ctx, "a"))
+	_, started, _ = store.Begin(ctx, "a", "fp", ttl)
+	assert.False(t, started)
+
+	// Освобожденный ключ можно занять снова
+	_, _, _ = store.Begin(ctx, "b", "fp", ttl)
+	require.NoError(t, store.Release(ctx, "b"))
+	_, started, _ = store.Begin(ctx, "b", "fp", ttl)
+	assert.True(t, started)
+
+	// Брошенная запись перестает держать ключ после PendingTimeout
+	now = now.Add(PendingTimeout)
+	_, started, _ = store.Begin(ctx, "b", "fp", ttl)
+	assert.True(t, started)
+
+	// Истекшие записи забываются
+	now = now.Add(ttl)
+	_, started, _ = store.Begin(ctx, "a", "fp", ttl)
+	assert.True(t, started)
+	assert.Len(t, store.records, 1)
+}
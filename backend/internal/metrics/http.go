@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTP - метрики HTTP-запросов по маршрутам.
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+func newHTTP() *HTTP {
+	return &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Число обработанных HTTP-запросов по маршруту, методу и статусу ответа.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Время обработки HTTP-запросов по маршруту и методу.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Число HTTP-запросов, которые обрабатываются прямо сейчас, включая открытые потоки.",
+		}),
+	}
+}
+
+// Started отмечает начало обработки запроса. Для каждого вызова нужен ровно один Finished.
+func (h *HTTP) Started() {
+	h.inFlight.Inc()
+}
+
+// Finished записывает обработанный запрос. route - шаблон маршрута, а не путь,
+// иначе каждый идентификатор растения создавал бы новый ряд метрик.
+func (h *HTTP) Finished(route, method string, status int, elapsed time.Duration) {
+	h.inFlight.Dec()
+	h.requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	h.duration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+}
+
+// Describe реализует prometheus.Collector.
+func (h *HTTP) Describe(ch chan<- *prometheus.Desc) {
+	h.requests.Describe(ch)
+	h.duration.Describe(ch)
+	h.inFlight.Describe(ch)
+}
+
+// Collect реализует prometheus.Collector.
+func (h *HTTP) Collect(ch chan<- prometheus.Metric) {
+	h.requests.Collect(ch)
+	h.duration.Collect(ch)
+	h.inFlight.Collect(ch)
+}
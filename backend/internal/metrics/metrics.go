@@ -0,0 +1,73 @@
+// Package metrics собирает метрики сервиса в формате Prometheus:
+// HTTP-запросы, вызовы use cases, пул соединений с базой и число растений.
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace - общий префикс имен всех метрик сервиса.
+const Namespace = "digital_forest"
+
+// Metrics - реестр метрик сервиса. Каждый экземпляр независим, поэтому тесты не мешают друг другу.
+type Metrics struct {
+	registry *prometheus.Registry
+	// HTTP - метрики HTTP-запросов, их пишет middleware.Metrics.
+	HTTP *HTTP
+	// UseCases - метрики вызовов use cases.
+	UseCases *UseCases
+}
+
+// New создает реестр с метриками HTTP, use cases, среды выполнения Go и процесса.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTP:     newHTTP(),
+		UseCases: newUseCases(),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTP,
+		m.UseCases,
+	)
+	return m
+}
+
+// Register добавляет в реестр дополнительные метрики, например NewPoolCollector и NewPlantCollector.
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler отдает метрики в текстовом формате Prometheus.
+// Если часть метрик собрать не удалось (например, база недоступна), остальные все равно отдаются.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorLog:      slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Server - отдельный HTTP-сервер на addr, который отдает только GET /metrics.
+// Метрики раскрывают трафик по маршрутам и число растений, поэтому они не публикуются на основном порту:
+// addr должен быть доступен только сборщику метрик, например из внутренней сети кластера.
+func (m *Metrics) Server(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m.Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
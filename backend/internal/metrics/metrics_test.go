@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	appTestutil "github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "success", err: nil, expected: ResultSuccess},
+		{name: "rejected", err: fmt.Errorf("get: %w", domain.ErrNotFound), expected: ResultRejected},
+		{name: "internal", err: errors.New("connection reset"), expected: ResultError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Result(tt.err))
+		})
+	}
+}
+
+// observe записывает в u завершенный вызов use case name с ошибкой err.
+func observe(u *UseCases, name string, err error) {
+	_, done := u.Observe(context.Background(), name)
+	done(err)
+}
+
+func TestUseCases_Observe(t *testing.T) {
+	// Arrange
+	u := newUseCases()
+
+	// Act
+	observe(u, "plant.get_random", nil)
+	observe(u, "plant.get_random", nil)
+	observe(u, "plant.get_by_id", domain.ErrNotFound)
+	observe(u, "plant.get_by_id", cerror.Wrap(cerror.Internal, errors.New("timeout")))
+
+	// Assert
+	assert.Equal(t, 2.0, testutil.ToFloat64(u.calls.WithLabelValues("plant.get_random", ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(u.calls.WithLabelValues("plant.get_by_id", ResultRejected)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(u.calls.WithLabelValues("plant.get_by_id", ResultError)))
+	assert.Equal(t, 2, testutil.CollectAndCount(u.duration))
+}
+
+func TestHTTP_Finished(t *testing.T) {
+	// Arrange
+	h := newHTTP()
+
+	// Act
+	h.Started()
+	h.Started()
+	h.Finished("/v1/plants/{id}", http.MethodGet, http.StatusNotFound, 20*time.Millisecond)
+
+	// Assert
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.inFlight))
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.requests.WithLabelValues("/v1/plants/{id}", http.MethodGet, "404")))
+}
+
+func TestPlantCollector(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		counts      map[domain.Status]int
+		countsErr   error
+		created     int
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:    "counts",
+			counts:  map[domain.Status]int{domain.StatusVisible: 40, domain.StatusPending: 2},
+			created: 3,
+			expected: `
+# HELP digital_forest_plants_created_last_minute Число растений, посаженных за последнюю минуту, в любом статусе.
+# TYPE digital_forest_plants_created_last_minute gauge
+digital_forest_plants_created_last_minute 3
+# HELP digital_forest_plants_total Число растений по статусам.
+# TYPE digital_forest_plants_total gauge
+digital_forest_plants_total{status="deleted"} 0
+digital_forest_plants_total{status="hidden"} 0
+digital_forest_plants_total{status="pending"} 2
+digital_forest_plants_total{status="visible"} 40
+`,
+		},
+		{
+			name:        "database error",
+			counts:      map[domain.Status]int(nil),
+			countsErr:   errors.New("db is down"),
+			created:     3,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(appTestutil.MockPlantRepository)
+			mockRepo.On("CountByStatus", mock.Anything).Return(tt.counts, tt.countsErr)
+			mockRepo.On("CountCreatedSince", mock.Anything, now.Add(-time.Minute)).Return(tt.created, nil)
+
+			c := NewPlantCollector(mockRepo)
+			c.now = func() time.Time { return now }
+
+			// Act
+			err := testutil.CollectAndCompare(c, strings.NewReader(tt.expected))
+
+			// Assert
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// failingCounter - источник растений, у которого база недоступна.
+type failingCounter struct{}
+
+func (failingCounter) CountByStatus(ctx context.Context) (map[domain.Status]int, error) {
+	return nil, errors.New("db is down")
+}
+
+func (failingCounter) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	return 0, errors.New("db is down")
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	// Arrange
+	m := New()
+	require.NoError(t, m.Register(NewPlantCollector(failingCounter{})))
+	m.HTTP.Started()
+	m.HTTP.Finished("/v1/plants", http.MethodGet, http.StatusOK, time.Millisecond)
+
+	rr := httptest.NewRecorder()
+
+	// Act
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Assert: недоступная база не мешает отдать остальные метрики
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body, _ := io.ReadAll(rr.Body)
+	assert.Contains(t, string(body), `digital_forest_http_requests_total{method="GET",route="/v1/plants",status="200"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+	assert.NotContains(t, string(body), "digital_forest_plants_total")
+}
+
+func TestMetrics_Server(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "metrics", method: http.MethodGet, path: "/metrics", expectedStatus: http.StatusOK},
+		{name: "other path", method: http.MethodGet, path: "/v1/plants", expectedStatus: http.StatusNotFound},
+		{name: "other method", method: http.MethodPost, path: "/metrics", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	server := New().Server(":9090")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rr := httptest.NewRecorder()
+
+			// Act
+			server.Handler.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+	assert.Equal(t, ":9090", server.Addr)
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// plantQueryTimeout ограничивает запросы к базе при сборе метрик.
+const plantQueryTimeout = 5 * time.Second
+
+// PlantCounter - интерфейс для подсчета растений.
+type PlantCounter interface {
+	CountByStatus(ctx context.Context) (map[domain.Status]int, error)
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+}
+
+// PlantCollector отдает число растений по статусам и число растений, посаженных за последнюю минуту.
+// Растения считаются запросом к базе при каждом сборе метрик.
+type PlantCollector struct {
+	plants PlantCounter
+	now    func() time.Time
+
+	total      *prometheus.Desc
+	lastMinute *prometheus.Desc
+}
+
+// NewPlantCollector создает сборщик метрик растений.
+func NewPlantCollector(plants PlantCounter) *PlantCollector {
+	return &PlantCollector{
+		plants: plants,
+		now:    time.Now,
+		total: prometheus.NewDesc(prometheus.BuildFQName(Namespace, "plants", "total"),
+			"Число растений по статусам.", []string{"status"}, nil),
+		lastMinute: prometheus.NewDesc(prometheus.BuildFQName(Namespace, "plants", "created_last_minute"),
+			"Число растений, посаженных за последнюю минуту, в любом статусе.", nil, nil),
+	}
+}
+
+// Describe реализует prometheus.Collector.
+func (c *PlantCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.total
+	ch <- c.lastMinute
+}
+
+// Collect реализует prometheus.Collector. Если запрос к базе не удался, метрика отдается с ошибкой,
+// а Metrics.Handler отвечает остальными метриками.
+func (c *PlantCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), plantQueryTimeout)
+	defer cancel()
+
+	counts, err := c.plants.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.total, err)
+	} else {
+		for _, status := range []domain.Status{domain.StatusVisible, domain.StatusPending, domain.StatusHidden, domain.StatusDeleted} {
+			ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(counts[status]), string(status))
+		}
+	}
+
+	created, err := c.plants.CountCreatedSince(ctx, c.now().Add(-time.Minute))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.lastMinute, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.lastMinute, prometheus.GaugeValue, float64(created))
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStater - источник статистики пула соединений, например *pgxpool.Pool.
+type PoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// PoolCollector отдает статистику пула соединений с базой в момент сбора метрик.
+type PoolCollector struct {
+	pool PoolStater
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	constructing *prometheus.Desc
+	total        *prometheus.Desc
+	max          *prometheus.Desc
+	acquires     *prometheus.Desc
+	acquireTime  *prometheus.Desc
+	waits        *prometheus.Desc
+	waitTime     *prometheus.Desc
+	canceled     *prometheus.Desc
+}
+
+// NewPoolCollector создает сборщик статистики пула pool.
+func NewPoolCollector(pool PoolStater) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(Namespace, "db_pool", name), help, nil, nil)
+	}
+	return &PoolCollector{
+		pool:         pool,
+		acquired:     desc("acquired_connections", "Число соединений, занятых запросами."),
+		idle:         desc("idle_connections", "Число свободных соединений."),
+		constructing: desc("constructing_connections", "Число соединений, которые сейчас устанавливаются."),
+		total:        desc("connections", "Общее число соединений в пуле."),
+		max:          desc("max_connections", "Наибольшее допустимое число соединений."),
+		acquires:     desc("acquires_total", "Число полученных из пула соединений."),
+		acquireTime:  desc("acquire_seconds_total", "Суммарное время получения соединений из пула."),
+		waits:        desc("waits_total", "Число запросов соединения, которым пришлось ждать: свободных соединений не было."),
+		waitTime:     desc("wait_seconds_total", "Суммарное время ожидания свободного соединения."),
+		canceled:     desc("canceled_acquires_total", "Число запросов соединения, отмененных до его получения."),
+	}
+}
+
+// Describe реализует prometheus.Collector.
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.acquireTime, c.waits, c.waitTime, c.canceled,
+	} {
+		ch <- d
+	}
+}
+
+// Collect реализует prometheus.Collector.
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquired, float64(s.AcquiredConns()))
+	gauge(c.idle, float64(s.IdleConns()))
+	gauge(c.constructing, float64(s.ConstructingConns()))
+	gauge(c.total, float64(s.TotalConns()))
+	gauge(c.max, float64(s.MaxConns()))
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.acquireTime, s.AcquireDuration().Seconds())
+	counter(c.waits, float64(s.EmptyAcquireCount()))
+	counter(c.waitTime, s.EmptyAcquireWaitTime().Seconds())
+	counter(c.canceled, float64(s.CanceledAcquireCount()))
+}
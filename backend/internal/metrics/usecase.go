@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Результаты вызова use case в метке result.
+const (
+	// ResultSuccess - вызов завершился без ошибки.
+	ResultSuccess = "success"
+	// ResultRejected - use case отклонил запрос: ошибка определенного вида, например cerror.NotFound.
+	ResultRejected = "rejected"
+	// ResultError - внутренняя ошибка (cerror.Internal).
+	ResultError = "error"
+)
+
+// UseCases - метрики вызовов use cases.
+type UseCases struct {
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newUseCases() *UseCases {
+	return &UseCases{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "usecase",
+			Name:      "calls_total",
+			Help:      "Число вызовов use cases по результату: success, rejected или error.",
+		}, []string{"usecase", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "usecase",
+			Name:      "duration_seconds",
+			Help:      "Время выполнения use cases.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"usecase"}),
+	}
+}
+
+// Observe начинает замер вызова use case с именем name, например "plant.get_random"; ctx не меняется.
+// Возвращенную функцию нужно вызвать с ошибкой use case, когда он завершится.
+func (u *UseCases) Observe(ctx context.Context, name string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		u.duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		u.calls.WithLabelValues(name, Result(err)).Inc()
+	}
+}
+
+// Result возвращает значение метки result для ошибки use case.
+func Result(err error) string {
+	switch {
+	case err == nil:
+		return ResultSuccess
+	case cerror.KindOf(err) == cerror.Internal:
+		return ResultError
+	default:
+		return ResultRejected
+	}
+}
+
+// Describe реализует prometheus.Collector.
+func (u *UseCases) Describe(ch chan<- *prometheus.Desc) {
+	u.calls.Describe(ch)
+	u.duration.Describe(ch)
+}
+
+// Collect реализует prometheus.Collector.
+func (u *UseCases) Collect(ch chan<- prometheus.Metric) {
+	u.calls.Collect(ch)
+	u.duration.Collect(ch)
+}
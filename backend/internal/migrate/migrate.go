@@ -0,0 +1,112 @@
+// Package migrate применяет к базе миграции, встроенные в бинарник (см. migrations.FS).
+// Версии примененных миграций goose хранит в таблице goose_db_version.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"github.com/heartmarshall/digital-forest/backend/migrations"
+)
+
+// ErrNothingApplied возвращается Down, если откатывать нечего.
+var ErrNothingApplied = errors.New("no applied migrations")
+
+// Migration - миграция и ее состояние в базе.
+type Migration struct {
+	Version int64
+	// Name - имя файла миграции, например 20250921121820_create_plants_table.sql.
+	Name    string
+	Applied bool
+	// AppliedAt - когда миграция применена; нулевое, если не применена.
+	AppliedAt time.Time
+}
+
+// Migrator применяет миграции к базе. Миграции разных реплик не пересекаются:
+// на время применения Migrator держит advisory lock Postgres.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// New создает Migrator для базы pool. Соединения берутся из pool; Close пул не закрывает.
+func New(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("migrate - New - NewPostgresSessionLocker: %w", err)
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, stdlib.OpenDBFromPool(pool), migrations.FS,
+		goose.WithSessionLocker(locker),
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithSlog(slog.Default()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("migrate - New - NewProvider: %w", err)
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Up применяет все еще не примененные миграции и возвращает их по порядку.
+// Если миграция завершилась ошибкой, предшествующие ей остаются примененными.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	results, err := m.provider.Up(ctx)
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		results = partial.Applied
+	}
+
+	applied := make([]Migration, 0, len(results))
+	for _, r := range results {
+		applied = append(applied, fromSource(r.Source, true))
+	}
+	if err != nil {
+		return applied, fmt.Errorf("migrate - Up: %w", err)
+	}
+	return applied, nil
+}
+
+// Down откатывает последнюю примененную миграцию и возвращает ее.
+// Если примененных миграций нет, возвращает ErrNothingApplied.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	result, err := m.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return Migration{}, ErrNothingApplied
+	}
+	if err != nil {
+		return Migration{}, fmt.Errorf("migrate - Down: %w", err)
+	}
+	return fromSource(result.Source, false), nil
+}
+
+// Status возвращает все встроенные миграции по возрастанию версии с отметкой, применены ли они.
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate - Status: %w", err)
+	}
+
+	list := make([]Migration, 0, len(statuses))
+	for _, s := range statuses {
+		migration := fromSource(s.Source, s.State == goose.StateApplied)
+		migration.AppliedAt = s.AppliedAt
+		list = append(list, migration)
+	}
+	return list, nil
+}
+
+// Close освобождает соединения Migrator. Пул, переданный в New, остается открытым.
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
+
+func fromSource(s *goose.Source, applied bool) Migration {
+	return Migration{Version: s.Version, Name: path.Base(s.Path), Applied: applied}
+}
@@ -0,0 +1,82 @@
+package migrate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/migrate"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+)
+
+func TestMigrator(t *testing.T) {
+	// Arrange: SetupTestDB уже применил все миграции
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	migrator, err := migrate.New(dbPool)
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	// Act & Assert: все миграции применены, повторный Up ничего не делает
+	list, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	for _, m := range list {
+		assert.True(t, m.Applied, m.Name)
+		assert.False(t, m.AppliedAt.IsZero(), m.Name)
+	}
+	last := list[len(list)-1]
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// Act & Assert: Down откатывает только последнюю миграцию
+	rolledBack, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, last.Version, rolledBack.Version)
+	assert.Equal(t, last.Name, rolledBack.Name)
+
+	list, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, list[len(list)-1].Applied)
+	assert.True(t, list[len(list)-2].Applied)
+
+	// Act & Assert: Up возвращает откаченную миграцию
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, last.Version, applied[0].Version)
+	assert.True(t, applied[0].Applied)
+}
+
+func TestMigrator_DownKeepsGridOnlyPlants(t *testing.T) {
+	// Arrange: растение новой схемы хранит только сетку
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	migrator, err := migrate.New(dbPool)
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	_, err = dbPool.Exec(ctx, `INSERT INTO plants (author, grid) VALUES ('author', '\x00')`)
+	require.NoError(t, err)
+
+	// Act: откатываем миграции, пока откат не остановится
+	for {
+		if _, err = migrator.Down(ctx); err != nil {
+			break
+		}
+	}
+
+	// Assert: откат остановился на миграции сетки и растение не удалено
+	assert.ErrorContains(t, err, "cannot roll back")
+	var count int
+	require.NoError(t, dbPool.QueryRow(ctx, "SELECT count(*) FROM plants WHERE grid IS NOT NULL").Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// regexPrefix отмечает в файле черного списка строку с регулярным выражением.
+const regexPrefix = "re:"
+
+// Rule - запись черного списка имен авторов.
+// Точная запись совпадает с именем, скелет которого равен ее скелету (см. domain.AuthorSkeleton),
+// регулярное выражение ищется в любом месте скелета имени без учета регистра.
+type Rule struct {
+	Pattern string
+	Regex   bool
+}
+
+// String возвращает запись в том виде, в каком она пишется в файле.
+func (r Rule) String() string {
+	if r.Regex {
+		return regexPrefix + r.Pattern
+	}
+	return r.Pattern
+}
+
+// ParseRules читает черный список: одна запись на строку, строки с префиксом "re:" - регулярные выражения,
+// пустые строки и строки, начинающиеся с "#", пропускаются.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		rule := Rule{Pattern: text}
+		if pattern, ok := strings.CutPrefix(text, regexPrefix); ok {
+			rule = Rule{Pattern: strings.TrimSpace(pattern), Regex: true}
+		}
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("line %d: empty rule", line)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// compiledRules - черный список, подготовленный к сравнению.
+type compiledRules struct {
+	// exact - скелеты точных записей.
+	exact map[string]Rule
+	regex []compiledRegex
+}
+
+type compiledRegex struct {
+	rule Rule
+	re   *regexp.Regexp
+}
+
+// compile проверяет записи и приводит их к виду скелета.
+func compile(rules []Rule) (*compiledRules, error) {
+	c := &compiledRules{exact: make(map[string]Rule)}
+	for _, rule := range rules {
+		if !rule.Regex {
+			skeleton := domain.AuthorSkeleton(rule.Pattern)
+			if skeleton == "" {
+				return nil, fmt.Errorf("rule %q has no letters or digits", rule.Pattern)
+			}
+			c.exact[skeleton] = rule
+			continue
+		}
+
+		re, err := regexp.Compile("(?i)" + domain.FoldHomoglyphs(rule.Pattern))
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule, err)
+		}
+		c.regex = append(c.regex, compiledRegex{rule: rule, re: re})
+	}
+	return c, nil
+}
+
+// Blocklist - черный список имен авторов, который можно заменить на лету.
+// Проверки читают текущий список без блокировок; неудачная замена оставляет прежний список.
+type Blocklist struct {
+	rules atomic.Pointer[compiledRules]
+	// path - файл, из которого список перечитывает Reload; пуст у списка, созданного NewBlocklist.
+	path string
+	// reload не дает двум Reload читать файл одновременно.
+	reload sync.Mutex
+}
+
+// NewBlocklist создает черный список из записей.
+func NewBlocklist(rules []Rule) (*Blocklist, error) {
+	b := &Blocklist{}
+	if err := b.Replace(rules); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// LoadBlocklist читает черный список из файла (формат - см. ParseRules). Reload перечитывает тот же файл.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	b := &Blocklist{path: path}
+	if _, err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Reload перечитывает файл черного списка и возвращает число записей в нем.
+// Если файл не читается или в нем есть ошибка, продолжает действовать прежний список.
+func (b *Blocklist) Reload() (int, error) {
+	if b.path == "" {
+		return 0, errors.New("blocklist was not loaded from a file")
+	}
+
+	b.reload.Lock()
+	defer b.reload.Unlock()
+
+	f, err := os.Open(b.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	rules, err := ParseRules(f)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", b.path, err)
+	}
+	if err := b.Replace(rules); err != nil {
+		return 0, fmt.Errorf("%s: %w", b.path, err)
+	}
+	return len(rules), nil
+}
+
+// Replace заменяет записи черного списка. При ошибке в любой записи список не меняется.
+func (b *Blocklist) Replace(rules []Rule) error {
+	compiled, err := compile(rules)
+	if err != nil {
+		return err
+	}
+	b.rules.Store(compiled)
+	return nil
+}
+
+// Match возвращает первую запись, под которую попадает имя.
+func (b *Blocklist) Match(name string) (Rule, bool) {
+	rules := b.rules.Load()
+	skeleton := domain.AuthorSkeleton(name)
+
+	if rule, ok := rules.exact[skeleton]; ok {
+		return rule, true
+	}
+	for _, r := range rules.regex {
+		if r.re.MatchString(skeleton) {
+			return r.rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// CheckAuthor возвращает domain.ErrAuthorBlocked, если имя автора попадает в черный список.
+// Сработавшая запись в ошибку не попадает: сообщение уходит клиенту, а список не должен подсказывать обход.
+// Запись можно узнать через Match.
+func (b *Blocklist) CheckAuthor(name string) error {
+	if _, ok := b.Match(name); ok {
+		return domain.ErrAuthorBlocked
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      []Rule
+		expectedError bool
+	}{
+		{
+			name:     "exact and regex",
+			input:    "admin\nre:f+u+c+k\n",
+			expected: []Rule{{Pattern: "admin"}, {Pattern: "f+u+c+k", Regex: true}},
+		},
+		{
+			name:     "comments, blank lines and spaces",
+			input:    "# header\n\n  модератор  \nre:  пизд \n",
+			expected: []Rule{{Pattern: "модератор"}, {Pattern: "пизд", Regex: true}},
+		},
+		{name: "empty file", input: "", expected: nil},
+		{name: "empty regex", input: "admin\nre:\n", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			rules, err := ParseRules(strings.NewReader(tt.input))
+
+			// Assert
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "line 2")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestNewBlocklist_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+	}{
+		{name: "broken regex", rules: []Rule{{Pattern: "(", Regex: true}}},
+		{name: "exact rule without letters", rules: []Rule{{Pattern: "..."}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBlocklist(tt.rules)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestBlocklist_DefaultFile проверяет список, который поставляется с приложением.
+func TestBlocklist_DefaultFile(t *testing.T) {
+	blocklist, err := LoadBlocklist(filepath.Join("..", "..", "config", "author_blocklist.txt"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		author  string
+		blocked bool
+	}{
+		// Выдача себя за администрацию - только имя целиком
+		{name: "exact", author: "admin", blocked: true},
+		{name: "exact, other case", author: "ADMIN", blocked: true},
+		{name: "exact, spaced", author: "a d m i n", blocked: true},
+		{name: "exact, cyrillic lookalike", author: "аdmin", blocked: true},
+		{name: "exact, symbol lookalike", author: "@DMIN", blocked: true},
+		{name: "exact, russian", author: "Модератор", blocked: true},
+		{name: "exact entry inside a longer name", author: "admin of the forest"},
+		{name: "russian with latin lookalikes", author: "Mодерaтор", blocked: true},
+
+		// Ругательства - в любом месте имени
+		{name: "english regex", author: "big fuuuck", blocked: true},
+		{name: "english regex with lookalikes", author: "fυck", blocked: true},
+		{name: "english regex with separators", author: "f.u.c.k", blocked: true},
+		{name: "russian regex", author: "хуй", blocked: true},
+		{name: "russian regex in latin lookalikes", author: "xyй", blocked: true},
+		{name: "russian regex, zero as o", author: "пид0р", blocked: true},
+		{name: "russian regex, three as ze", author: "пи3да", blocked: true},
+		{name: "russian regex, anchored", author: "Ёбаный", blocked: true},
+		{name: "russian regex, anchored, inside a word", author: "хлебал"},
+
+		// Обычные имена
+		{name: "plain english", author: "Forest Keeper"},
+		{name: "plain russian", author: "Лесник Иван"},
+		{name: "word containing an exact entry", author: "rooted"},
+		{name: "similar but allowed", author: "худой ёжик"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := blocklist.CheckAuthor(tt.author)
+
+			// Assert
+			if tt.blocked {
+				assert.ErrorIs(t, err, domain.ErrAuthorBlocked)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBlocklist_Reload(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("spruce\n"), 0o600))
+	blocklist, err := LoadBlocklist(path)
+	require.NoError(t, err)
+	require.ErrorIs(t, blocklist.CheckAuthor("Spruce"), domain.ErrAuthorBlocked)
+
+	t.Run("new rules replace old ones", func(t *testing.T) {
+		// Arrange
+		require.NoError(t, os.WriteFile(path, []byte("pine\nre:^oa+k\n"), 0o600))
+
+		// Act
+		count, err := blocklist.Reload()
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.NoError(t, blocklist.CheckAuthor("spruce"))
+		assert.ErrorIs(t, blocklist.CheckAuthor("pine"), domain.ErrAuthorBlocked)
+		assert.ErrorIs(t, blocklist.CheckAuthor("Oaaak tree"), domain.ErrAuthorBlocked)
+	})
+
+	t.Run("broken file keeps current rules", func(t *testing.T) {
+		// Arrange
+		require.NoError(t, os.WriteFile(path, []byte("re:(\n"), 0o600))
+
+		// Act
+		_, err := blocklist.Reload()
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, blocklist.CheckAuthor("pine"), domain.ErrAuthorBlocked)
+	})
+
+	t.Run("missing file keeps current rules", func(t *testing.T) {
+		// Arrange
+		require.NoError(t, os.Remove(path))
+
+		// Act
+		_, err := blocklist.Reload()
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, blocklist.CheckAuthor("pine"), domain.ErrAuthorBlocked)
+	})
+
+	t.Run("blocklist without a file cannot reload", func(t *testing.T) {
+		inline, err := NewBlocklist([]Rule{{Pattern: "pine"}})
+		require.NoError(t, err)
+
+		_, err = inline.Reload()
+		assert.Error(t, err)
+	})
+}
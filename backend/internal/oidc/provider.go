@@ -0,0 +1,129 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// ErrTokenRejected - провайдер не выдал токен по коду или ID-токен не прошел проверку.
+var ErrTokenRejected = errors.New("oidc: token rejected")
+
+// defaultScopes запрашиваются, если в конфигурации провайдера scopes не заданы.
+var defaultScopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+
+// Config - настройки одного провайдера.
+type Config struct {
+	// Name - короткое имя провайдера в URL входа, например google.
+	Name string
+	// Issuer - URL издателя; по нему читается /.well-known/openid-configuration.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL - адрес callback этого сервиса, зарегистрированный у провайдера.
+	RedirectURL string
+	Scopes      []string
+}
+
+// Claims - проверенные утверждения ID-токена, нужные для входа.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Nonce             string
+	PreferredUsername string
+	Email             string
+	EmailVerified     bool
+}
+
+// Provider - провайдер OpenID Connect, настроенный по discovery-документу издателя.
+type Provider struct {
+	name     string
+	oauth    oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewProvider читает discovery-документ издателя и готовит провайдера к входу.
+// Discovery проверяет, что издатель в документе совпадает с cfg.Issuer.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("oidc - NewProvider: provider name is required")
+	}
+
+	discovered, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc - NewProvider - discovery %q: %w", cfg.Issuer, err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+	if !slices.Contains(scopes, gooidc.ScopeOpenID) {
+		scopes = append([]string{gooidc.ScopeOpenID}, scopes...)
+	}
+
+	return &Provider{
+		name: cfg.Name,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     discovered.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: discovered.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// Name возвращает короткое имя провайдера.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL возвращает адрес, на который нужно отправить браузер.
+// В адрес попадает только S256-хэш verifier: сам verifier остается на сервере до обмена кода.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange обменивает код авторизации на токены и проверяет ID-токен:
+// подпись по ключам издателя, iss, aud и срок действия. Nonce проверяет вызывающий код,
+// потому что только он знает, какой nonce был выдан.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (Claims, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: exchange: %v", ErrTokenRejected, err)
+	}
+
+	raw, ok := token.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return Claims{}, fmt.Errorf("%w: no id_token in token response", ErrTokenRejected)
+	}
+
+	idToken, err := p.verifier.Verify(ctx, raw)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrTokenRejected, err)
+	}
+
+	var extra struct {
+		PreferredUsername string `json:"preferred_username"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&extra); err != nil {
+		return Claims{}, fmt.Errorf("%w: claims: %v", ErrTokenRejected, err)
+	}
+
+	return Claims{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Nonce:             idToken.Nonce,
+		PreferredUsername: extra.PreferredUsername,
+		Email:             extra.Email,
+		EmailVerified:     extra.EmailVerified,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const testRedirectURL = "http://forest.test/v1/auth/oidc/mock/callback"
+
+func newTestProvider(t *testing.T, issuer *testutil.MockOIDCIssuer) *Provider {
+	t.Helper()
+	p, err := NewProvider(context.Background(), Config{
+		Name:         "mock",
+		Issuer:       issuer.Issuer(),
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+// authorize проходит /authorize провайдера, как браузер, и возвращает код из редиректа на callback.
+func authorize(t *testing.T, authURL string) string {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query().Get("code")
+}
+
+func TestNewProvider(t *testing.T) {
+	issuer := testutil.NewMockOIDCIssuer(t)
+
+	p := newTestProvider(t, issuer)
+
+	assert.Equal(t, "mock", p.Name())
+	assert.Equal(t, issuer.Issuer()+"/token", p.oauth.Endpoint.TokenURL)
+	assert.Equal(t, []string{"openid", "profile", "email"}, p.oauth.Scopes)
+
+	_, err := NewProvider(context.Background(), Config{Name: "mock", Issuer: issuer.Issuer() + "/other"})
+	assert.Error(t, err, "discovery document of another issuer is rejected")
+
+	_, err = NewProvider(context.Background(), Config{Issuer: issuer.Issuer()})
+	assert.Error(t, err, "provider name is required")
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	p := newTestProvider(t, testutil.NewMockOIDCIssuer(t))
+	verifier := oauth2.GenerateVerifier()
+
+	u, err := url.Parse(p.AuthCodeURL("state-1", "nonce-1", verifier))
+	require.NoError(t, err)
+
+	q := u.Query()
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, "nonce-1", q.Get("nonce"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, oauth2.S256ChallengeFromVerifier(verifier), q.Get("code_challenge"))
+	assert.NotContains(t, u.String(), verifier)
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		override map[string]any
+		verifier func(string) string
+		wantErr  bool
+	}{
+		{name: "valid token"},
+		{name: "wrong PKCE verifier", verifier: func(string) string { return oauth2.GenerateVerifier() }, wantErr: true},
+		{name: "token for another client", override: map[string]any{"aud": "someone-else"}, wantErr: true},
+		{name: "expired token", override: map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}, wantErr: true},
+		{name: "foreign issuer", override: map[string]any{"iss": "https://evil.example"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			issuer := testutil.NewMockOIDCIssuer(t)
+			issuer.SetUser("subject-42", "alice", "alice@example.com")
+			issuer.SetOverride(tt.override)
+			p := newTestProvider(t, issuer)
+
+			verifier := oauth2.GenerateVerifier()
+			code := authorize(t, p.AuthCodeURL("state", "nonce-42", verifier))
+			if tt.verifier != nil {
+				verifier = tt.verifier(verifier)
+			}
+
+			// Act
+			claims, err := p.Exchange(context.Background(), code, verifier)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrTokenRejected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, issuer.Issuer(), claims.Issuer)
+			assert.Equal(t, "subject-42", claims.Subject)
+			assert.Equal(t, "nonce-42", claims.Nonce)
+			assert.Equal(t, "alice", claims.PreferredUsername)
+			assert.Equal(t, "alice@example.com", claims.Email)
+			assert.True(t, claims.EmailVerified)
+		})
+	}
+
+	t.Run("code is single use", func(t *testing.T) {
+		p := newTestProvider(t, testutil.NewMockOIDCIssuer(t))
+		verifier := oauth2.GenerateVerifier()
+		code := authorize(t, p.AuthCodeURL("state", "nonce", verifier))
+
+		_, err := p.Exchange(context.Background(), code, verifier)
+		require.NoError(t, err)
+		_, err = p.Exchange(context.Background(), code, verifier)
+		assert.ErrorIs(t, err, ErrTokenRejected)
+	})
+}
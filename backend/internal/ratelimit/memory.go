@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто MemoryStore удаляет наполнившиеся корзины.
+const sweepInterval = time.Minute
+
+// memoryBucket - корзина вместе с моментом, после которого ее можно удалить.
+type memoryBucket struct {
+	Bucket
+	fullAt time.Time
+}
+
+// MemoryStore хранит корзины в памяти процесса. Каждая реплика считает лимиты отдельно.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore - конструктор для MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+// Take пополняет корзину key и берет из нее токен.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{Bucket: NewBucket(limit, now)}
+		s.buckets[key] = b
+	}
+	result := b.Take(limit, now)
+	b.fullAt = b.FullAt(limit)
+	return result, nil
+}
+
+// sweep удаляет наполнившиеся корзины, чтобы память не росла от разовых посетителей.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 1, Period: time.Minute}
+	ctx := context.Background()
+
+	// Act & Assert: у каждого ключа своя корзина
+	result, err := store.Take(ctx, "ip:1.1.1.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = store.Take(ctx, "ip:1.1.1.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+
+	result, err = store.Take(ctx, "ip:2.2.2.2", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// Наполнившиеся корзины удаляются
+	now = now.Add(2 * time.Minute)
+	_, err = store.Take(ctx, "ip:3.3.3.3", limit)
+	require.NoError(t, err)
+	assert.Len(t, store.buckets, 1)
+}
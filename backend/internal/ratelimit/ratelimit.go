@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// Limit - параметры корзины токенов: Requests запросов за Period в среднем и до Burst подряд.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst - емкость корзины. Ноль означает Requests.
+	Burst int
+}
+
+// Validate проверяет, что лимит задан осмысленно.
+func (l Limit) Validate() error {
+	if l.Requests <= 0 || l.Period <= 0 || l.Burst < 0 {
+		return errors.New("rate limit requires positive requests and period and non-negative burst")
+	}
+	return nil
+}
+
+// Capacity возвращает емкость корзины.
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate - сколько токенов добавляется в корзину за секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result - итог попытки взять токен.
+type Result struct {
+	Allowed bool
+	// Limit - емкость корзины, Remaining - сколько целых токенов в ней осталось.
+	Limit     int
+	Remaining int
+	// RetryAfter - через сколько появится следующий токен; только для отклоненных запросов.
+	RetryAfter time.Duration
+	// Reset - через сколько корзина снова наполнится целиком.
+	Reset time.Duration
+}
+
+// Store хранит корзины по ключам. Take атомарно пополняет корзину key и берет из нее один токен.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket - состояние корзины токенов. Его одинаково считают хранилище в памяти и в Postgres.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewBucket возвращает полную корзину.
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Capacity()), UpdatedAt: now}
+}
+
+// Take пополняет корзину за время, прошедшее с прошлого обращения, и берет токен, если он есть.
+// Отклоненный запрос токен не тратит.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Capacity())
+	rate := limit.rate()
+
+	// Часы могут отставать от момента прошлого обращения (другая реплика), назад корзину не откатываем.
+	elapsed := max(0, now.Sub(b.UpdatedAt).Seconds())
+	b.Tokens = min(capacity, b.Tokens+elapsed*rate)
+	b.UpdatedAt = now
+
+	result := Result{Limit: limit.Capacity()}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.Reset = seconds((capacity - b.Tokens) / rate)
+	return result
+}
+
+// FullAt возвращает момент, когда корзина наполнится целиком. После него хранить ее незачем:
+// отсутствующая корзина и полная ведут себя одинаково.
+func (b Bucket) FullAt(limit Limit) time.Time {
+	return b.UpdatedAt.Add(seconds((float64(limit.Capacity()) - b.Tokens) / limit.rate()))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   Limit
+		wantErr bool
+	}{
+		{name: "valid", limit: Limit{Requests: 10, Period: time.Minute}},
+		{name: "valid with burst", limit: Limit{Requests: 10, Period: time.Minute, Burst: 3}},
+		{name: "zero requests", limit: Limit{Period: time.Minute}, wantErr: true},
+		{name: "zero period", limit: Limit{Requests: 10}, wantErr: true},
+		{name: "negative burst", limit: Limit{Requests: 10, Period: time.Minute, Burst: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr {
+				assert.Error(t, tt.limit.Validate())
+			} else {
+				assert.NoError(t, tt.limit.Validate())
+			}
+		})
+	}
+}
+
+func TestBucket_Take(t *testing.T) {
+	// Arrange: 2 запроса в секунду, до 3 подряд
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	b := NewBucket(limit, now)
+
+	// Act & Assert: полная корзина пропускает Burst запросов подряд
+	for i := 2; i >= 0; i-- {
+		result := b.Take(limit, now)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	denied := b.Take(limit, now)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, denied.Reset)
+
+	// Отклоненный запрос токен не тратит: через полсекунды появляется ровно один
+	assert.True(t, b.Take(limit, now.Add(500*time.Millisecond)).Allowed)
+	assert.False(t, b.Take(limit, now.Add(500*time.Millisecond)).Allowed)
+
+	// Корзина не переполняется сверх емкости
+	later := now.Add(time.Hour)
+	assert.Equal(t, 2, b.Take(limit, later).Remaining)
+	assert.Equal(t, later.Add(500*time.Millisecond), b.FullAt(limit))
+}
+
+func TestBucket_Take_ClockSkew(t *testing.T) {
+	// Arrange
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Now()
+	b := Bucket{Tokens: 0, UpdatedAt: now}
+
+	// Act: часы другой реплики отстают
+	result := b.Take(limit, now.Add(-time.Minute))
+
+	// Assert
+	assert.False(t, result.Allowed)
+	assert.Equal(t, float64(0), b.Tokens)
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+)
+
+// apiKeyColumns - колонки, которые читаются для каждого ключа.
+var apiKeyColumns = []string{"id", "name", "prefix", "key_hash", "scopes", "created_at", "last_used_at", "revoked_at"}
+
+// APIKeyRepo - репозиторий ключей административного API в PostgreSQL.
+type APIKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAPIKeyRepo - конструктор для репозитория.
+func NewAPIKeyRepo(db *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+// CreateAPIKey сохраняет новый ключ и возвращает его с присвоенным id.
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, k apikey.APIKey) (apikey.APIKey, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Insert("api_keys").
+		Columns("name", "prefix", "key_hash", "scopes", "created_at").
+		Values(k.Name, k.Prefix, k.Hash, scopeStrings(k.Scopes), k.CreatedAt).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return apikey.APIKey{}, fmt.Errorf("APIKeyRepo - CreateAPIKey - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&k.ID); err != nil {
+		return apikey.APIKey{}, fmt.Errorf("APIKeyRepo - CreateAPIKey - QueryRow.Scan: %w", err)
+	}
+	return k, nil
+}
+
+// ListAPIKeys возвращает все ключи, включая отозванные, в порядке создания.
+func (r *APIKeyRepo) ListAPIKeys(ctx context.Context) ([]apikey.APIKey, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(apiKeyColumns...).
+		From("api_keys").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - ListAPIKeys - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - ListAPIKeys - Query: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (apikey.APIKey, error) {
+		return scanAPIKey(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - ListAPIKeys - CollectRows: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ. Если действующего ключа с таким id нет, возвращается apikey.ErrNotFound.
+func (r *APIKeyRepo) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	tag, err := r.db.Exec(ctx, "UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL", id, at)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo - RevokeAPIKey - Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apikey.ErrNotFound
+	}
+	return nil
+}
+
+// UseAPIKey находит действующий ключ по хэшу токена и отмечает время его использования.
+// Если ключа нет или он отозван, возвращается apikey.ErrNotFound.
+func (r *APIKeyRepo) UseAPIKey(ctx context.Context, hash string) (apikey.APIKey, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Update("api_keys").
+		Set("last_used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"key_hash": hash}).
+		Where("revoked_at IS NULL").
+		Suffix("RETURNING " + strings.Join(apiKeyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return apikey.APIKey{}, fmt.Errorf("APIKeyRepo - UseAPIKey - ToSql: %w", err)
+	}
+
+	k, err := scanAPIKey(r.db.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apikey.APIKey{}, apikey.ErrNotFound
+	}
+	if err != nil {
+		return apikey.APIKey{}, fmt.Errorf("APIKeyRepo - UseAPIKey - QueryRow.Scan: %w", err)
+	}
+	return k, nil
+}
+
+// scanAPIKey читает ключ из строки с колонками apiKeyColumns.
+func scanAPIKey(row pgx.Row) (apikey.APIKey, error) {
+	var (
+		k                   apikey.APIKey
+		scopes              []string
+		lastUsed, revokedAt *time.Time
+	)
+	if err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.Hash, &scopes, &k.CreatedAt, &lastUsed, &revokedAt); err != nil {
+		return apikey.APIKey{}, err
+	}
+
+	k.Scopes = make([]apikey.Scope, len(scopes))
+	for i, s := range scopes {
+		k.Scopes[i] = apikey.Scope(s)
+	}
+	if lastUsed != nil {
+		k.LastUsedAt = *lastUsed
+	}
+	if revokedAt != nil {
+		k.RevokedAt = *revokedAt
+	}
+	return k, nil
+}
+
+// scopeStrings превращает права в массив строк для колонки TEXT[].
+func scopeStrings(scopes []apikey.Scope) []string {
+	result := make([]string, len(scopes))
+	for i, s := range scopes {
+		result[i] = string(s)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepo(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewAPIKeyRepo(dbPool)
+	key, token, err := apikey.New("ci", []apikey.Scope{apikey.ScopePlantsModerate, apikey.ScopeStatsRead}, time.Now().UTC())
+	require.NoError(t, err)
+
+	// Act
+	created, err := repo.CreateAPIKey(ctx, key)
+	require.NoError(t, err)
+
+	// Assert
+	assert.NotZero(t, created.ID)
+
+	used, err := repo.UseAPIKey(ctx, apikey.HashToken(token))
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, used.ID)
+	assert.Equal(t, key.Scopes, used.Scopes)
+	assert.False(t, used.LastUsedAt.IsZero())
+
+	_, err = repo.UseAPIKey(ctx, apikey.HashToken(token+"x"))
+	assert.ErrorIs(t, err, apikey.ErrNotFound)
+
+	require.NoError(t, repo.RevokeAPIKey(ctx, created.ID, time.Now().UTC()))
+	assert.ErrorIs(t, repo.RevokeAPIKey(ctx, created.ID, time.Now().UTC()), apikey.ErrNotFound, "already revoked")
+	assert.ErrorIs(t, repo.RevokeAPIKey(ctx, 999999, time.Now().UTC()), apikey.ErrNotFound)
+
+	_, err = repo.UseAPIKey(ctx, apikey.HashToken(token))
+	assert.ErrorIs(t, err, apikey.ErrNotFound, "revoked key is rejected")
+
+	keys, err := repo.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ci", keys[0].Name)
+	assert.True(t, keys[0].Revoked())
+	assert.Equal(t, key.Prefix, keys[0].Prefix)
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+)
+
+// idempotencyPruneInterval - как часто IdempotencyStore удаляет истекшие записи.
+const idempotencyPruneInterval = time.Minute
+
+// IdempotencyStore хранит ответы на запросы с ключом идемпотентности в PostgreSQL,
+// чтобы повтор, попавший на другую реплику, тоже получил исходный ответ.
+type IdempotencyStore struct {
+	db *pgxpool.Pool
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewIdempotencyStore - конструктор для хранилища.
+func NewIdempotencyStore(db *pgxpool.Pool) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// Begin занимает ключ или возвращает существующую запись. Истекшая или брошенная запись
+// перезаписывается тем же запросом, так что из параллельных запросов ключ займет только один.
+func (s *IdempotencyStore) Begin(ctx context.Context, keyHash, fingerprint string, ttl time.Duration) (idempotency.Record, bool, error) {
+	defer s.prune(ctx)
+
+	var started bool
+	err := s.db.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (key_hash, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (key_hash) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.status IS NULL AND idempotency_keys.created_at <= NOW() - make_interval(secs => $4))
+		RETURNING true`,
+		keyHash, fingerprint, ttl.Seconds(), idempotency.PendingTimeout.Seconds(),
+	).Scan(&started)
+	if err == nil {
+		return idempotency.Record{Fingerprint: fingerprint}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return idempotency.Record{}, false, fmt.Errorf("IdempotencyStore - Begin - Insert: %w", err)
+	}
+
+	// Ключ занят действующей записью.
+	var (
+		record idempotency.Record
+		status *int
+	)
+	err = s.db.QueryRow(ctx,
+		"SELECT fingerprint, status, body FROM idempotency_keys WHERE key_hash = $1", keyHash,
+	).Scan(&record.Fingerprint, &status, &record.Body)
+	if err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("IdempotencyStore - Begin - QueryRow.Scan: %w", err)
+	}
+	if status != nil {
+		record.Status = *status
+	}
+	return record, false, nil
+}
+
+// Complete сохраняет ответ первого запроса.
+func (s *IdempotencyStore) Complete(ctx context.Context, keyHash string, status int, body []byte) error {
+	_, err := s.db.Exec(ctx,
+		"UPDATE idempotency_keys SET status = $2, body = $3 WHERE key_hash = $1 AND status IS NULL",
+		keyHash, status, body,
+	)
+	if err != nil {
+		return fmt.Errorf("IdempotencyStore - Complete - Exec: %w", err)
+	}
+	return nil
+}
+
+// Release удаляет незавершенную запись.
+func (s *IdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	_, err := s.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE key_hash = $1 AND status IS NULL", keyHash)
+	if err != nil {
+		return fmt.Errorf("IdempotencyStore - Release - Exec: %w", err)
+	}
+	return nil
+}
+
+// prune время от времени удаляет истекшие записи. Ошибка не мешает ответу.
+func (s *IdempotencyStore) prune(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < idempotencyPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+
+	_, _ = s.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at < NOW()")
+}
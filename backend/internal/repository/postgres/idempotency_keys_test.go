@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	store := NewIdempotencyStore(dbPool)
+
+	// Act & Assert: первый запрос занимает ключ, повтор видит незавершенную запись
+	_, started, err := store.Begin(ctx, "a", "fp", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	record, started, err := store.Begin(ctx, "a", "other", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Equal(t, idempotency.Record{Fingerprint: "fp"}, record)
+
+	// После завершения повтор получает ответ
+	require.NoError(t, store.Complete(ctx, "a", 201, []byte("sealed")))
+	record, started, err = store.Begin(ctx, "a", "fp", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Equal(t, idempotency.Record{Fingerprint: "fp", Status: 201, Body: []byte("sealed")}, record)
+
+	// Release освобождает только незавершенную запись
+	require.NoError(t, store.Release(ctx, "a"))
+	_, started, _ = store.Begin(ctx, "a", "fp", time.Hour)
+	assert.False(t, started)
+
+	_, _, err = store.Begin(ctx, "b", "fp", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Release(ctx, "b"))
+	_, started, _ = store.Begin(ctx, "b", "fp", time.Hour)
+	assert.True(t, started)
+
+	// Истекшая запись перезаписывается
+	_, err = dbPool.Exec(ctx, "UPDATE idempotency_keys SET expires_at = NOW() - INTERVAL '1 second' WHERE key_hash = 'a'")
+	require.NoError(t, err)
+	record, started, err = store.Begin(ctx, "a", "new", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, "new", record.Fingerprint)
+}
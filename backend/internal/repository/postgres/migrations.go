@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgUndefinedTable - код ошибки Postgres при обращении к несуществующей таблице.
+const pgUndefinedTable = "42P01"
+
+// MigrationRepo читает версию схемы базы из таблицы goose_db_version, которую ведет goose.
+type MigrationRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewMigrationRepo - конструктор для репозитория.
+func NewMigrationRepo(db *pgxpool.Pool) *MigrationRepo {
+	return &MigrationRepo{db: db}
+}
+
+// Version возвращает версию последней примененной миграции; 0, если миграции еще не применялись.
+func (r *MigrationRepo) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.db.QueryRow(ctx,
+		"SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied",
+	).Scan(&version)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUndefinedTable {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("MigrationRepo - Version - QueryRow.Scan: %w", err)
+	}
+	return version, nil
+}
+
+// LatestMigration возвращает наибольшую версию среди файлов миграций goose в корне fsys,
+// например 20250921121820 для 20250921121820_create_plants_table.sql.
+func LatestMigration(fsys fs.FS) (int64, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return 0, fmt.Errorf("LatestMigration - Glob: %w", err)
+	}
+	if len(names) == 0 {
+		return 0, errors.New("LatestMigration: no migrations found")
+	}
+
+	var latest int64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("LatestMigration: file %s has no version prefix", name)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
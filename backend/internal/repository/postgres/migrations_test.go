@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/heartmarshall/digital-forest/backend/migrations"
+)
+
+func TestMigrationRepo_Version(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewMigrationRepo(dbPool)
+
+	latest, err := LatestMigration(migrations.FS)
+	require.NoError(t, err)
+
+	// Act & Assert: тестовая база создана встроенными миграциями
+	version, err := repo.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+
+	// Act & Assert: без таблицы goose миграций нет
+	_, err = dbPool.Exec(ctx, "DROP TABLE goose_db_version")
+	require.NoError(t, err)
+
+	version, err = repo.Version(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+}
+
+func TestLatestMigration(t *testing.T) {
+	tests := []struct {
+		name            string
+		fsys            fstest.MapFS
+		expectedVersion int64
+		expectedErr     bool
+	}{
+		{
+			name: "latest of many",
+			fsys: fstest.MapFS{
+				"20250921121820_create_plants_table.sql":    {},
+				"20261016200000_add_plant_fingerprints.sql": {},
+				"20250922105315_seed_initial_plants.sql":    {},
+				"README.md":                                 {},
+			},
+			expectedVersion: 20261016200000,
+		},
+		{name: "empty", fsys: fstest.MapFS{}, expectedErr: true},
+		{name: "no version", fsys: fstest.MapFS{"create_plants_table.sql": {}}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			version, err := LatestMigration(tt.fsys)
+
+			// Assert
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, version)
+		})
+	}
+}
+
+func TestLatestMigration_EmbeddedMigrations(t *testing.T) {
+	// Act
+	version, err := LatestMigration(migrations.FS)
+
+	// Assert
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, version, int64(20261016200000))
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// fingerprintDistance - расстояние Хэмминга до отпечатка ($2, $3) в SQL, как domain.Fingerprint.Distance.
+// BIGINT приводится к bit(64) без потери битов, bit_count появился в PostgreSQL 14.
+const fingerprintDistance = "GREATEST(bit_count((ahash # $2)::bit(64)), bit_count((dhash # $3)::bit(64)))"
+
+// FindDuplicate возвращает растение, изображение которого ближе всего к отпечатку fp:
+// совпадающее полностью или отличающееся не больше чем на maxDistance бит.
+// Удаленные растения, растения без отпечатка и само растение excludeID не учитываются. Если похожих нет, возвращается domain.ErrNotFound.
+// Перцептивное сравнение не использует индекс и просматривает всю таблицу, но лес невелик, а хэши - два числа на строку.
+func (r *PlantRepo) FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error) {
+	sql := `SELECT id, ` + fingerprintDistance + ` AS distance, content_hash = $1 AS exact
+		FROM plants
+		WHERE status <> $5 AND id <> $6 AND content_hash IS NOT NULL
+			AND (content_hash = $1 OR ` + fingerprintDistance + ` <= $4)
+		ORDER BY exact DESC, distance, id
+		LIMIT 1`
+
+	var d domain.Duplicate
+	err := r.db.QueryRow(ctx, sql, fp.ContentHash, int64(fp.AHash), int64(fp.DHash), maxDistance, domain.StatusDeleted, excludeID).
+		Scan(&d.PlantID, &d.Distance, &d.Exact)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Duplicate{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Duplicate{}, fmt.Errorf("PlantRepo - FindDuplicate - QueryRow.Scan: %w", err)
+	}
+	return d, nil
+}
+
+// ListFingerprints возвращает отпечатки всех неудаленных растений, у которых они есть, без изображений.
+func (r *PlantRepo) ListFingerprints(ctx context.Context) ([]domain.FingerprintedPlant, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, author, status, image_version, created_at, content_hash, ahash, dhash
+		FROM plants
+		WHERE status <> $1 AND content_hash IS NOT NULL
+		ORDER BY id`, domain.StatusDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ListFingerprints - Query: %w", err)
+	}
+
+	plants, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.FingerprintedPlant, error) {
+		var (
+			p            domain.FingerprintedPlant
+			ahash, dhash int64
+		)
+		err := row.Scan(&p.ID, &p.Author, &p.Status, &p.ImageVersion, &p.CreatedAt, &p.Fingerprint.ContentHash, &ahash, &dhash)
+		p.Fingerprint.AHash, p.Fingerprint.DHash = uint64(ahash), uint64(dhash)
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ListFingerprints - CollectRows: %w", err)
+	}
+	return plants, nil
+}
+
+// ListWithoutFingerprint возвращает до limit растений без отпечатка с id больше afterID, по возрастанию id.
+// Используется для дозаполнения отпечатков у растений, посаженных до их появления.
+func (r *PlantRepo) ListWithoutFingerprint(ctx context.Context, afterID, limit int) ([]domain.Plant, error) {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants").
+		Where("content_hash IS NULL").
+		Where("id > ?", afterID).
+		OrderBy("id").
+		Limit(uint64(limit))
+
+	return r.queryPlants(ctx, query)
+}
+
+// SetFingerprint сохраняет отпечаток растения, не меняя ничего другого. Если растения нет, возвращается domain.ErrNotFound.
+func (r *PlantRepo) SetFingerprint(ctx context.Context, id int, fp domain.Fingerprint) error {
+	contentHash, ahash, dhash := fingerprintValues(fp)
+	tag, err := r.db.Exec(ctx, "UPDATE plants SET content_hash = $2, ahash = $3, dhash = $4 WHERE id = $1",
+		id, contentHash, ahash, dhash)
+	if err != nil {
+		return fmt.Errorf("PlantRepo - SetFingerprint - Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// fingerprintValues превращает отпечаток в значения колонок content_hash, ahash и dhash.
+// Невычисленный отпечаток записывается как NULL, хэши - как BIGINT с тем же набором битов.
+func fingerprintValues(fp domain.Fingerprint) (contentHash, ahash, dhash any) {
+	if fp.IsZero() {
+		return nil, nil, nil
+	}
+	return fp.ContentHash, int64(fp.AHash), int64(fp.DHash)
+}
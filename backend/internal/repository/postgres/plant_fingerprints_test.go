@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlantRepo_FindDuplicate(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+
+	// Старший бит проверяет, что uint64 переживает хранение в BIGINT
+	original := domain.Fingerprint{ContentHash: "original", AHash: 1<<63 | 0xF0, DHash: 1<<63 | 0x0F}
+	created, err := repo.Create(ctx, domain.Plant{Author: "a", ImageData: "data", Fingerprint: original, CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+	assert.Equal(t, original, created.Fingerprint)
+
+	tests := []struct {
+		name        string
+		fingerprint domain.Fingerprint
+		maxDistance int
+		excludeID   int
+		expected    domain.Duplicate
+		notFound    bool
+	}{
+		{
+			name:        "exact copy",
+			fingerprint: original,
+			maxDistance: 0,
+			expected:    domain.Duplicate{PlantID: created.ID, Distance: 0, Exact: true},
+		},
+		{
+			name:        "near copy",
+			fingerprint: domain.Fingerprint{ContentHash: "edited", AHash: original.AHash ^ 0b11, DHash: original.DHash ^ 0b1},
+			maxDistance: 2,
+			expected:    domain.Duplicate{PlantID: created.ID, Distance: 2},
+		},
+		{
+			name:        "too far",
+			fingerprint: domain.Fingerprint{ContentHash: "edited", AHash: original.AHash ^ 0b111, DHash: original.DHash},
+			maxDistance: 2,
+			notFound:    true,
+		},
+		{
+			name:        "exact only",
+			fingerprint: domain.Fingerprint{ContentHash: "edited", AHash: original.AHash, DHash: original.DHash},
+			maxDistance: -1,
+			notFound:    true,
+		},
+		{
+			name:        "plant itself is excluded",
+			fingerprint: original,
+			maxDistance: 0,
+			excludeID:   created.ID,
+			notFound:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			duplicate, err := repo.FindDuplicate(ctx, tt.fingerprint, tt.maxDistance, tt.excludeID)
+
+			// Assert
+			if tt.notFound {
+				assert.ErrorIs(t, err, domain.ErrNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, duplicate)
+		})
+	}
+
+	t.Run("deleted plants are ignored", func(t *testing.T) {
+		// Arrange
+		_, err := dbPool.Exec(ctx, "UPDATE plants SET status = 'deleted' WHERE id = $1", created.ID)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.FindDuplicate(ctx, original, 0, 0)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestPlantRepo_Fingerprints(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+
+	var ids []int
+	for range 3 {
+		p, err := repo.Create(ctx, domain.Plant{Author: "legacy", ImageData: "data", CreatedAt: time.Now().UTC()})
+		require.NoError(t, err)
+		ids = append(ids, p.ID)
+	}
+
+	// Act
+	first, err := repo.ListWithoutFingerprint(ctx, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+
+	fp := domain.Fingerprint{ContentHash: "hash", AHash: 1, DHash: 2}
+	for _, p := range first {
+		require.NoError(t, repo.SetFingerprint(ctx, p.ID, fp))
+	}
+	rest, err := repo.ListWithoutFingerprint(ctx, 0, 10)
+	require.NoError(t, err)
+	listed, err := repo.ListFingerprints(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, []int{ids[0], ids[1]}, []int{first[0].ID, first[1].ID})
+	require.Len(t, rest, 1)
+	assert.Equal(t, ids[2], rest[0].ID)
+	require.Len(t, listed, 2)
+	assert.Equal(t, fp, listed[0].Fingerprint)
+	assert.Equal(t, "legacy", listed[0].Author)
+	assert.ErrorIs(t, repo.SetFingerprint(ctx, ids[2]+1000, fp), domain.ErrNotFound)
+}
@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// PlantCreatedChannel - канал NOTIFY, в который триггер plants_created_notify отправляет id новых растений.
+const PlantCreatedChannel = "plants_created"
+
+const (
+	// defaultMinBackoff и defaultMaxBackoff - границы паузы между попытками переподключения.
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 10 * time.Second
+	// catchUpLimit - сколько пропущенных за время разрыва растений listener дочитывает после переподключения.
+	catchUpLimit = 100
+	// recentSize - сколько последних опубликованных id помнит listener, чтобы не публиковать растение дважды.
+	recentSize = 1024
+)
+
+// PlantPublisher получает растения, о которых сообщил Postgres.
+type PlantPublisher interface {
+	Publish(plant domain.Plant)
+}
+
+// PlantListener слушает уведомления о новых растениях и передает растения в PlantPublisher.
+//
+// Уведомления отправляет триггер на вставку в plants, поэтому listener каждой реплики видит
+// растения, созданные на любой реплике. Для LISTEN используется отдельное соединение, изъятое из пула:
+// соединение в режиме LISTEN не должно возвращаться в пул и попадать к обычным запросам.
+// После потери соединения listener переподключается с экспоненциальной паузой и дочитывает
+// растения, пропущенные за время разрыва.
+type PlantListener struct {
+	db         *pgxpool.Pool
+	repo       *PlantRepo
+	publisher  PlantPublisher
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	started bool // Был ли уже хотя бы один успешный LISTEN
+	lastID  int  // Наибольший опубликованный id, с него начинается дочитывание
+	recent  *recentIDs
+
+	mu        sync.Mutex
+	healthErr error // Почему listener сейчас не слушает канал; nil, пока слушает
+}
+
+// ListenerOption настраивает PlantListener.
+type ListenerOption func(*PlantListener)
+
+// WithReconnectBackoff задает границы паузы между попытками переподключения.
+func WithReconnectBackoff(minBackoff, maxBackoff time.Duration) ListenerOption {
+	return func(l *PlantListener) {
+		l.minBackoff = minBackoff
+		l.maxBackoff = maxBackoff
+	}
+}
+
+// NewPlantListener - конструктор для listener.
+func NewPlantListener(db *pgxpool.Pool, publisher PlantPublisher, opts ...ListenerOption) *PlantListener {
+	l := &PlantListener{
+		db:         db,
+		repo:       NewPlantRepo(db),
+		publisher:  publisher,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		recent:     newRecentIDs(recentSize),
+		healthErr:  errors.New("not started"),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Run слушает уведомления, пока не будет отменен ctx. Ошибки соединения не прерывают работу:
+// listener логирует их и переподключается. Возвращает ctx.Err() после отмены.
+func (l *PlantListener) Run(ctx context.Context) error {
+	backoff := l.minBackoff
+	for {
+		connected, err := l.listen(ctx)
+		l.setHealth(err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			// Соединение успело поработать - следующую попытку начинаем без долгой паузы.
+			backoff = l.minBackoff
+		}
+		logger.FromContext(ctx).Warn("plant listener disconnected", slog.Any("error", err), slog.Duration("backoff", backoff))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, l.maxBackoff)
+	}
+}
+
+// listen выполняет LISTEN на отдельном соединении и обрабатывает уведомления до первой ошибки.
+// connected сообщает, удалось ли подписаться на канал.
+func (l *PlantListener) listen(ctx context.Context) (connected bool, err error) {
+	pooled, err := l.db.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("PlantListener - listen - Acquire: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{PlantCreatedChannel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("PlantListener - listen - LISTEN: %w", err)
+	}
+
+	// Подписка уже действует, так что все, что вставят после этого момента, придет уведомлением.
+	if !l.started {
+		// Растения, созданные до запуска, не публикуем: начинаем с текущего максимума.
+		if err := l.db.QueryRow(ctx, "SELECT COALESCE(MAX(id), 0) FROM plants").Scan(&l.lastID); err != nil {
+			return true, fmt.Errorf("PlantListener - listen - QueryRow.Scan: %w", err)
+		}
+		l.started = true
+	} else if err := l.catchUp(ctx); err != nil {
+		return true, err
+	}
+	l.setHealth(nil)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("PlantListener - listen - WaitForNotification: %w", err)
+		}
+
+		id, err := strconv.Atoi(notification.Payload)
+		if err != nil {
+			logger.FromContext(ctx).Warn("plant listener: unexpected payload", slog.String("payload", notification.Payload))
+			continue
+		}
+		if err := l.publishByID(ctx, id); err != nil {
+			return true, err
+		}
+	}
+}
+
+// Health возвращает ошибку, если listener сейчас не подписан на канал: еще не подключился или переподключается.
+// Пока listener не слушает, новые растения других реплик не попадают в поток.
+func (l *PlantListener) Health() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.healthErr != nil {
+		return fmt.Errorf("plant listener is not listening: %w", l.healthErr)
+	}
+	return nil
+}
+
+func (l *PlantListener) setHealth(err error) {
+	l.mu.Lock()
+	l.healthErr = err
+	l.mu.Unlock()
+}
+
+// catchUp публикует растения, вставленные после последнего опубликованного.
+func (l *PlantListener) catchUp(ctx context.Context) error {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants").
+		Where(sq.Gt{"id": l.lastID}).
+		Where(visible).
+		OrderBy("id").
+		Limit(catchUpLimit)
+
+	plants, err := l.repo.queryPlants(ctx, query)
+	if err != nil {
+		return fmt.Errorf("PlantListener - catchUp: %w", err)
+	}
+	for _, p := range plants {
+		l.publish(p)
+	}
+	return nil
+}
+
+// publishByID загружает растение и публикует его.
+// Растение могли удалить до того, как дошло уведомление, - тогда публиковать нечего.
+func (l *PlantListener) publishByID(ctx context.Context, id int) error {
+	if l.recent.contains(id) {
+		return nil
+	}
+
+	plant, err := l.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("PlantListener - publishByID: %w", err)
+	}
+
+	l.publish(plant)
+	return nil
+}
+
+// publish передает растение дальше, если оно еще не публиковалось.
+func (l *PlantListener) publish(plant domain.Plant) {
+	if l.recent.contains(plant.ID) {
+		return
+	}
+	l.recent.add(plant.ID)
+	l.lastID = max(l.lastID, plant.ID)
+	l.publisher.Publish(plant)
+}
+
+// recentIDs - множество последних size id с вытеснением самых старых.
+type recentIDs struct {
+	ids  map[int]struct{}
+	ring []int
+	next int
+}
+
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{ids: make(map[int]struct{}, size), ring: make([]int, 0, size)}
+}
+
+func (r *recentIDs) contains(id int) bool {
+	_, ok := r.ids[id]
+	return ok
+}
+
+func (r *recentIDs) add(id int) {
+	if len(r.ring) < cap(r.ring) {
+		r.ring = append(r.ring, id)
+	} else {
+		delete(r.ids, r.ring[r.next])
+		r.ring[r.next] = id
+		r.next = (r.next + 1) % len(r.ring)
+	}
+	r.ids[id] = struct{}{}
+}
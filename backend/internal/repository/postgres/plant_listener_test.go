@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startListener запускает listener и ждет, пока он подпишется на канал.
+func startListener(t *testing.T, dbPool *pgxpool.Pool, bus *events.PlantBus) *PlantListener {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := NewPlantListener(dbPool, bus, WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond))
+
+	done := make(chan error, 1)
+	go func() { done <- listener.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	waitForListeners(t, dbPool, 1)
+	require.Eventually(t, func() bool { return listener.Health() == nil }, 10*time.Second, 20*time.Millisecond)
+	return listener
+}
+
+// waitForListeners ждет, пока на канал plants_created подпишутся count соединений.
+func waitForListeners(t *testing.T, dbPool *pgxpool.Pool, count int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		var n int
+		err := dbPool.QueryRow(context.Background(),
+			"SELECT COUNT(*) FROM pg_stat_activity WHERE query LIKE 'LISTEN %' AND state = 'idle'").Scan(&n)
+		return err == nil && n == count
+	}, 10*time.Second, 20*time.Millisecond)
+}
+
+// receive ждет следующее растение из подписки.
+func receive(t *testing.T, sub *events.Subscription) domain.Plant {
+	t.Helper()
+
+	select {
+	case p := <-sub.C:
+		return p
+	case <-time.After(10 * time.Second):
+		t.Fatal("plant was not published")
+		return domain.Plant{}
+	}
+}
+
+func TestPlantListener(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+
+	// Растение, созданное до запуска listener, не публикуется
+	_, err := repo.Create(ctx, domain.Plant{Author: "before", ImageData: "data", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	bus := events.NewPlantBus(16)
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+	listener := startListener(t, dbPool, bus)
+
+	t.Run("insert is published", func(t *testing.T) {
+		// Act
+		created, err := repo.Create(ctx, domain.Plant{Author: "listener_author", ImageData: "data", CreatedAt: time.Now()})
+		require.NoError(t, err)
+
+		// Assert
+		published := receive(t, sub)
+		assert.Equal(t, created.ID, published.ID)
+		assert.Equal(t, "listener_author", published.Author)
+	})
+
+	t.Run("reconnects after connection loss", func(t *testing.T) {
+		// Act: обрываем соединение listener и вставляем растение, пока он переподключается
+		_, err := dbPool.Exec(ctx, "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE query LIKE 'LISTEN %'")
+		require.NoError(t, err)
+
+		missed, err := repo.Create(ctx, domain.Plant{Author: "during_outage", ImageData: "data", CreatedAt: time.Now()})
+		require.NoError(t, err)
+
+		// Assert: пропущенное растение дочитывается, новые снова приходят уведомлениями
+		assert.Equal(t, missed.ID, receive(t, sub).ID)
+
+		waitForListeners(t, dbPool, 1)
+		require.Eventually(t, func() bool { return listener.Health() == nil }, 10*time.Second, 20*time.Millisecond)
+		after, err := repo.Create(ctx, domain.Plant{Author: "after_outage", ImageData: "data", CreatedAt: time.Now()})
+		require.NoError(t, err)
+		assert.Equal(t, after.ID, receive(t, sub).ID)
+
+		// Ни одно растение не опубликовано дважды
+		select {
+		case p := <-sub.C:
+			t.Fatalf("unexpected plant %d published", p.ID)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
+
+func TestPlantListener_ManyReplicas(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	first, second := events.NewPlantBus(4), events.NewPlantBus(4)
+	firstSub, err := first.Subscribe()
+	require.NoError(t, err)
+	secondSub, err := second.Subscribe()
+	require.NoError(t, err)
+
+	startListener(t, dbPool, first)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewPlantListener(dbPool, second).Run(ctx)
+	waitForListeners(t, dbPool, 2)
+
+	// Act
+	created, err := NewPlantRepo(dbPool).Create(context.Background(),
+		domain.Plant{Author: "replica", ImageData: "data", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, created.ID, receive(t, firstSub).ID)
+	assert.Equal(t, created.ID, receive(t, secondSub).ID)
+}
+
+func TestPlantListener_HealthBeforeRun(t *testing.T) {
+	listener := NewPlantListener(nil, events.NewPlantBus(1))
+
+	assert.ErrorContains(t, listener.Health(), "not started")
+}
+
+func TestRecentIDs(t *testing.T) {
+	r := newRecentIDs(2)
+
+	r.add(1)
+	r.add(2)
+	assert.True(t, r.contains(1))
+	assert.True(t, r.contains(2))
+
+	r.add(3)
+	assert.False(t, r.contains(1), "oldest id must be evicted")
+	assert.True(t, r.contains(2))
+	assert.True(t, r.contains(3))
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// moderationColumns - колонки журнала модерации в порядке полей domain.ModerationEvent.
+var moderationColumns = []string{"id", "plant_id", "action", "actor", "reason", "from_status", "to_status", "created_at"}
+
+// Moderate применяет к растению действие модерации и записывает его в журнал в одной транзакции.
+// Изменение выполняется, только если растение все еще находится в статусе event.FromStatus;
+// иначе (растение изменили параллельно или его уже нет) возвращается domain.ErrInvalidTransition.
+// Для domain.ActionPurge растение удаляется, и возвращается его последнее состояние.
+func (r *PlantRepo) Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) // После Commit ничего не делает
+
+	var query sq.Sqlizer
+	if event.Action == domain.ActionPurge {
+		query = sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Delete("plants").
+			Where(sq.Eq{"id": event.PlantID, "status": event.FromStatus}).
+			Suffix("RETURNING " + strings.Join(plantColumns, ", "))
+	} else {
+		query = sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Update("plants").
+			Set("status", event.ToStatus).
+			Set("status_changed_at", event.CreatedAt).
+			Where(sq.Eq{"id": event.PlantID, "status": event.FromStatus}).
+			Suffix("RETURNING " + strings.Join(plantColumns, ", "))
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - ToSql: %w", err)
+	}
+
+	plant, err := scanPlant(tx.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Plant{}, fmt.Errorf("%w: plant %d is no longer in status %q",
+			domain.ErrInvalidTransition, event.PlantID, event.FromStatus)
+	}
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - QueryRow.Scan: %w", err)
+	}
+
+	sql, args, err = sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Insert("moderation_log").
+		Columns("plant_id", "action", "actor", "reason", "from_status", "to_status", "created_at").
+		Values(event.PlantID, event.Action, event.Actor, event.Reason, event.FromStatus, event.ToStatus, event.CreatedAt).
+		ToSql()
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - Exec: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Moderate - Commit: %w", err)
+	}
+	return plant, nil
+}
+
+// ModerationLog возвращает журнал модерации растения в хронологическом порядке.
+// Журнал доступен и для растений, удаленных окончательно.
+func (r *PlantRepo) ModerationLog(ctx context.Context, plantID int) ([]domain.ModerationEvent, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(moderationColumns...).
+		From("moderation_log").
+		Where(sq.Eq{"plant_id": plantID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ModerationLog - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ModerationLog - Query: %w", err)
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ModerationEvent, error) {
+		var e domain.ModerationEvent
+		err := row.Scan(&e.ID, &e.PlantID, &e.Action, &e.Actor, &e.Reason, &e.FromStatus, &e.ToStatus, &e.CreatedAt)
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ModerationLog - CollectRows: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlantRepo_Moderate(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+
+	plant, err := repo.Create(ctx, domain.Plant{Author: "vandal", ImageData: "data", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusVisible, plant.Status)
+
+	event := func(action domain.ModerationAction, from, to domain.Status) domain.ModerationEvent {
+		return domain.ModerationEvent{
+			PlantID:    plant.ID,
+			Action:     action,
+			Actor:      "moderator",
+			Reason:     "offensive",
+			FromStatus: from,
+			ToStatus:   to,
+			CreatedAt:  time.Now().UTC(),
+		}
+	}
+
+	t.Run("hidden plant disappears from public queries", func(t *testing.T) {
+		// Act
+		hidden, err := repo.Moderate(ctx, event(domain.ActionHide, domain.StatusVisible, domain.StatusHidden))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusHidden, hidden.Status)
+
+		_, err = repo.GetByID(ctx, plant.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		random, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 10})
+		require.NoError(t, err)
+		assert.Empty(t, random)
+
+		seed := int64(1)
+		seeded, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 10, Seed: &seed})
+		require.NoError(t, err)
+		assert.Empty(t, seeded)
+
+		listed, err := repo.List(ctx, domain.ListFilter{Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+
+		// Модератор по-прежнему видит растение
+		moderated, err := repo.List(ctx, domain.ListFilter{Limit: 10, Status: domain.StatusHidden})
+		require.NoError(t, err)
+		require.Len(t, moderated, 1)
+		assert.Equal(t, plant.ID, moderated[0].ID)
+
+		found, err := repo.GetByIDAnyStatus(ctx, plant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusHidden, found.Status)
+	})
+
+	t.Run("stale status is rejected", func(t *testing.T) {
+		_, err := repo.Moderate(ctx, event(domain.ActionHide, domain.StatusVisible, domain.StatusHidden))
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+
+	t.Run("restored plant is visible again", func(t *testing.T) {
+		_, err := repo.Moderate(ctx, event(domain.ActionRestore, domain.StatusHidden, domain.StatusVisible))
+		require.NoError(t, err)
+
+		found, err := repo.GetByID(ctx, plant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusVisible, found.Status)
+	})
+
+	t.Run("purge removes plant but keeps the log", func(t *testing.T) {
+		_, err := repo.Moderate(ctx, event(domain.ActionPurge, domain.StatusVisible, domain.StatusDeleted))
+		require.NoError(t, err)
+
+		_, err = repo.GetByIDAnyStatus(ctx, plant.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		log, err := repo.ModerationLog(ctx, plant.ID)
+		require.NoError(t, err)
+		require.Len(t, log, 3)
+		assert.Equal(t, domain.ActionHide, log[0].Action)
+		assert.Equal(t, domain.ActionRestore, log[1].Action)
+		assert.Equal(t, domain.ActionPurge, log[2].Action)
+		for _, e := range log {
+			assert.Equal(t, "moderator", e.Actor)
+			assert.Equal(t, "offensive", e.Reason)
+			assert.False(t, e.CreatedAt.IsZero())
+		}
+	})
+}
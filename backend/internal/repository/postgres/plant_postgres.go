@@ -2,46 +2,80 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	// Убедись, что путь импорта соответствует имени твоего Go-модуля
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
 )
 
+// plantColumns - колонки, которые читаются для каждого растения.
+// image_data может быть NULL у растений, хранящихся в виде сетки.
+var plantColumns = []string{
+	"id", "author", "COALESCE(image_data, '')", "grid", "status", "image_version", "COALESCE(owner_token_hash, '')", "COALESCE(user_id, 0)", "created_at",
+	"COALESCE(content_hash, '')", "COALESCE(ahash, 0)", "COALESCE(dhash, 0)",
+}
+
+// visible - условие для всех публичных запросов: растения в остальных статусах видны только модераторам.
+var visible = sq.Eq{"status": domain.StatusVisible}
+
 // PlantRepo - это реализация usecase.PlantRepository для работы с PostgreSQL.
 type PlantRepo struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	sampling SamplingStrategy
+}
+
+// Option настраивает PlantRepo.
+type Option func(*PlantRepo)
+
+// WithSamplingStrategy задает стратегию выбора случайных растений для GetRandom.
+func WithSamplingStrategy(s SamplingStrategy) Option {
+	return func(r *PlantRepo) {
+		r.sampling = s
+	}
 }
 
 // NewPlantRepo - конструктор для репозитория.
 // Принимает пул соединений с базой данных в качестве зависимости.
-func NewPlantRepo(db *pgxpool.Pool) *PlantRepo {
-	return &PlantRepo{db: db}
+func NewPlantRepo(db *pgxpool.Pool, opts ...Option) *PlantRepo {
+	r := &PlantRepo{db: db, sampling: DefaultSamplingStrategy}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Create реализует метод интерфейса usecase.PlantRepository.
 // Он вставляет новую запись о растении в таблицу "plants".
+// Сетка хранится в компактном бинарном виде (см. domain.Grid.MarshalBinary).
 func (r *PlantRepo) Create(ctx context.Context, plant domain.Plant) (domain.Plant, error) {
+	var grid []byte
+	if plant.Grid != nil {
+		var err error
+		grid, err = plant.Grid.MarshalBinary()
+		if err != nil {
+			return domain.Plant{}, fmt.Errorf("PlantRepo - Create - MarshalBinary: %w", err)
+		}
+	}
+
+	contentHash, ahash, dhash := fingerprintValues(plant.Fingerprint)
 	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
 		Insert("plants").
-		Columns("author", "image_data", "created_at").
-		Values(plant.Author, plant.ImageData, plant.CreatedAt).
-		Suffix("RETURNING id, author, image_data, created_at"). // Возвращаем все поля
+		Columns("author", "image_data", "grid", "status", "owner_token_hash", "user_id", "created_at", "content_hash", "ahash", "dhash").
+		Values(plant.Author, nullIfEmpty(plant.ImageData), grid, statusOrVisible(plant.Status), nullIfEmpty(plant.OwnerTokenHash), nullIfZero(plant.UserID), plant.CreatedAt, contentHash, ahash, dhash).
+		Suffix("RETURNING " + strings.Join(plantColumns, ", ")). // Возвращаем все поля
 		ToSql()
 	if err != nil {
 		return domain.Plant{}, fmt.Errorf("PlantRepo - Create - ToSql: %w", err)
 	}
 
-	var createdPlant domain.Plant
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
-		&createdPlant.ID,
-		&createdPlant.Author,
-		&createdPlant.ImageData,
-		&createdPlant.CreatedAt,
-	)
+	createdPlant, err := scanPlant(r.db.QueryRow(ctx, sql, args...))
 	if err != nil {
 		return domain.Plant{}, fmt.Errorf("PlantRepo - Create - QueryRow.Scan: %w", err)
 	}
@@ -49,41 +83,215 @@ func (r *PlantRepo) Create(ctx context.Context, plant domain.Plant) (domain.Plan
 }
 
 // GetRandom реализует метод интерфейса usecase.PlantRepository.
-// Он извлекает случайные записи из таблицы "plants".
-func (r *PlantRepo) GetRandom(ctx context.Context, count int) ([]domain.Plant, error) {
+// Без зерна он извлекает случайные записи из таблицы "plants" выбранной стратегией (см. SamplingStrategy),
+// с зерном - очередную страницу детерминированного перемешанного порядка (см. sampleSeeded).
+// Растения из filter.Exclude не возвращаются.
+func (r *PlantRepo) GetRandom(ctx context.Context, filter domain.RandomFilter) ([]domain.Plant, error) {
+	if filter.Count <= 0 {
+		return []domain.Plant{}, nil
+	}
+	if filter.Seed != nil {
+		return r.sampleSeeded(ctx, *filter.Seed, filter.AfterID, filter.Count, filter.Exclude)
+	}
+
+	switch r.sampling {
+	case SamplingTableSample:
+		return r.sampleTableSample(ctx, filter.Count, filter.Exclude)
+	case SamplingIDProbe:
+		return r.sampleIDProbe(ctx, filter.Count, filter.Exclude)
+	default:
+		return r.sampleOrderByRandom(ctx, filter.Count, filter.Exclude)
+	}
+}
+
+// GetByID возвращает видимое растение по идентификатору.
+// Если растения нет или оно не видно публично, возвращается domain.ErrNotFound.
+func (r *PlantRepo) GetByID(ctx context.Context, id int) (domain.Plant, error) {
+	return r.getByID(ctx, sq.And{sq.Eq{"id": id}, visible})
+}
+
+// GetByIDAnyStatus возвращает растение по идентификатору независимо от статуса.
+// Используется модерацией. Если растения нет, возвращается domain.ErrNotFound.
+func (r *PlantRepo) GetByIDAnyStatus(ctx context.Context, id int) (domain.Plant, error) {
+	return r.getByID(ctx, sq.Eq{"id": id})
+}
+
+// getByID возвращает одно растение, удовлетворяющее условию.
+func (r *PlantRepo) getByID(ctx context.Context, where sq.Sqlizer) (domain.Plant, error) {
 	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id", "author", "image_data", "created_at").
+		Select(plantColumns...).
 		From("plants").
-		OrderBy("RANDOM()"). // ORDER BY RANDOM() - простой, но потенциально медленный способ для очень больших таблиц.
-		Limit(uint64(count)).
+		Where(where).
 		ToSql()
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - GetByID - ToSql: %w", err)
+	}
 
+	plant, err := scanPlant(r.db.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Plant{}, domain.ErrNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("PlantRepo - GetRandom - ToSql: %w", err)
+		return domain.Plant{}, fmt.Errorf("PlantRepo - GetByID - QueryRow.Scan: %w", err)
 	}
+	return plant, nil
+}
+
+// Update применяет изменения автора к видимому растению и возвращает его новое состояние.
+// Новая сетка заменяет и старый PNG, а версия изображения увеличивается, чтобы сменился его URL.
+// Вместе с сеткой сохраняется ее отпечаток из update.Fingerprint.
+// Если растения нет или оно не видно публично, возвращается domain.ErrNotFound.
+func (r *PlantRepo) Update(ctx context.Context, id int, update domain.PlantUpdate) (domain.Plant, error) {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Update("plants").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.And{sq.Eq{"id": id}, visible}).
+		Suffix("RETURNING " + strings.Join(plantColumns, ", "))
 
-	// Выполняем запрос для получения нескольких строк.
-	rows, err := r.db.Query(ctx, sql, args...)
+	if update.Author != nil {
+		query = query.Set("author", *update.Author)
+	}
+	if update.Grid != nil {
+		grid, err := update.Grid.MarshalBinary()
+		if err != nil {
+			return domain.Plant{}, fmt.Errorf("PlantRepo - Update - MarshalBinary: %w", err)
+		}
+		contentHash, ahash, dhash := fingerprintValues(update.Fingerprint)
+		query = query.
+			Set("grid", grid).
+			Set("image_data", nil).
+			Set("image_version", sq.Expr("image_version + 1")).
+			Set("content_hash", contentHash).
+			Set("ahash", ahash).
+			Set("dhash", dhash)
+	}
+	if update.Status != "" {
+		query = query.
+			Set("status", update.Status).
+			Set("status_changed_at", sq.Expr("NOW()"))
+	}
+
+	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("PlantRepo - GetRandom - Query: %w", err)
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Update - ToSql: %w", err)
+	}
+
+	plant, err := scanPlant(r.db.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Plant{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Plant{}, fmt.Errorf("PlantRepo - Update - QueryRow.Scan: %w", err)
+	}
+	return plant, nil
+}
+
+// List возвращает страницу растений, отсортированных по (created_at, id) по убыванию.
+// Используется keyset-пагинация: вместо OFFSET условие строится по курсору предыдущей страницы.
+func (r *PlantRepo) List(ctx context.Context, filter domain.ListFilter) ([]domain.Plant, error) {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants").
+		Where(sq.Eq{"status": statusOrVisible(filter.Status)}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit))
+
+	if filter.Author != "" {
+		query = query.Where(sq.Eq{"author": filter.Author})
+	}
+	if filter.UserID > 0 {
+		query = query.Where(sq.Eq{"user_id": filter.UserID})
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where(sq.GtOrEq{"created_at": filter.CreatedFrom})
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where(sq.Lt{"created_at": filter.CreatedTo})
+	}
+	if filter.After != nil {
+		// Сравнение кортежей позволяет использовать индекс по (created_at, id).
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
 	}
-	defer rows.Close()
 
-	plants := make([]domain.Plant, 0, count)
+	return r.queryPlants(ctx, query)
+}
 
-	// Итерируемся по результатам и сканируем каждую строку в структуру domain.Plant.
+// CountCreatedSince возвращает число растений в любом статусе, посаженных начиная с since.
+func (r *PlantRepo) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM plants WHERE created_at >= $1", since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("PlantRepo - CountCreatedSince - QueryRow.Scan: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatus возвращает число растений в каждом статусе. Статусов без растений в результате нет.
+func (r *PlantRepo) CountByStatus(ctx context.Context) (map[domain.Status]int, error) {
+	rows, err := r.db.Query(ctx, "SELECT status, COUNT(*) FROM plants GROUP BY status")
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - CountByStatus - Query: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.Status]int)
 	for rows.Next() {
-		var p domain.Plant
-		if err := rows.Scan(&p.ID, &p.Author, &p.ImageData, &p.CreatedAt); err != nil {
-			return nil, fmt.Errorf("PlantRepo - GetRandom - rows.Scan: %w", err)
+		var (
+			status domain.Status
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("PlantRepo - CountByStatus - rows.Scan: %w", err)
 		}
-		plants = append(plants, p)
+		counts[status] = count
 	}
-
-	// Проверяем на наличие ошибок, которые могли возникнуть во время итерации.
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("PlantRepo - GetRandom - rows.Err: %w", err)
+		return nil, fmt.Errorf("PlantRepo - CountByStatus - rows.Err: %w", err)
 	}
+	return counts, nil
+}
 
-	return plants, nil
+// scanPlant сканирует строку с колонками plantColumns в domain.Plant.
+func scanPlant(row pgx.Row) (domain.Plant, error) {
+	var (
+		p            domain.Plant
+		grid         []byte
+		ahash, dhash int64
+	)
+	if err := row.Scan(&p.ID, &p.Author, &p.ImageData, &grid, &p.Status, &p.ImageVersion, &p.OwnerTokenHash, &p.UserID, &p.CreatedAt,
+		&p.Fingerprint.ContentHash, &ahash, &dhash); err != nil {
+		return domain.Plant{}, err
+	}
+	p.Fingerprint.AHash, p.Fingerprint.DHash = uint64(ahash), uint64(dhash)
+
+	if grid != nil {
+		p.Grid = &domain.Grid{}
+		if err := p.Grid.UnmarshalBinary(grid); err != nil {
+			return domain.Plant{}, err
+		}
+	}
+	return p, nil
+}
+
+// statusOrVisible подставляет статус по умолчанию для новых растений и фильтров.
+func statusOrVisible(s domain.Status) domain.Status {
+	if s == "" {
+		return domain.StatusVisible
+	}
+	return s
+}
+
+// nullIfEmpty превращает пустую строку в NULL.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullIfZero превращает нулевой идентификатор в NULL.
+func nullIfZero(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
 }
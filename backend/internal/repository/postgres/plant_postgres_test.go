@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"image/color"
 	"strings"
 	"testing"
 	"time"
@@ -74,6 +75,171 @@ func TestPlantRepo_Create(t *testing.T) {
 	}
 }
 
+func TestPlantRepo_CreateWithGrid(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	repo := NewPlantRepo(dbPool)
+	ctx := context.Background()
+
+	grid := &domain.Grid{
+		Width:   2,
+		Height:  2,
+		Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+		Pixels:  []uint8{0, 1, 1, 0},
+	}
+
+	// Act
+	created, err := repo.Create(ctx, domain.Plant{Author: "grid_author", Grid: grid, CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	plants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 10})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, grid, created.Grid)
+	assert.Empty(t, created.ImageData)
+	require.Len(t, plants, 1)
+	assert.Equal(t, grid, plants[0].Grid)
+
+	// Растение без изображения и без сетки сохранить нельзя
+	_, err = repo.Create(ctx, domain.Plant{Author: "no_image", CreatedAt: time.Now().UTC()})
+	assert.Error(t, err)
+}
+
+func TestPlantRepo_GetByID(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	repo := NewPlantRepo(dbPool)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, domain.Plant{Author: "author", ImageData: "data", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	t.Run("existing plant", func(t *testing.T) {
+		plant, err := repo.GetByID(ctx, created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, created, plant)
+	})
+
+	t.Run("missing plant", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, created.ID+1000)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestPlantRepo_CountCreatedSince(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	repo := NewPlantRepo(dbPool)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, age := range []time.Duration{time.Hour, 5 * time.Minute, time.Minute} {
+		_, err := repo.Create(ctx, domain.Plant{Author: "author", ImageData: "data", CreatedAt: now.Add(-age)})
+		require.NoError(t, err)
+	}
+
+	// Act
+	count, err := repo.CountCreatedSince(ctx, now.Add(-10*time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestPlantRepo_CountByStatus(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	repo := NewPlantRepo(dbPool)
+	ctx := context.Background()
+
+	for _, status := range []domain.Status{domain.StatusVisible, domain.StatusVisible, domain.StatusPending} {
+		_, err := repo.Create(ctx, domain.Plant{Author: "author", ImageData: "data", Status: status})
+		require.NoError(t, err)
+	}
+
+	// Act
+	counts, err := repo.CountByStatus(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.Status]int{domain.StatusVisible: 2, domain.StatusPending: 1}, counts)
+}
+
+func TestPlantRepo_List(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	repo := NewPlantRepo(dbPool)
+	ctx := context.Background()
+
+	base := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	created := make([]domain.Plant, 0, 5)
+	for i := 0; i < 5; i++ {
+		author := "author_a"
+		if i%2 == 1 {
+			author = "author_b"
+		}
+		// Два последних растения создаются в одну и ту же секунду, порядок между ними задает id
+		createdAt := base.Add(time.Duration(min(i, 3)) * time.Hour)
+		p, err := repo.Create(ctx, domain.Plant{Author: author, ImageData: "data", CreatedAt: createdAt})
+		require.NoError(t, err)
+		created = append(created, p)
+	}
+
+	ids := func(plants []domain.Plant) []int {
+		result := make([]int, 0, len(plants))
+		for _, p := range plants {
+			result = append(result, p.ID)
+		}
+		return result
+	}
+
+	t.Run("walk all pages", func(t *testing.T) {
+		var (
+			all   []int
+			after *domain.Cursor
+		)
+		for {
+			page, err := repo.List(ctx, domain.ListFilter{Limit: 2, After: after})
+			require.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			all = append(all, ids(page)...)
+			last := page[len(page)-1]
+			after = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+
+		assert.Equal(t, []int{created[4].ID, created[3].ID, created[2].ID, created[1].ID, created[0].ID}, all)
+	})
+
+	t.Run("filter by author", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.ListFilter{Author: "author_b", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []int{created[3].ID, created[1].ID}, ids(page))
+	})
+
+	t.Run("filter by created_at range", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.ListFilter{
+			CreatedFrom: base.Add(time.Hour),
+			CreatedTo:   base.Add(3 * time.Hour),
+			Limit:       10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{created[2].ID, created[1].ID}, ids(page))
+	})
+}
+
 func TestPlantRepo_GetRandom(t *testing.T) {
 	// Arrange
 	dbPool, _, container := testutil.SetupTestDB(t)
@@ -125,7 +291,7 @@ func TestPlantRepo_GetRandom(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Act
-			result, err := repo.GetRandom(ctx, tt.count)
+			result, err := repo.GetRandom(ctx, domain.RandomFilter{Count: tt.count})
 
 			// Assert
 			assert.NoError(t, err)
@@ -166,7 +332,7 @@ func TestPlantRepo_Integration(t *testing.T) {
 		}
 
 		// Retrieve random plants
-		randomPlants, err := repo.GetRandom(ctx, 2)
+		randomPlants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 2})
 		require.NoError(t, err)
 		assert.Len(t, randomPlants, 2)
 
@@ -214,7 +380,7 @@ func TestPlantRepo_Integration(t *testing.T) {
 		}
 
 		// Verify all plants were created
-		plants, err := repo.GetRandom(ctx, 10)
+		plants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 10})
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, len(plants), 5)
 	})
@@ -234,7 +400,7 @@ func TestPlantRepo_EdgeCases(t *testing.T) {
 		require.NoError(t, err)
 
 		// Try to get random plants
-		plants, err := repo.GetRandom(ctx, 5)
+		plants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 5})
 		assert.NoError(t, err)
 		assert.Empty(t, plants)
 	})
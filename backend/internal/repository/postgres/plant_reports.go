@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// pgForeignKeyViolation - код ошибки Postgres при нарушении внешнего ключа.
+const pgForeignKeyViolation = "23503"
+
+// CreateReport сохраняет жалобу и возвращает число открытых жалоб на растение вместе с ней.
+// Если у посетителя уже есть открытая жалоба на это растение, возвращается domain.ErrAlreadyReported,
+// если растения нет - domain.ErrNotFound.
+func (r *PlantRepo) CreateReport(ctx context.Context, report domain.Report) (int, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Insert("plant_reports").
+		Columns("plant_id", "reason", "reporter_hash", "created_at").
+		Values(report.PlantID, report.Reason, report.ReporterHash, report.CreatedAt).
+		Suffix("ON CONFLICT (plant_id, reporter_hash) WHERE resolved_at IS NULL DO NOTHING RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - CreateReport - ToSql: %w", err)
+	}
+
+	var id int
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, domain.ErrAlreadyReported
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return 0, domain.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - CreateReport - QueryRow.Scan: %w", err)
+	}
+
+	var open int
+	err = r.db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM plant_reports WHERE plant_id = $1 AND resolved_at IS NULL", report.PlantID,
+	).Scan(&open)
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - CreateReport - Count: %w", err)
+	}
+	return open, nil
+}
+
+// ReportQueue возвращает растения с открытыми жалобами: сначала те, на которые жалуются чаще,
+// при равенстве - с более свежими жалобами.
+func (r *PlantRepo) ReportQueue(ctx context.Context, limit int) ([]domain.ReportSummary, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("r.plant_id", "p.status", "COUNT(*)", "MIN(r.created_at)", "MAX(r.created_at)", "array_agg(r.reason)").
+		From("plant_reports r").
+		Join("plants p ON p.id = r.plant_id").
+		Where("r.resolved_at IS NULL").
+		GroupBy("r.plant_id", "p.status").
+		OrderBy("COUNT(*) DESC", "MAX(r.created_at) DESC", "r.plant_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ReportQueue - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ReportQueue - Query: %w", err)
+	}
+
+	queue, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ReportSummary, error) {
+		var s domain.ReportSummary
+		var reasons []string
+		if err := row.Scan(&s.PlantID, &s.PlantStatus, &s.Count, &s.FirstAt, &s.LastAt, &reasons); err != nil {
+			return s, err
+		}
+		s.Reasons = make(map[domain.ReportReason]int, len(reasons))
+		for _, reason := range reasons {
+			s.Reasons[domain.ReportReason(reason)]++
+		}
+		return s, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - ReportQueue - CollectRows: %w", err)
+	}
+	return queue, nil
+}
+
+// ResolveReports закрывает все открытые жалобы на растение и возвращает их число.
+func (r *PlantRepo) ResolveReports(ctx context.Context, plantID int, at time.Time) (int, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Update("plant_reports").
+		Set("resolved_at", at).
+		Where(sq.Eq{"plant_id": plantID}).
+		Where("resolved_at IS NULL").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - ResolveReports - ToSql: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - ResolveReports - Exec: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlantRepo_Reports(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+
+	first, err := repo.Create(ctx, domain.Plant{Author: "first", ImageData: "data", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	second, err := repo.Create(ctx, domain.Plant{Author: "second", ImageData: "data", CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	report := func(plantID int, reason domain.ReportReason, reporter string) (int, error) {
+		return repo.CreateReport(ctx, domain.Report{
+			PlantID:      plantID,
+			Reason:       reason,
+			ReporterHash: reporter,
+			CreatedAt:    time.Now().UTC(),
+		})
+	}
+
+	t.Run("reports are counted and deduplicated per reporter", func(t *testing.T) {
+		// Act & Assert
+		open, err := report(first.ID, domain.ReasonSpam, "a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, open)
+
+		open, err = report(first.ID, domain.ReasonOffensive, "b")
+		require.NoError(t, err)
+		assert.Equal(t, 2, open)
+
+		_, err = report(first.ID, domain.ReasonOther, "a")
+		assert.ErrorIs(t, err, domain.ErrAlreadyReported)
+
+		open, err = report(second.ID, domain.ReasonSpam, "a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, open)
+	})
+
+	t.Run("missing plant", func(t *testing.T) {
+		_, err := report(999999, domain.ReasonSpam, "a")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("queue groups open reports by plant", func(t *testing.T) {
+		// Act
+		queue, err := repo.ReportQueue(ctx, 10)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, queue, 2)
+		assert.Equal(t, first.ID, queue[0].PlantID)
+		assert.Equal(t, domain.StatusVisible, queue[0].PlantStatus)
+		assert.Equal(t, 2, queue[0].Count)
+		assert.Equal(t, map[domain.ReportReason]int{domain.ReasonSpam: 1, domain.ReasonOffensive: 1}, queue[0].Reasons)
+		assert.Equal(t, second.ID, queue[1].PlantID)
+	})
+
+	t.Run("resolved reports leave the queue and can be filed again", func(t *testing.T) {
+		// Act
+		resolved, err := repo.ResolveReports(ctx, first.ID, time.Now().UTC())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, resolved)
+
+		queue, err := repo.ReportQueue(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, queue, 1)
+		assert.Equal(t, second.ID, queue[0].PlantID)
+
+		open, err := report(first.ID, domain.ReasonSpam, "a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, open)
+	})
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// SamplingStrategy определяет, каким способом PlantRepo.GetRandom выбирает случайные растения.
+// Все стратегии возвращают различные растения, каждое из которых выбрано равновероятно.
+type SamplingStrategy string
+
+const (
+	// SamplingOrderByRandom - ORDER BY RANDOM(): просто, но сортирует всю таблицу на каждый запрос.
+	SamplingOrderByRandom SamplingStrategy = "order_by_random"
+	// SamplingTableSample - TABLESAMPLE BERNOULLI с процентом, рассчитанным по оценке числа строк.
+	// Если выборка оказалась меньше нужной, используется ORDER BY RANDOM().
+	SamplingTableSample SamplingStrategy = "tablesample"
+	// SamplingIDProbe - случайные id из диапазона [min(id), max(id)] проверяются по первичному ключу.
+	// Пропуски в нумерации просто не дают попаданий; недобор добирается через ORDER BY RANDOM().
+	SamplingIDProbe SamplingStrategy = "id_probe"
+)
+
+// DefaultSamplingStrategy - стратегия, которая используется, если в конфигурации ничего не задано.
+const DefaultSamplingStrategy = SamplingIDProbe
+
+const (
+	// smallTableRows - на таблицах меньше этого размера ORDER BY RANDOM() быстрее любых ухищрений.
+	smallTableRows = 1000
+	// sampleOversample - во сколько раз больше строк, чем нужно, пытается выбрать TABLESAMPLE и id_probe.
+	sampleOversample = 3
+	// maxProbeRounds - сколько раз id_probe генерирует новые id, прежде чем перейти к ORDER BY RANDOM().
+	maxProbeRounds = 4
+)
+
+// ParseSamplingStrategy проверяет название стратегии из конфигурации.
+// Пустая строка означает DefaultSamplingStrategy.
+func ParseSamplingStrategy(s string) (SamplingStrategy, error) {
+	switch strategy := SamplingStrategy(s); strategy {
+	case "":
+		return DefaultSamplingStrategy, nil
+	case SamplingOrderByRandom, SamplingTableSample, SamplingIDProbe:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown sampling strategy %q", s)
+	}
+}
+
+// sampleOrderByRandom выбирает count случайных растений сортировкой всей таблицы.
+// Растения из exclude пропускаются.
+func (r *PlantRepo) sampleOrderByRandom(ctx context.Context, count int, exclude []int) ([]domain.Plant, error) {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants").
+		Where(visible).
+		OrderBy("RANDOM()").
+		Limit(uint64(count))
+	if len(exclude) > 0 {
+		query = query.Where("id <> ALL(?)", exclude)
+	}
+
+	return r.queryPlants(ctx, query)
+}
+
+// sampleTableSample выбирает count случайных растений из выборки TABLESAMPLE BERNOULLI.
+// BERNOULLI включает каждую строку независимо с одной и той же вероятностью, поэтому выборка равномерна.
+// Исключенные растения отбрасываются уже после выборки, поэтому в процент закладываем и их.
+func (r *PlantRepo) sampleTableSample(ctx context.Context, count int, exclude []int) ([]domain.Plant, error) {
+	estimate, err := r.estimateRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if estimate < smallTableRows {
+		return r.sampleOrderByRandom(ctx, count, exclude)
+	}
+
+	percent := min(100, float64((count+len(exclude))*sampleOversample)*100/float64(estimate))
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants TABLESAMPLE BERNOULLI (" + strconv.FormatFloat(percent, 'f', 6, 64) + ")").
+		Where(visible).
+		OrderBy("RANDOM()").
+		Limit(uint64(count))
+	if len(exclude) > 0 {
+		query = query.Where("id <> ALL(?)", exclude)
+	}
+
+	plants, err := r.queryPlants(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(plants) < count {
+		// Не повезло с выборкой или статистика устарела.
+		return r.sampleOrderByRandom(ctx, count, exclude)
+	}
+	return plants, nil
+}
+
+// sampleIDProbe выбирает count случайных растений, подбирая случайные id в диапазоне первичного ключа.
+// Каждый id выбирается равновероятно, поэтому каждое существующее растение тоже попадает равновероятно.
+// Исключенные id просто не запрашиваются.
+func (r *PlantRepo) sampleIDProbe(ctx context.Context, count int, exclude []int) ([]domain.Plant, error) {
+	var minID, maxID *int
+	if err := r.db.QueryRow(ctx, "SELECT MIN(id), MAX(id) FROM plants").Scan(&minID, &maxID); err != nil {
+		return nil, fmt.Errorf("PlantRepo - sampleIDProbe - QueryRow.Scan: %w", err)
+	}
+	if minID == nil {
+		return []domain.Plant{}, nil
+	}
+
+	span := *maxID - *minID + 1
+	if span < smallTableRows {
+		return r.sampleOrderByRandom(ctx, count, exclude)
+	}
+
+	skip := make(map[int]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	found := make(map[int]domain.Plant, count)
+	for round := 0; round < maxProbeRounds && len(found) < count; round++ {
+		need := count - len(found)
+		ids := make([]int, 0, need*sampleOversample)
+		for len(ids) < need*sampleOversample {
+			id := *minID + rand.IntN(span)
+			if _, ok := found[id]; !ok && !skip[id] {
+				ids = append(ids, id)
+			}
+		}
+
+		query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Select(plantColumns...).
+			From("plants").
+			Where("id = ANY(?)", ids).
+			Where(visible)
+		plants, err := r.queryPlants(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		// Порядок id в ids случаен, а порядок строк в ответе - нет, поэтому перемешиваем перед отбором.
+		rand.Shuffle(len(plants), func(i, j int) { plants[i], plants[j] = plants[j], plants[i] })
+		for _, p := range plants {
+			if len(found) == count {
+				break
+			}
+			found[p.ID] = p
+		}
+	}
+
+	result := make([]domain.Plant, 0, count)
+	seen := append(make([]int, 0, len(exclude)+len(found)), exclude...)
+	for id, p := range found {
+		result = append(result, p)
+		seen = append(seen, id)
+	}
+
+	if len(result) < count {
+		// Таблица слишком разрежена, исключено слишком много или растений меньше, чем просили.
+		rest, err := r.sampleOrderByRandom(ctx, count-len(result), seen)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rest...)
+	}
+
+	rand.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
+	return result, nil
+}
+
+// sampleSeeded возвращает до count растений из перемешанного порядка, который однозначно задается зерном.
+// Порядок - сортировка по hashtextextended(id, seed), поэтому одно и то же зерно всегда дает один и тот же лес,
+// а новые растения встают в него на свои места, не сдвигая уже просмотренные страницы.
+// afterID - последнее растение предыдущей страницы; его позиция вычисляется заново,
+// так что курсор остается корректным, даже если это растение успели удалить.
+//
+// Ранг зависит от зерна, и индекса под него быть не может: каждая страница вычисляет хэш всех видимых растений
+// и выбирает из них count первых (top-N heapsort, без полной сортировки), то есть стоит O(n) от размера леса,
+// как и SamplingOrderByRandom. Для ленты, которую листают страницами, это приемлемо; стоимость на большой таблице
+// показывает BenchmarkPlantRepo_GetRandom/seeded_*.
+func (r *PlantRepo) sampleSeeded(ctx context.Context, seed int64, afterID, count int, exclude []int) ([]domain.Plant, error) {
+	// Зерно - целое число, поэтому его можно безопасно подставить прямо в текст запроса.
+	rank := "hashtextextended(id::text, " + strconv.FormatInt(seed, 10) + ")"
+
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(plantColumns...).
+		From("plants").
+		Where(visible).
+		OrderBy(rank, "id").
+		Limit(uint64(count))
+	if afterID > 0 {
+		query = query.Where("("+rank+", id) > (hashtextextended(?, ?), ?)", strconv.Itoa(afterID), seed, afterID)
+	}
+	if len(exclude) > 0 {
+		query = query.Where("id <> ALL(?)", exclude)
+	}
+
+	return r.queryPlants(ctx, query)
+}
+
+// estimateRows возвращает оценку числа строк в таблице plants по статистике планировщика.
+// Если статистика еще не собиралась, возвращается 0.
+func (r *PlantRepo) estimateRows(ctx context.Context) (int64, error) {
+	var estimate float64
+	err := r.db.QueryRow(ctx, "SELECT reltuples FROM pg_class WHERE oid = 'plants'::regclass").Scan(&estimate)
+	if err != nil {
+		return 0, fmt.Errorf("PlantRepo - estimateRows - QueryRow.Scan: %w", err)
+	}
+	return max(0, int64(estimate)), nil
+}
+
+// queryPlants выполняет запрос, возвращающий колонки plantColumns.
+func (r *PlantRepo) queryPlants(ctx context.Context, query sq.SelectBuilder) ([]domain.Plant, error) {
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - queryPlants - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - queryPlants - Query: %w", err)
+	}
+
+	plants, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Plant, error) {
+		return scanPlant(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlantRepo - queryPlants - CollectRows: %w", err)
+	}
+	return plants, nil
+}
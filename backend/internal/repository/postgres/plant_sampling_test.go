@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var samplingStrategies = []SamplingStrategy{SamplingOrderByRandom, SamplingTableSample, SamplingIDProbe}
+
+// seedPlants быстро вставляет count растений и удаляет каждое gapEvery-е, чтобы в id были пропуски.
+func seedPlants(tb testing.TB, db *pgxpool.Pool, count, gapEvery int) {
+	tb.Helper()
+	ctx := context.Background()
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO plants (author, image_data, created_at)
+		SELECT 'seed_' || g, 'data_' || g, NOW()
+		FROM generate_series(1, $1) AS g`, count)
+	require.NoError(tb, err)
+
+	if gapEvery > 0 {
+		_, err = db.Exec(ctx, "DELETE FROM plants WHERE id % $1 = 0", gapEvery)
+		require.NoError(tb, err)
+	}
+
+	// Обновляем статистику, на которую опирается TABLESAMPLE.
+	_, err = db.Exec(ctx, "ANALYZE plants")
+	require.NoError(tb, err)
+}
+
+func TestParseSamplingStrategy(t *testing.T) {
+	for _, s := range samplingStrategies {
+		parsed, err := ParseSamplingStrategy(string(s))
+		assert.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	parsed, err := ParseSamplingStrategy("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSamplingStrategy, parsed)
+
+	_, err = ParseSamplingStrategy("magic")
+	assert.Error(t, err)
+}
+
+func TestPlantRepo_GetRandomStrategies(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+
+	tableSizes := []struct {
+		name     string
+		count    int
+		gapEvery int
+	}{
+		{name: "small table", count: 30, gapEvery: 0},
+		{name: "large sparse table", count: 20000, gapEvery: 3},
+	}
+
+	for _, size := range tableSizes {
+		require.NoError(t, testutil.TruncateTables(ctx, dbPool))
+		seedPlants(t, dbPool, size.count, size.gapEvery)
+
+		var total int
+		require.NoError(t, dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM plants").Scan(&total))
+
+		for _, strategy := range samplingStrategies {
+			t.Run(fmt.Sprintf("%s/%s", size.name, strategy), func(t *testing.T) {
+				repo := NewPlantRepo(dbPool, WithSamplingStrategy(strategy))
+
+				for _, count := range []int{1, 15, 50} {
+					// Act
+					plants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: count})
+
+					// Assert
+					require.NoError(t, err)
+					assert.Len(t, plants, min(count, total))
+
+					seen := make(map[int]bool, len(plants))
+					for _, p := range plants {
+						assert.False(t, seen[p.ID], "plant %d returned twice", p.ID)
+						seen[p.ID] = true
+						if size.gapEvery > 0 {
+							assert.NotZero(t, p.ID%size.gapEvery, "deleted plant %d returned", p.ID)
+						}
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("empty table", func(t *testing.T) {
+		require.NoError(t, testutil.TruncateTables(ctx, dbPool))
+		for _, strategy := range samplingStrategies {
+			plants, err := NewPlantRepo(dbPool, WithSamplingStrategy(strategy)).GetRandom(ctx, domain.RandomFilter{Count: 10})
+			assert.NoError(t, err)
+			assert.Empty(t, plants)
+		}
+	})
+}
+
+func TestPlantRepo_GetRandomExclude(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	seedPlants(t, dbPool, 5000, 0)
+
+	var exclude []int
+	require.NoError(t, dbPool.QueryRow(ctx, "SELECT array_agg(id) FROM plants WHERE id % 2 = 0").Scan(&exclude))
+
+	for _, strategy := range samplingStrategies {
+		t.Run(string(strategy), func(t *testing.T) {
+			repo := NewPlantRepo(dbPool, WithSamplingStrategy(strategy))
+
+			// Act
+			plants, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 30, Exclude: exclude})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Len(t, plants, 30)
+			for _, p := range plants {
+				assert.NotZero(t, p.ID%2, "excluded plant %d returned", p.ID)
+			}
+		})
+	}
+}
+
+func TestPlantRepo_GetRandomSeeded(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewPlantRepo(dbPool)
+	seedPlants(t, dbPool, 100, 0)
+
+	seed := int64(20231016)
+	otherSeed := int64(-1)
+
+	// Act: один и тот же порядок целиком и постранично
+	whole, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 100, Seed: &seed})
+	require.NoError(t, err)
+	again, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 100, Seed: &seed})
+	require.NoError(t, err)
+	other, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 100, Seed: &otherSeed})
+	require.NoError(t, err)
+
+	var paged []domain.Plant
+	afterID := 0
+	for {
+		page, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 30, Seed: &seed, AfterID: afterID})
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		afterID = page[len(page)-1].ID
+	}
+
+	// Assert
+	ids := func(plants []domain.Plant) []int {
+		result := make([]int, len(plants))
+		for i, p := range plants {
+			result[i] = p.ID
+		}
+		return result
+	}
+	require.Len(t, whole, 100)
+	assert.Equal(t, ids(whole), ids(again), "same seed must give the same order")
+	assert.NotEqual(t, ids(whole), ids(other), "different seeds should give different orders")
+	assert.ElementsMatch(t, ids(whole), ids(other))
+	assert.Equal(t, ids(whole), ids(paged), "pages must follow the same order without repeats")
+
+	// Курсор остается корректным после удаления растения, на котором он остановился
+	_, err = dbPool.Exec(ctx, "DELETE FROM plants WHERE id = $1", whole[49].ID)
+	require.NoError(t, err)
+	rest, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 100, Seed: &seed, AfterID: whole[49].ID})
+	require.NoError(t, err)
+	assert.Equal(t, ids(whole[50:]), ids(rest))
+
+	// Исключения применяются и к детерминированной выборке
+	excluded, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 2, Seed: &seed, Exclude: []int{whole[0].ID}})
+	require.NoError(t, err)
+	assert.Equal(t, ids(whole[1:3]), ids(excluded))
+}
+
+// BenchmarkPlantRepo_GetRandom сравнивает стратегии выборки на заранее заполненной таблице,
+// а также детерминированную выборку с зерном: первую страницу и страницу из середины порядка.
+// Размер таблицы задается переменной окружения BENCH_PLANTS (по умолчанию 200000 строк).
+//
+//	go test -run '^$' -bench GetRandom ./internal/repository/postgres/
+func BenchmarkPlantRepo_GetRandom(b *testing.B) {
+	rows := 200000
+	if s := os.Getenv("BENCH_PLANTS"); s != "" {
+		n, err := strconv.Atoi(s)
+		require.NoError(b, err)
+		rows = n
+	}
+
+	dbPool, _, container := testutil.SetupTestDB(b)
+	defer testutil.CleanupTestDB(b, dbPool, container)
+
+	// Каждое десятое растение удалено, как будто его убрали модераторы.
+	seedPlants(b, dbPool, rows, 10)
+
+	ctx := context.Background()
+	for _, strategy := range samplingStrategies {
+		repo := NewPlantRepo(dbPool, WithSamplingStrategy(strategy))
+		b.Run(string(strategy), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 15}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	repo := NewPlantRepo(dbPool)
+	seed := int64(20231016)
+	// Курсор середины порядка: последнее растение страницы, на которой пролистана половина леса.
+	middle, err := repo.GetRandom(ctx, domain.RandomFilter{Count: rows / 2, Seed: &seed})
+	require.NoError(b, err)
+	require.NotEmpty(b, middle)
+	cursors := map[string]int{"seeded_first_page": 0, "seeded_middle_page": middle[len(middle)-1].ID}
+	for _, name := range []string{"seeded_first_page", "seeded_middle_page"} {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetRandom(ctx, domain.RandomFilter{Count: 15, Seed: &seed, AfterID: cursors[name]}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
+)
+
+// rateLimitPruneInterval - как часто RateLimitStore удаляет наполнившиеся корзины.
+const rateLimitPruneInterval = time.Minute
+
+// RateLimitStore - хранилище корзин ограничителя частоты в PostgreSQL, общее для всех реплик.
+// Время берется из часов базы, чтобы расхождение часов реплик не влияло на лимиты.
+type RateLimitStore struct {
+	db *pgxpool.Pool
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewRateLimitStore - конструктор для хранилища.
+func NewRateLimitStore(db *pgxpool.Pool) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// Take пополняет корзину key и берет из нее токен. Корзина блокируется на время расчета,
+// поэтому параллельные запросы разных реплик не тратят один и тот же токен дважды.
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("RateLimitStore - Take - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) // После Commit ничего не делает
+
+	// Новая корзина создается полной; если она уже есть, ничего не меняется.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, full_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (key) DO NOTHING`,
+		key, float64(limit.Capacity()),
+	)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("RateLimitStore - Take - Insert: %w", err)
+	}
+
+	var (
+		b   ratelimit.Bucket
+		now time.Time
+	)
+	err = tx.QueryRow(ctx,
+		"SELECT tokens, updated_at, NOW() FROM rate_limit_buckets WHERE key = $1 FOR UPDATE", key,
+	).Scan(&b.Tokens, &b.UpdatedAt, &now)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("RateLimitStore - Take - QueryRow.Scan: %w", err)
+	}
+
+	result := b.Take(limit, now)
+	_, err = tx.Exec(ctx,
+		"UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3, full_at = $4 WHERE key = $1",
+		key, b.Tokens, b.UpdatedAt, b.FullAt(limit),
+	)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("RateLimitStore - Take - Update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ratelimit.Result{}, fmt.Errorf("RateLimitStore - Take - Commit: %w", err)
+	}
+
+	s.prune(ctx)
+	return result, nil
+}
+
+// prune время от времени удаляет наполнившиеся корзины. Ошибка не мешает ответу:
+// корзины просто удалятся при следующей попытке.
+func (s *RateLimitStore) prune(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < rateLimitPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+
+	_, _ = s.db.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE full_at < NOW()")
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStore_Take(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	store := NewRateLimitStore(dbPool)
+	limit := ratelimit.Limit{Requests: 1, Period: time.Hour, Burst: 3}
+
+	// Act & Assert: корзина пропускает Burst запросов, затем отказывает
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "create_plant:ip:192.0.2.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "create_plant:ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.InDelta(t, time.Hour.Seconds(), result.RetryAfter.Seconds(), 5)
+
+	result, err = store.Take(ctx, "create_plant:ip:192.0.2.2", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "other keys have their own buckets")
+}
+
+func TestRateLimitStore_Take_Concurrent(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	store := NewRateLimitStore(dbPool)
+	limit := ratelimit.Limit{Requests: 1, Period: time.Hour, Burst: 5}
+
+	// Act: параллельные запросы, как от нескольких реплик
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "shared", limit)
+			assert.NoError(t, err)
+			if result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, 5, allowed)
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+)
+
+// spentChallengePruneInterval - как часто SpentChallengeStore удаляет истекшие задачи.
+const spentChallengePruneInterval = time.Minute
+
+// SpentChallengeStore помнит использованные задачи proof-of-work в PostgreSQL,
+// чтобы решение нельзя было повторить на другой реплике.
+type SpentChallengeStore struct {
+	db *pgxpool.Pool
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewSpentChallengeStore - конструктор для хранилища.
+func NewSpentChallengeStore(db *pgxpool.Pool) *SpentChallengeStore {
+	return &SpentChallengeStore{db: db}
+}
+
+// Spend отмечает задачу использованной или возвращает hashcash.ErrReplayed.
+// Первичный ключ гарантирует, что из параллельных запросов с одной задачей пройдет только один.
+func (s *SpentChallengeStore) Spend(ctx context.Context, id string, expiresAt time.Time) error {
+	tag, err := s.db.Exec(ctx,
+		"INSERT INTO spent_challenges (id, expires_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING",
+		id, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("SpentChallengeStore - Spend - Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return hashcash.ErrReplayed
+	}
+
+	s.prune(ctx)
+	return nil
+}
+
+// prune время от времени удаляет истекшие задачи. Ошибка не мешает ответу.
+func (s *SpentChallengeStore) prune(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < spentChallengePruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+
+	_, _ = s.db.Exec(ctx, "DELETE FROM spent_challenges WHERE expires_at < NOW()")
+}
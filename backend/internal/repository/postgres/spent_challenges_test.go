@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpentChallengeStore_Spend(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	store := NewSpentChallengeStore(dbPool)
+	expiresAt := time.Now().Add(time.Minute)
+
+	// Act & Assert
+	require.NoError(t, store.Spend(ctx, "first", expiresAt))
+	assert.ErrorIs(t, store.Spend(ctx, "first", expiresAt), hashcash.ErrReplayed)
+	assert.NoError(t, store.Spend(ctx, "second", expiresAt))
+
+	// Из параллельных попыток с одной задачей проходит ровно одна
+	var (
+		wg       sync.WaitGroup
+		accepted atomic.Int32
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Spend(ctx, "raced", expiresAt) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load())
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+)
+
+// QueryTracer начинает span на каждый SQL-запрос: с текстом запроса и числом строк, которые он вернул или изменил.
+// Аргументы запроса в span не попадают. Подключается к пулу через pgxpool.Config.ConnConfig.Tracer.
+type QueryTracer struct{}
+
+// TraceQueryStart реализует pgx.QueryTracer.
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := sqlOperation(data.SQL)
+	ctx, _ = tracing.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd реализует pgx.QueryTracer. Для Query он вызывается, когда строки прочитаны и закрыты.
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+	}
+	tracing.End(span, data.Err)
+}
+
+// sqlOperation возвращает первое слово запроса, например SELECT или INSERT.
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+)
+
+func TestQueryTracer(t *testing.T) {
+	const query = "SELECT id, author FROM plants WHERE status = $1 LIMIT 10"
+
+	tests := []struct {
+		name         string
+		end          pgx.TraceQueryEndData
+		expectedRows bool
+		expectedCode codes.Code
+	}{
+		{
+			name:         "rows",
+			end:          pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 7")},
+			expectedRows: true,
+		},
+		{
+			name:         "error",
+			end:          pgx.TraceQueryEndData{Err: errors.New("relation \"plants\" does not exist")},
+			expectedCode: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			recorder := testutil.RecordSpans(t)
+			tracer := QueryTracer{}
+
+			// Act
+			ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: query, Args: []any{"visible"}})
+			tracer.TraceQueryEnd(ctx, nil, tt.end)
+
+			// Assert
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "postgres SELECT", span.Name())
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+			assert.Contains(t, span.Attributes(), attribute.String("db.query.text", query))
+			assert.Equal(t, tt.expectedCode, span.Status().Code)
+			if tt.expectedRows {
+				assert.Contains(t, span.Attributes(), attribute.Int("db.response.returned_rows", 7))
+			}
+			// Аргументы запроса в span не записываются
+			for _, attr := range span.Attributes() {
+				assert.NotEqual(t, "visible", attr.Value.Emit())
+			}
+		})
+	}
+}
+
+func TestSQLOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", sqlOperation("SELECT 1"))
+	assert.Equal(t, "INSERT", sqlOperation("\n\tinsert into plants (author) values ($1)"))
+	assert.Equal(t, "QUERY", sqlOperation("  "))
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+)
+
+// GetIdentity возвращает привязку внешней учетной записи.
+// Если учетная запись еще не привязана, возвращается userDomain.ErrIdentityNotFound.
+func (r *UserRepo) GetIdentity(ctx context.Context, issuer, subject string) (userDomain.Identity, error) {
+	var id userDomain.Identity
+	err := r.db.QueryRow(ctx,
+		"SELECT issuer, subject, user_id, created_at FROM user_identities WHERE issuer = $1 AND subject = $2",
+		issuer, subject,
+	).Scan(&id.Issuer, &id.Subject, &id.UserID, &id.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userDomain.Identity{}, userDomain.ErrIdentityNotFound
+	}
+	if err != nil {
+		return userDomain.Identity{}, fmt.Errorf("UserRepo - GetIdentity - QueryRow.Scan: %w", err)
+	}
+	return id, nil
+}
+
+// CreateIdentity привязывает внешнюю учетную запись к пользователю.
+// Повторная привязка к тому же пользователю ничего не меняет,
+// а к другому - возвращает userDomain.ErrIdentityLinked.
+func (r *UserRepo) CreateIdentity(ctx context.Context, id userDomain.Identity) error {
+	var owner int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO user_identities (issuer, subject, user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (issuer, subject) DO UPDATE SET issuer = EXCLUDED.issuer
+		RETURNING user_id`,
+		id.Issuer, id.Subject, id.UserID, id.CreatedAt,
+	).Scan(&owner)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return userDomain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("UserRepo - CreateIdentity - QueryRow.Scan: %w", err)
+	}
+	if owner != id.UserID {
+		return userDomain.ErrIdentityLinked
+	}
+	return nil
+}
+
+// CreateLoginState сохраняет состояние начатого входа через провайдера.
+// Заодно удаляются истекшие состояния: браузеры, не вернувшиеся от провайдера, их не заберут.
+func (r *UserRepo) CreateLoginState(ctx context.Context, s userDomain.LoginState) error {
+	_, err := r.db.Exec(ctx, `
+		WITH expired AS (
+			DELETE FROM oidc_login_states WHERE expires_at <= NOW()
+		)
+		INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, link_user_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		s.State, s.Provider, s.Nonce, s.CodeVerifier, nullIfZero(s.LinkUserID), s.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UserRepo - CreateLoginState - Exec: %w", err)
+	}
+	return nil
+}
+
+// ConsumeLoginState забирает состояние входа: каждое состояние можно использовать только один раз.
+// Если состояния нет или оно истекло, возвращается userDomain.ErrLoginStateNotFound.
+func (r *UserRepo) ConsumeLoginState(ctx context.Context, state string) (userDomain.LoginState, error) {
+	var (
+		s    userDomain.LoginState
+		live bool
+	)
+	err := r.db.QueryRow(ctx, `
+		DELETE FROM oidc_login_states
+		WHERE state = $1
+		RETURNING state, provider, nonce, code_verifier, COALESCE(link_user_id, 0), expires_at, expires_at > NOW()`,
+		state,
+	).Scan(&s.State, &s.Provider, &s.Nonce, &s.CodeVerifier, &s.LinkUserID, &s.ExpiresAt, &live)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userDomain.LoginState{}, userDomain.ErrLoginStateNotFound
+	}
+	if err != nil {
+		return userDomain.LoginState{}, fmt.Errorf("UserRepo - ConsumeLoginState - QueryRow.Scan: %w", err)
+	}
+	// Истекшее состояние все равно удаляется, но вход по нему не засчитывается.
+	if !live {
+		return userDomain.LoginState{}, userDomain.ErrLoginStateNotFound
+	}
+	return s, nil
+}
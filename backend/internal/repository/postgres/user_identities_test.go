@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepo_Identities(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewUserRepo(dbPool)
+	alice, err := repo.CreateUser(ctx, userDomain.User{Username: "alice", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+	bob, err := repo.CreateUser(ctx, userDomain.User{Username: "bob", PasswordHash: "hash", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+	identity := userDomain.Identity{Issuer: "https://idp.example.com", Subject: "sub-1", UserID: alice.ID, CreatedAt: time.Now().UTC()}
+
+	// Act
+	_, err = repo.GetIdentity(ctx, identity.Issuer, identity.Subject)
+	assert.ErrorIs(t, err, userDomain.ErrIdentityNotFound)
+
+	require.NoError(t, repo.CreateIdentity(ctx, identity))
+
+	// Assert
+	found, err := repo.GetIdentity(ctx, identity.Issuer, identity.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, found.UserID)
+
+	assert.NoError(t, repo.CreateIdentity(ctx, identity), "linking again to the same user is a no-op")
+
+	other := identity
+	other.UserID = bob.ID
+	assert.ErrorIs(t, repo.CreateIdentity(ctx, other), userDomain.ErrIdentityLinked)
+
+	missing := identity
+	missing.Subject, missing.UserID = "sub-2", 999999
+	assert.ErrorIs(t, repo.CreateIdentity(ctx, missing), userDomain.ErrNotFound)
+
+	_, err = repo.GetIdentity(ctx, "https://other.example.com", identity.Subject)
+	assert.ErrorIs(t, err, userDomain.ErrIdentityNotFound, "subjects are scoped by issuer")
+}
+
+func TestUserRepo_LoginStates(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewUserRepo(dbPool)
+	u, err := repo.CreateUser(ctx, userDomain.User{Username: "alice", PasswordHash: "hash", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	live := userDomain.LoginState{
+		State:        "state-1",
+		Provider:     "mock",
+		Nonce:        "nonce-1",
+		CodeVerifier: "verifier-1",
+		LinkUserID:   u.ID,
+		ExpiresAt:    time.Now().UTC().Add(userDomain.LoginStateTTL),
+	}
+	expired := live
+	expired.State, expired.LinkUserID, expired.ExpiresAt = "state-2", 0, time.Now().UTC().Add(-time.Minute)
+
+	// Act
+	require.NoError(t, repo.CreateLoginState(ctx, expired))
+	require.NoError(t, repo.CreateLoginState(ctx, live))
+
+	// Assert
+	consumed, err := repo.ConsumeLoginState(ctx, "state-1")
+	require.NoError(t, err)
+	assert.Equal(t, "mock", consumed.Provider)
+	assert.Equal(t, "nonce-1", consumed.Nonce)
+	assert.Equal(t, "verifier-1", consumed.CodeVerifier)
+	assert.Equal(t, u.ID, consumed.LinkUserID)
+
+	_, err = repo.ConsumeLoginState(ctx, "state-1")
+	assert.ErrorIs(t, err, userDomain.ErrLoginStateNotFound, "state is single-use")
+	_, err = repo.ConsumeLoginState(ctx, "state-2")
+	assert.ErrorIs(t, err, userDomain.ErrLoginStateNotFound, "expired state is rejected")
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+)
+
+// pgUniqueViolation - код ошибки Postgres при нарушении уникального индекса.
+const pgUniqueViolation = "23505"
+
+// userColumns - колонки, которые читаются для каждого пользователя.
+var userColumns = []string{"id", "username", "password_hash", "created_at"}
+
+// UserRepo - репозиторий пользователей и их сессий в PostgreSQL.
+type UserRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewUserRepo - конструктор для репозитория.
+func NewUserRepo(db *pgxpool.Pool) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+// CreateUser сохраняет нового пользователя.
+// Если имя уже занято (без учета регистра), возвращается userDomain.ErrUsernameTaken.
+func (r *UserRepo) CreateUser(ctx context.Context, u userDomain.User) (userDomain.User, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Insert("users").
+		Columns("username", "password_hash", "created_at").
+		Values(u.Username, u.PasswordHash, u.CreatedAt).
+		Suffix("RETURNING id, username, password_hash, created_at").
+		ToSql()
+	if err != nil {
+		return userDomain.User{}, fmt.Errorf("UserRepo - CreateUser - ToSql: %w", err)
+	}
+
+	created, err := scanUser(r.db.QueryRow(ctx, sql, args...))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return userDomain.User{}, userDomain.ErrUsernameTaken
+	}
+	if err != nil {
+		return userDomain.User{}, fmt.Errorf("UserRepo - CreateUser - QueryRow.Scan: %w", err)
+	}
+	return created, nil
+}
+
+// GetUserByUsername ищет пользователя по имени без учета регистра.
+// Если пользователя нет, возвращается userDomain.ErrNotFound.
+func (r *UserRepo) GetUserByUsername(ctx context.Context, username string) (userDomain.User, error) {
+	return r.getUser(ctx, sq.Expr("lower(username) = lower(?)", username))
+}
+
+// GetUserByID возвращает пользователя по идентификатору.
+// Если пользователя нет, возвращается userDomain.ErrNotFound.
+func (r *UserRepo) GetUserByID(ctx context.Context, id int) (userDomain.User, error) {
+	return r.getUser(ctx, sq.Eq{"id": id})
+}
+
+// getUser возвращает одного пользователя, удовлетворяющего условию.
+func (r *UserRepo) getUser(ctx context.Context, where sq.Sqlizer) (userDomain.User, error) {
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(userColumns...).
+		From("users").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return userDomain.User{}, fmt.Errorf("UserRepo - GetUser - ToSql: %w", err)
+	}
+
+	u, err := scanUser(r.db.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userDomain.User{}, userDomain.ErrNotFound
+	}
+	if err != nil {
+		return userDomain.User{}, fmt.Errorf("UserRepo - GetUser - QueryRow.Scan: %w", err)
+	}
+	return u, nil
+}
+
+// scanUser сканирует строку с колонками userColumns.
+func scanUser(row pgx.Row) (userDomain.User, error) {
+	var u userDomain.User
+	err := row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	return u, err
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepo_Users(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewUserRepo(dbPool)
+
+	// Act
+	created, err := repo.CreateUser(ctx, userDomain.User{Username: "Alice", PasswordHash: "hash", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	// Assert
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "Alice", created.Username)
+
+	_, err = repo.CreateUser(ctx, userDomain.User{Username: "alice", PasswordHash: "other", CreatedAt: time.Now().UTC()})
+	assert.ErrorIs(t, err, userDomain.ErrUsernameTaken, "usernames are case-insensitive")
+
+	found, err := repo.GetUserByUsername(ctx, "ALICE")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, "hash", found.PasswordHash)
+
+	byID, err := repo.GetUserByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", byID.Username)
+
+	_, err = repo.GetUserByUsername(ctx, "bob")
+	assert.ErrorIs(t, err, userDomain.ErrNotFound)
+	_, err = repo.GetUserByID(ctx, 999999)
+	assert.ErrorIs(t, err, userDomain.ErrNotFound)
+}
+
+func TestUserRepo_Sessions(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	repo := NewUserRepo(dbPool)
+	u, err := repo.CreateUser(ctx, userDomain.User{Username: "alice", PasswordHash: "hash", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	expired, _, err := userDomain.NewSession(u.ID, now.Add(-2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateSession(ctx, expired))
+
+	// Act
+	session, _, err := userDomain.NewSession(u.ID, now, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateSession(ctx, session))
+
+	// Assert
+	found, owner, err := repo.GetSession(ctx, session.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, owner.ID)
+	assert.Equal(t, "alice", owner.Username)
+	assert.Equal(t, session.CSRFToken, found.CSRFToken)
+
+	_, _, err = repo.GetSession(ctx, expired.TokenHash)
+	assert.ErrorIs(t, err, userDomain.ErrSessionNotFound)
+
+	var rows int
+	require.NoError(t, dbPool.QueryRow(ctx, "SELECT COUNT(*) FROM sessions").Scan(&rows))
+	assert.Equal(t, 1, rows, "expired sessions are purged on login")
+
+	require.NoError(t, repo.DeleteSession(ctx, session.TokenHash))
+	_, _, err = repo.GetSession(ctx, session.TokenHash)
+	assert.ErrorIs(t, err, userDomain.ErrSessionNotFound)
+	assert.NoError(t, repo.DeleteSession(ctx, session.TokenHash), "logout is idempotent")
+}
+
+func TestPlantRepo_UserPlants(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	ctx := context.Background()
+	users := NewUserRepo(dbPool)
+	plants := NewPlantRepo(dbPool)
+
+	u, err := users.CreateUser(ctx, userDomain.User{Username: "gardener", PasswordHash: "hash", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	// Act
+	owned, err := plants.Create(ctx, domain.Plant{Author: "gardener", ImageData: "data", UserID: u.ID, CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+	anonymous, err := plants.Create(ctx, domain.Plant{Author: "gardener", ImageData: "data", CreatedAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, u.ID, owned.UserID)
+	assert.Zero(t, anonymous.UserID)
+
+	list, err := plants.List(ctx, domain.ListFilter{UserID: u.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, owned.ID, list[0].ID)
+}
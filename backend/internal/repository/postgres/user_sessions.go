@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+)
+
+// CreateSession сохраняет новую сессию.
+// Заодно удаляются истекшие сессии того же пользователя, чтобы таблица не росла от повторных входов.
+func (r *UserRepo) CreateSession(ctx context.Context, s userDomain.Session) error {
+	_, err := r.db.Exec(ctx, `
+		WITH expired AS (
+			DELETE FROM sessions WHERE user_id = $2 AND expires_at <= $4
+		)
+		INSERT INTO sessions (token_hash, user_id, csrf_token, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		s.TokenHash, s.UserID, s.CSRFToken, s.CreatedAt, s.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("UserRepo - CreateSession - Exec: %w", err)
+	}
+	return nil
+}
+
+// GetSession возвращает действующую сессию по хэшу токена вместе с ее пользователем.
+// Если сессии нет или она истекла, возвращается userDomain.ErrSessionNotFound.
+func (r *UserRepo) GetSession(ctx context.Context, tokenHash string) (userDomain.Session, userDomain.User, error) {
+	var (
+		s userDomain.Session
+		u userDomain.User
+	)
+	err := r.db.QueryRow(ctx, `
+		SELECT s.token_hash, s.user_id, s.csrf_token, s.created_at, s.expires_at,
+			u.id, u.username, u.password_hash, u.created_at
+		FROM sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = $1 AND s.expires_at > NOW()`,
+		tokenHash,
+	).Scan(&s.TokenHash, &s.UserID, &s.CSRFToken, &s.CreatedAt, &s.ExpiresAt,
+		&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return userDomain.Session{}, userDomain.User{}, userDomain.ErrSessionNotFound
+	}
+	if err != nil {
+		return userDomain.Session{}, userDomain.User{}, fmt.Errorf("UserRepo - GetSession - QueryRow.Scan: %w", err)
+	}
+	return s, u, nil
+}
+
+// DeleteSession удаляет сессию. Отсутствие сессии ошибкой не считается: выход идемпотентен.
+func (r *UserRepo) DeleteSession(ctx context.Context, tokenHash string) error {
+	if _, err := r.db.Exec(ctx, "DELETE FROM sessions WHERE token_hash = $1", tokenHash); err != nil {
+		return fmt.Errorf("UserRepo - DeleteSession - Exec: %w", err)
+	}
+	return nil
+}
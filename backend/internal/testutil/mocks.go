@@ -2,8 +2,11 @@ package testutil
 
 import (
 	"context"
+	"time"
 
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -17,11 +20,166 @@ func (m *MockPlantRepository) Create(ctx context.Context, plant domain.Plant) (d
 	return args.Get(0).(domain.Plant), args.Error(1)
 }
 
-func (m *MockPlantRepository) GetRandom(ctx context.Context, count int) ([]domain.Plant, error) {
-	args := m.Called(ctx, count)
+func (m *MockPlantRepository) GetRandom(ctx context.Context, filter domain.RandomFilter) ([]domain.Plant, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.Plant), args.Error(1)
 }
 
+func (m *MockPlantRepository) GetByID(ctx context.Context, id int) (domain.Plant, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) List(ctx context.Context, filter domain.ListFilter) ([]domain.Plant, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) GetByIDAnyStatus(ctx context.Context, id int) (domain.Plant, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) ModerationLog(ctx context.Context, plantID int) ([]domain.ModerationEvent, error) {
+	args := m.Called(ctx, plantID)
+	return args.Get(0).([]domain.ModerationEvent), args.Error(1)
+}
+
+func (m *MockPlantRepository) CreateReport(ctx context.Context, report domain.Report) (int, error) {
+	args := m.Called(ctx, report)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPlantRepository) ReportQueue(ctx context.Context, limit int) ([]domain.ReportSummary, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.ReportSummary), args.Error(1)
+}
+
+func (m *MockPlantRepository) ResolveReports(ctx context.Context, plantID int, at time.Time) (int, error) {
+	args := m.Called(ctx, plantID, at)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPlantRepository) Update(ctx context.Context, id int, update domain.PlantUpdate) (domain.Plant, error) {
+	args := m.Called(ctx, id, update)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error) {
+	args := m.Called(ctx, fp, maxDistance, excludeID)
+	return args.Get(0).(domain.Duplicate), args.Error(1)
+}
+
+func (m *MockPlantRepository) ListFingerprints(ctx context.Context) ([]domain.FingerprintedPlant, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.FingerprintedPlant), args.Error(1)
+}
+
+func (m *MockPlantRepository) ListWithoutFingerprint(ctx context.Context, afterID, limit int) ([]domain.Plant, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]domain.Plant), args.Error(1)
+}
+
+func (m *MockPlantRepository) SetFingerprint(ctx context.Context, id int, fp domain.Fingerprint) error {
+	args := m.Called(ctx, id, fp)
+	return args.Error(0)
+}
+
+func (m *MockPlantRepository) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	args := m.Called(ctx, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPlantRepository) CountByStatus(ctx context.Context) (map[domain.Status]int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[domain.Status]int), args.Error(1)
+}
+
+// MockUserRepository - мок для репозитория пользователей и сессий
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, u userDomain.User) (userDomain.User, error) {
+	args := m.Called(ctx, u)
+	return args.Get(0).(userDomain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (userDomain.User, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(userDomain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id int) (userDomain.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(userDomain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateSession(ctx context.Context, s userDomain.Session) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetSession(ctx context.Context, tokenHash string) (userDomain.Session, userDomain.User, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(userDomain.Session), args.Get(1).(userDomain.User), args.Error(2)
+}
+
+func (m *MockUserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	args := m.Called(ctx, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetIdentity(ctx context.Context, issuer, subject string) (userDomain.Identity, error) {
+	args := m.Called(ctx, issuer, subject)
+	return args.Get(0).(userDomain.Identity), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateIdentity(ctx context.Context, id userDomain.Identity) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CreateLoginState(ctx context.Context, s userDomain.LoginState) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ConsumeLoginState(ctx context.Context, state string) (userDomain.LoginState, error) {
+	args := m.Called(ctx, state)
+	return args.Get(0).(userDomain.LoginState), args.Error(1)
+}
+
+// MockAPIKeyRepository - мок для репозитория ключей административного API
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, k apikey.APIKey) (apikey.APIKey, error) {
+	args := m.Called(ctx, k)
+	return args.Get(0).(apikey.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]apikey.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]apikey.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) UseAPIKey(ctx context.Context, hash string) (apikey.APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(apikey.APIKey), args.Error(1)
+}
+
 // MockValidator - мок для валидатора
 type MockValidator struct {
 	mock.Mock
@@ -40,6 +198,16 @@ func NewMockPlantRepository() *MockPlantRepository {
 	return &MockPlantRepository{}
 }
 
+// NewMockUserRepository создает новый мок репозитория пользователей
+func NewMockUserRepository() *MockUserRepository {
+	return &MockUserRepository{}
+}
+
+// NewMockAPIKeyRepository создает новый мок репозитория ключей
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{}
+}
+
 // NewMockValidator создает новый мок валидатора
 func NewMockValidator() *MockValidator {
 	return &MockValidator{}
@@ -48,8 +216,51 @@ func NewMockValidator() *MockValidator {
 // PlantRepositoryInterface определяет интерфейс для репозитория растений
 type PlantRepositoryInterface interface {
 	Create(ctx context.Context, plant domain.Plant) (domain.Plant, error)
-	GetRandom(ctx context.Context, count int) ([]domain.Plant, error)
+	GetRandom(ctx context.Context, filter domain.RandomFilter) ([]domain.Plant, error)
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+	List(ctx context.Context, filter domain.ListFilter) ([]domain.Plant, error)
+	GetByIDAnyStatus(ctx context.Context, id int) (domain.Plant, error)
+	Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error)
+	ModerationLog(ctx context.Context, plantID int) ([]domain.ModerationEvent, error)
+	CreateReport(ctx context.Context, report domain.Report) (int, error)
+	ReportQueue(ctx context.Context, limit int) ([]domain.ReportSummary, error)
+	ResolveReports(ctx context.Context, plantID int, at time.Time) (int, error)
+	Update(ctx context.Context, id int, update domain.PlantUpdate) (domain.Plant, error)
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+	CountByStatus(ctx context.Context) (map[domain.Status]int, error)
+	FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error)
+	ListFingerprints(ctx context.Context) ([]domain.FingerprintedPlant, error)
+	ListWithoutFingerprint(ctx context.Context, afterID, limit int) ([]domain.Plant, error)
+	SetFingerprint(ctx context.Context, id int, fp domain.Fingerprint) error
 }
 
 // AssertPlantRepositoryInterface проверяет, что мок реализует интерфейс
 var _ PlantRepositoryInterface = (*MockPlantRepository)(nil)
+
+// UserRepositoryInterface определяет интерфейс для репозитория пользователей
+type UserRepositoryInterface interface {
+	CreateUser(ctx context.Context, u userDomain.User) (userDomain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (userDomain.User, error)
+	GetUserByID(ctx context.Context, id int) (userDomain.User, error)
+	CreateSession(ctx context.Context, s userDomain.Session) error
+	GetSession(ctx context.Context, tokenHash string) (userDomain.Session, userDomain.User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	GetIdentity(ctx context.Context, issuer, subject string) (userDomain.Identity, error)
+	CreateIdentity(ctx context.Context, id userDomain.Identity) error
+	CreateLoginState(ctx context.Context, s userDomain.LoginState) error
+	ConsumeLoginState(ctx context.Context, state string) (userDomain.LoginState, error)
+}
+
+// AssertUserRepositoryInterface проверяет, что мок реализует интерфейс
+var _ UserRepositoryInterface = (*MockUserRepository)(nil)
+
+// APIKeyRepositoryInterface определяет интерфейс для репозитория ключей административного API
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(ctx context.Context, k apikey.APIKey) (apikey.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]apikey.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, at time.Time) error
+	UseAPIKey(ctx context.Context, hash string) (apikey.APIKey, error)
+}
+
+// AssertAPIKeyRepositoryInterface проверяет, что мок реализует интерфейс
+var _ APIKeyRepositoryInterface = (*MockAPIKeyRepository)(nil)
@@ -0,0 +1,249 @@
+package testutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// MockOIDCIssuer - провайдер OpenID Connect, работающий внутри теста.
+// Он поддерживает discovery, authorization code flow с обязательным PKCE (S256) и подписывает
+// ID-токены RS256, так что весь вход можно проверить без сети.
+//
+// Страницы входа нет: /authorize сразу возвращает браузер на redirect_uri с кодом
+// для пользователя, заданного SetUser.
+type MockOIDCIssuer struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu sync.Mutex
+	// subject, username и email - кто "входит" при следующем запросе /authorize.
+	subject  string
+	username string
+	email    string
+	// override заменяет или добавляет утверждения в следующие ID-токены.
+	override map[string]any
+
+	key   *rsa.PrivateKey
+	codes map[string]mockAuthCode
+}
+
+// mockAuthCode - выданный, но еще не обмененный код авторизации.
+type mockAuthCode struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	subject     string
+	username    string
+	email       string
+}
+
+const mockOIDCKeyID = "mock-key"
+
+// NewMockOIDCIssuer запускает провайдера и останавливает его по завершении теста.
+func NewMockOIDCIssuer(t testing.TB) *MockOIDCIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+
+	m := &MockOIDCIssuer{
+		ClientID:     "digital-forest",
+		ClientSecret: "mock-secret",
+		subject:      "mock-subject",
+		username:     "mock.user",
+		email:        "mock.user@example.com",
+		key:          key,
+		codes:        make(map[string]mockAuthCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("GET /jwks", m.jwks)
+	mux.HandleFunc("GET /authorize", m.authorize)
+	mux.HandleFunc("POST /token", m.token)
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Server.Close)
+
+	return m
+}
+
+// Issuer возвращает URL издателя для конфигурации провайдера.
+func (m *MockOIDCIssuer) Issuer() string {
+	return m.Server.URL
+}
+
+// SetUser задает, кто войдет при следующем запросе /authorize.
+func (m *MockOIDCIssuer) SetUser(subject, username, email string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subject, m.username, m.email = subject, username, email
+}
+
+// SetOverride задает утверждения, которые заменят стандартные в следующих ID-токенах,
+// например чужой aud или истекший exp.
+func (m *MockOIDCIssuer) SetOverride(claims map[string]any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.override = claims
+}
+
+func (m *MockOIDCIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                m.Issuer(),
+		"authorization_endpoint":                m.Issuer() + "/authorize",
+		"token_endpoint":                        m.Issuer() + "/token",
+		"jwks_uri":                              m.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "profile", "email"},
+	})
+}
+
+func (m *MockOIDCIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeMockJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &m.key.PublicKey,
+		KeyID:     mockOIDCKeyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+func (m *MockOIDCIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != m.ClientID || q.Get("response_type") != "code" {
+		http.Error(w, "invalid client or response type", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+	m.mu.Lock()
+	m.codes[code] = mockAuthCode{
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		redirectURI: redirectURI.String(),
+		subject:     m.subject,
+		username:    m.username,
+		email:       m.email,
+	}
+	m.mu.Unlock()
+
+	back := redirectURI.Query()
+	back.Set("code", code)
+	back.Set("state", q.Get("state"))
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (m *MockOIDCIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	if !ok {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != m.ClientID || subtle.ConstantTimeCompare([]byte(secret), []byte(m.ClientSecret)) != 1 {
+		writeMockJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	m.mu.Lock()
+	code, found := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code")) // код одноразовый
+	override := m.override
+	m.mu.Unlock()
+
+	if !found || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != code.redirectURI {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+		writeMockJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":                m.Issuer(),
+		"sub":                code.subject,
+		"aud":                m.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              code.nonce,
+		"preferred_username": code.username,
+		"email":              code.email,
+		"email_verified":     true,
+	}
+	for k, v := range override {
+		claims[k] = v
+	}
+
+	idToken, err := m.sign(claims)
+	if err != nil {
+		writeMockJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeMockJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+// sign подписывает утверждения ключом издателя.
+func (m *MockOIDCIssuer) sign(claims map[string]any) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: m.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", mockOIDCKeyID),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+	return signed.CompactSerialize()
+}
+
+func writeMockJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
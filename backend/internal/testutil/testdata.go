@@ -1,6 +1,12 @@
 package testutil
 
 import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
@@ -86,3 +92,41 @@ func GetInvalidCreatePlantRequest(index int) dto.CreatePlantRequest {
 	}
 	return dto.CreatePlantRequest{}
 }
+
+// Параметры изображений, которые рисует фронтенд (gridToBase64).
+const (
+	TestGridSize  = 16
+	TestCellScale = 10
+)
+
+// GenerateImageData возвращает base64 PNG в формате фронтенда: 16x16 клеток по 10px.
+// Для разных variant получаются разные изображения, для одинаковых - одинаковые.
+func GenerateImageData(variant int) string {
+	rnd := rand.New(rand.NewSource(int64(variant)))
+	palette := []color.RGBA{
+		{0, 0, 0, 0},
+		{34, 139, 34, 255},
+		{139, 69, 19, 255},
+		{255, 215, 0, 255},
+		{220, 20, 60, 255},
+	}
+
+	side := TestGridSize * TestCellScale
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	for cy := 0; cy < TestGridSize; cy++ {
+		for cx := 0; cx < TestGridSize; cx++ {
+			c := palette[rnd.Intn(len(palette))]
+			for y := cy * TestCellScale; y < (cy+1)*TestCellScale; y++ {
+				for x := cx * TestCellScale; x < (cx+1)*TestCellScale; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
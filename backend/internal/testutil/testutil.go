@@ -8,6 +8,8 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/migrate"
 )
 
 // TestDBConfig содержит конфигурацию для тестовой базы данных
@@ -21,7 +23,7 @@ type TestDBConfig struct {
 }
 
 // SetupTestDB создает тестовую базу данных с помощью testcontainers
-func SetupTestDB(t *testing.T) (*pgxpool.Pool, *TestDBConfig, testcontainers.Container) {
+func SetupTestDB(t testing.TB) (*pgxpool.Pool, *TestDBConfig, testcontainers.Container) {
 	ctx := context.Background()
 
 	// Создаем PostgreSQL контейнер
@@ -70,7 +72,7 @@ func SetupTestDB(t *testing.T) (*pgxpool.Pool, *TestDBConfig, testcontainers.Con
 }
 
 // CleanupTestDB закрывает соединения и останавливает контейнер
-func CleanupTestDB(t *testing.T, dbPool *pgxpool.Pool, container testcontainers.Container) {
+func CleanupTestDB(t testing.TB, dbPool *pgxpool.Pool, container testcontainers.Container) {
 	if dbPool != nil {
 		dbPool.Close()
 	}
@@ -82,22 +84,23 @@ func CleanupTestDB(t *testing.T, dbPool *pgxpool.Pool, container testcontainers.
 	}
 }
 
-// createTestTables создает необходимые таблицы для тестов
+// createTestTables накатывает на базу те же встроенные миграции, что и сервер,
+// и очищает таблицы от данных, которые добавили миграции.
 func createTestTables(ctx context.Context, db *pgxpool.Pool) error {
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS plants (
-		id SERIAL PRIMARY KEY,
-		author VARCHAR(255) NOT NULL,
-		image_data TEXT NOT NULL,
-		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
-	);`
-
-	_, err := db.Exec(ctx, createTableSQL)
-	return err
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+	return TruncateTables(ctx, db)
 }
 
 // TruncateTables очищает все таблицы для изоляции тестов
 func TruncateTables(ctx context.Context, db *pgxpool.Pool) error {
-	_, err := db.Exec(ctx, "TRUNCATE TABLE plants RESTART IDENTITY CASCADE")
+	_, err := db.Exec(ctx, "TRUNCATE TABLE plants, moderation_log, plant_reports, sessions, user_identities, oidc_login_states, users, api_keys, rate_limit_buckets, spent_challenges, idempotency_keys RESTART IDENTITY CASCADE")
 	return err
 }
@@ -0,0 +1,131 @@
+// Package tracing настраивает трассировку OpenTelemetry: экспорт spans, распространение контекста
+// через заголовки W3C traceparent и общие помощники для spans обработчиков, use cases и запросов к базе.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// instrumentationName - имя, под которым сервис создает spans.
+const instrumentationName = "github.com/heartmarshall/digital-forest/backend"
+
+// Экспортеры spans для Config.Exporter.
+const (
+	// ExporterNone выключает трассировку: spans не создаются, но traceparent по-прежнему распространяется.
+	ExporterNone = "none"
+	// ExporterStdout печатает spans в stdout в JSON. Нужен для отладки без коллектора.
+	ExporterStdout = "stdout"
+	// ExporterOTLP отправляет spans коллектору по OTLP/HTTP.
+	ExporterOTLP = "otlp"
+)
+
+// Config - настройки трассировки.
+type Config struct {
+	// Exporter - ExporterNone (или пустая строка), ExporterStdout или ExporterOTLP.
+	Exporter string
+	// Endpoint - адрес коллектора OTLP/HTTP, например "otel-collector:4318".
+	// Пустая строка - адрес из OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4318.
+	Endpoint string
+	// Insecure отправляет spans коллектору по http вместо https.
+	Insecure bool
+	// ServiceName - имя сервиса в spans.
+	ServiceName string
+	// SampleRatio - доля записываемых трасс от 0 до 1. Если у запроса есть traceparent, решение берется из него.
+	SampleRatio float64
+	// Output - куда пишет ExporterStdout; по умолчанию os.Stdout.
+	Output io.Writer
+}
+
+// Setup настраивает глобальные TracerProvider и распространение контекста по cfg.
+// Возвращенная функция дожидается отправки накопленных spans; ее нужно вызвать при остановке сервиса.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("sample ratio must be from 0 to 1, got %v", cfg.SampleRatio)
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		output := cfg.Output
+		if output == nil {
+			output = os.Stdout
+		}
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(output)); err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		var err error
+		if exporter, err = otlptracehttp.New(ctx, opts...); err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe service: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start начинает span name, дочерний для span из ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End завершает span. Ошибка err записывается в span, а внутренняя ошибка (cerror.Internal)
+// еще и помечает его неудачным: отказ по неверному запросу - обычный результат, а не сбой.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if cerror.KindOf(err) == cerror.Internal {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// UseCases начинает span на каждый вызов use case; имя span - имя use case, например "plant.get_random".
+type UseCases struct{}
+
+// Observe начинает span use case. Возвращенную функцию нужно вызвать с ошибкой use case.
+func (UseCases) Observe(ctx context.Context, name string) (context.Context, func(err error)) {
+	ctx, span := Start(ctx, name)
+	return ctx, func(err error) { End(span, err) }
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectedErr bool
+	}{
+		{name: "disabled", cfg: Config{}},
+		{name: "none", cfg: Config{Exporter: ExporterNone}},
+		{name: "otlp", cfg: Config{Exporter: ExporterOTLP, Endpoint: "localhost:4318", Insecure: true, SampleRatio: 0.5}},
+		{name: "unknown exporter", cfg: Config{Exporter: "jaeger", SampleRatio: 1}, expectedErr: true},
+		{name: "sample ratio above one", cfg: Config{Exporter: ExporterStdout, SampleRatio: 2}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Глобальный провайдер, установленный Setup, не должен достаться другим тестам
+			testutil.RecordSpans(t)
+
+			// Act
+			shutdown, err := Setup(context.Background(), tt.cfg)
+
+			// Assert
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	// Arrange
+	testutil.RecordSpans(t)
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout, ServiceName: "forest-test", SampleRatio: 1, Output: &out})
+	require.NoError(t, err)
+
+	// Act
+	_, span := Start(context.Background(), "plant.get_random")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	// Assert: spans выгружаются при остановке
+	assert.Contains(t, out.String(), `"Name":"plant.get_random"`)
+	assert.Contains(t, out.String(), "forest-test")
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
+
+func TestUseCases_Observe(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus codes.Code
+		expectedEvents int
+	}{
+		{name: "success", err: nil, expectedStatus: codes.Unset},
+		{name: "rejected", err: domain.ErrNotFound, expectedStatus: codes.Unset, expectedEvents: 1},
+		{name: "internal error", err: errors.New("connection reset"), expectedStatus: codes.Error, expectedEvents: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			recorder := testutil.RecordSpans(t)
+			parentCtx, parent := Start(context.Background(), "GET /v1/plants/{id}")
+
+			// Act
+			ctx, done := UseCases{}.Observe(parentCtx, "plant.get_by_id")
+			_, child := Start(ctx, "postgres SELECT")
+			child.End()
+			done(tt.err)
+			parent.End()
+
+			// Assert
+			spans := recorder.Ended()
+			require.Len(t, spans, 3)
+			useCase := spans[1]
+			assert.Equal(t, "plant.get_by_id", useCase.Name())
+			assert.Equal(t, parent.SpanContext().SpanID(), useCase.Parent().SpanID())
+			assert.Equal(t, useCase.SpanContext().SpanID(), spans[0].Parent().SpanID())
+			assert.Equal(t, tt.expectedStatus, useCase.Status().Code)
+			assert.Len(t, useCase.Events(), tt.expectedEvents)
+		})
+	}
+}
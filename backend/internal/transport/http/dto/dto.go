@@ -1,34 +1,293 @@
 package dto
 
 import (
+	"fmt"
+	"image/color"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
 )
 
+// OwnerTokenHeader - заголовок, в котором автор передает токен владельца из ответа на создание.
+const OwnerTokenHeader = "X-Owner-Token"
+
 // CreatePlantRequest - DTO для запроса на создание растения.
 // Теги `validate` используются библиотекой go-playground/validator.
+// Изображение передается либо как base64 PNG (ImageData), либо как пиксельная сетка (Grid).
+// Challenge и Solution - задача proof-of-work из GET /v1/challenges и ее решение; нужны только анонимной посадке,
+// когда проверка включена.
 type CreatePlantRequest struct {
-	Author    string `json:"author" validate:"required,max=255"`
-	ImageData string `json:"imageData" validate:"required"`
+	Author    string       `json:"author" validate:"required,max=255"`
+	ImageData string       `json:"imageData,omitempty" validate:"required_without=Grid,excluded_with=Grid"`
+	Grid      *GridPayload `json:"grid,omitempty" validate:"required_without=ImageData"`
+	Challenge string       `json:"challenge,omitempty" validate:"max=256"`
+	Solution  string       `json:"solution,omitempty" validate:"max=64"`
+}
+
+// GridPayload - DTO пиксельной сетки растения.
+// Палитра задается цветами "#RRGGBB", "#RRGGBBAA" или "transparent",
+// пиксели - индексами в палитре, построчно слева направо.
+type GridPayload struct {
+	Width   int      `json:"width" validate:"required,min=1"`
+	Height  int      `json:"height" validate:"required,min=1"`
+	Palette []string `json:"palette" validate:"required,min=1,max=256"`
+	Pixels  []int    `json:"pixels" validate:"required"`
 }
 
 // PlantResponse - DTO для ответа клиенту.
 // Мы отделяем эту структуру от доменной, чтобы иметь полный контроль
 // над тем, как наши данные выглядят в API.
 type PlantResponse struct {
+	ID        int          `json:"id"`
+	Author    string       `json:"author"`
+	ImageData string       `json:"imageData,omitempty"`
+	ImageURL  string       `json:"imageUrl"`
+	Grid      *GridPayload `json:"grid,omitempty"`
+	Status    string       `json:"status,omitempty"`
+	UserID    int          `json:"userId,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	// OwnerToken есть только в ответе на создание: это единственный раз, когда автор видит токен.
+	OwnerToken string `json:"ownerToken,omitempty"`
+}
+
+// UpdatePlantRequest - DTO для запроса автора на изменение растения.
+// Все поля необязательны; изображение, как и при создании, передается либо PNG, либо сеткой.
+type UpdatePlantRequest struct {
+	Author    *string      `json:"author,omitempty" validate:"omitnil,min=1,max=255"`
+	ImageData string       `json:"imageData,omitempty" validate:"excluded_with=Grid"`
+	Grid      *GridPayload `json:"grid,omitempty"`
+}
+
+// RegisterRequest - DTO запроса на регистрацию.
+// Допустимые символы имени проверяет userDomain.NormalizeUsername.
+type RegisterRequest struct {
+	Username string `json:"username" validate:"required,max=32"`
+	Password string `json:"password" validate:"required,min=8,max=128"`
+}
+
+// LoginRequest - DTO запроса на вход.
+type LoginRequest struct {
+	Username string `json:"username" validate:"required,max=32"`
+	Password string `json:"password" validate:"required,max=128"`
+}
+
+// UserResponse - DTO пользователя. Хэш пароля наружу не отдается.
+type UserResponse struct {
 	ID        int       `json:"id"`
-	Author    string    `json:"author"`
-	ImageData string    `json:"imageData"`
+	Username  string    `json:"username"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// SessionResponse - DTO текущей сессии. CSRF-токен нужно передавать в заголовке X-CSRF-Token
+// во всех изменяющих запросах, пока сессия действует.
+type SessionResponse struct {
+	User      UserResponse `json:"user"`
+	CSRFToken string       `json:"csrfToken"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+}
+
+// ModerationRequest - DTO необязательного тела запроса на действие модерации.
+type ModerationRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// ModerationEventResponse - DTO записи журнала модерации.
+type ModerationEventResponse struct {
+	ID         int       `json:"id"`
+	PlantID    int       `json:"plantId"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason,omitempty"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// CreateReportRequest - DTO жалобы посетителя на растение.
+type CreateReportRequest struct {
+	Reason string `json:"reason" validate:"required,oneof=spam offensive sexual violence copyright other"`
+}
+
+// ReportSummaryResponse - DTO растения в очереди жалоб.
+type ReportSummaryResponse struct {
+	PlantID         int            `json:"plantId"`
+	Status          string         `json:"status"`
+	Count           int            `json:"count"`
+	Reasons         map[string]int `json:"reasons"`
+	FirstReportedAt time.Time      `json:"firstReportedAt"`
+	LastReportedAt  time.Time      `json:"lastReportedAt"`
+}
+
+// DuplicateClusterResponse - DTO группы похожих растений, от старых к новым.
+// Exact - изображения всех растений группы совпадают полностью.
+type DuplicateClusterResponse struct {
+	Exact  bool                     `json:"exact"`
+	Plants []DuplicatePlantResponse `json:"plants"`
+}
+
+// DuplicatePlantResponse - DTO растения в группе похожих, без изображения.
+type DuplicatePlantResponse struct {
+	ID          int       `json:"id"`
+	Author      string    `json:"author"`
+	Status      string    `json:"status"`
+	ImageURL    string    `json:"imageUrl"`
+	ContentHash string    `json:"contentHash"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ChallengeResponse - DTO задачи proof-of-work. Клиент перебирает строки solution,
+// пока у SHA-256(challenge + ":" + solution) не окажется difficulty ведущих нулевых бит.
+type ChallengeResponse struct {
+	Challenge  string    `json:"challenge"`
+	Algorithm  string    `json:"algorithm"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// ImageURL возвращает путь, по которому можно загрузить PNG растения.
+// Изображение кешируется навсегда, поэтому после замены картинки путь получает номер версии.
+func ImageURL(id, version int) string {
+	if version <= 1 {
+		return fmt.Sprintf("/v1/plants/%d/image.png", id)
+	}
+	return fmt.Sprintf("/v1/plants/%d/image.png?v=%d", id, version)
+}
+
+// ToDomain преобразует DTO сетки в доменную модель.
+// Некорректные цвета и индексы приводят к ошибке domain.ErrGridInvalid.
+func (g GridPayload) ToDomain() (domain.Grid, error) {
+	grid := domain.Grid{
+		Width:   g.Width,
+		Height:  g.Height,
+		Palette: make([]color.NRGBA, len(g.Palette)),
+		Pixels:  make([]uint8, len(g.Pixels)),
+	}
+
+	for i, s := range g.Palette {
+		c, err := domain.ParseColor(s)
+		if err != nil {
+			return domain.Grid{}, err
+		}
+		grid.Palette[i] = c
+	}
+
+	for i, p := range g.Pixels {
+		if p < 0 || p >= len(g.Palette) {
+			return domain.Grid{}, fmt.Errorf("%w: pixel %d refers to missing palette color %d", domain.ErrGridInvalid, i, p)
+		}
+		grid.Pixels[i] = uint8(p)
+	}
+
+	return grid, nil
+}
+
+// ToGridPayload преобразует доменную сетку в DTO.
+func ToGridPayload(g domain.Grid) GridPayload {
+	payload := GridPayload{
+		Width:   g.Width,
+		Height:  g.Height,
+		Palette: make([]string, len(g.Palette)),
+		Pixels:  make([]int, len(g.Pixels)),
+	}
+	for i, c := range g.Palette {
+		payload.Palette[i] = domain.FormatColor(c)
+	}
+	for i, p := range g.Pixels {
+		payload.Pixels[i] = int(p)
+	}
+	return payload
+}
+
 // ToPlantResponse преобразует доменную модель в DTO для ответа.
+// Для растений, хранящихся в виде сетки, imageData рисуется на сервере.
 func ToPlantResponse(p domain.Plant) PlantResponse {
+	// Ошибка возможна только для некорректной сетки, которую мы не сохраняем.
+	imageData, _ := p.ImageBase64()
+
+	response := ToPlantResponseWithoutImage(p)
+	response.ImageData = imageData
+	if p.Grid != nil {
+		grid := ToGridPayload(*p.Grid)
+		response.Grid = &grid
+	}
+	return response
+}
+
+// ToPlantResponseWithoutImage преобразует доменную модель в DTO без встроенного изображения и сетки.
+// Клиент загружает изображение отдельно по ImageURL, что позволяет кешировать его.
+func ToPlantResponseWithoutImage(p domain.Plant) PlantResponse {
 	return PlantResponse{
-		ID:        p.ID,
-		Author:    p.Author,
-		ImageData: p.ImageData,
-		CreatedAt: p.CreatedAt,
+		ID:         p.ID,
+		Author:     p.Author,
+		ImageURL:   ImageURL(p.ID, p.ImageVersion),
+		Status:     string(p.Status),
+		UserID:     p.UserID,
+		CreatedAt:  p.CreatedAt,
+		OwnerToken: p.OwnerToken,
+	}
+}
+
+// ToModerationEventResponse преобразует запись журнала модерации в DTO.
+func ToModerationEventResponse(e domain.ModerationEvent) ModerationEventResponse {
+	return ModerationEventResponse{
+		ID:         e.ID,
+		PlantID:    e.PlantID,
+		Action:     string(e.Action),
+		Actor:      e.Actor,
+		Reason:     e.Reason,
+		FromStatus: string(e.FromStatus),
+		ToStatus:   string(e.ToStatus),
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// ToReportSummaryResponse преобразует открытые жалобы на растение в DTO.
+func ToReportSummaryResponse(s domain.ReportSummary) ReportSummaryResponse {
+	reasons := make(map[string]int, len(s.Reasons))
+	for reason, count := range s.Reasons {
+		reasons[string(reason)] = count
+	}
+	return ReportSummaryResponse{
+		PlantID:         s.PlantID,
+		Status:          string(s.PlantStatus),
+		Count:           s.Count,
+		Reasons:         reasons,
+		FirstReportedAt: s.FirstAt,
+		LastReportedAt:  s.LastAt,
+	}
+}
+
+// ToDuplicateClusterResponse преобразует группу похожих растений в DTO.
+func ToDuplicateClusterResponse(c domain.DuplicateCluster) DuplicateClusterResponse {
+	plants := make([]DuplicatePlantResponse, len(c.Plants))
+	for i, p := range c.Plants {
+		plants[i] = DuplicatePlantResponse{
+			ID:          p.ID,
+			Author:      p.Author,
+			Status:      string(p.Status),
+			ImageURL:    ImageURL(p.ID, p.ImageVersion),
+			ContentHash: p.Fingerprint.ContentHash,
+			CreatedAt:   p.CreatedAt,
+		}
+	}
+	return DuplicateClusterResponse{Exact: c.Exact, Plants: plants}
+}
+
+// ToUserResponse преобразует пользователя в DTO.
+func ToUserResponse(u userDomain.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+// ToSessionResponse преобразует сессию и ее пользователя в DTO.
+func ToSessionResponse(s userDomain.Session, u userDomain.User) SessionResponse {
+	return SessionResponse{
+		User:      ToUserResponse(u),
+		CSRFToken: s.CSRFToken,
+		ExpiresAt: s.ExpiresAt,
 	}
 }
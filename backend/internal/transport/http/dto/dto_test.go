@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"image/color"
 	"testing"
 	"time"
 
@@ -26,6 +27,33 @@ func TestToPlantResponse(t *testing.T) {
 				ID:        123,
 				Author:    "test_author",
 				ImageData: "base64_image_data",
+				ImageURL:  "/v1/plants/123/image.png",
+				CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "plant stored as grid",
+			plant: domain.Plant{
+				ID:     7,
+				Author: "grid_author",
+				Grid: &domain.Grid{
+					Width:   2,
+					Height:  1,
+					Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+					Pixels:  []uint8{1, 0},
+				},
+				CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+			expected: PlantResponse{
+				ID:       7,
+				Author:   "grid_author",
+				ImageURL: "/v1/plants/7/image.png",
+				Grid: &GridPayload{
+					Width:   2,
+					Height:  1,
+					Palette: []string{"#00000000", "#228b22"},
+					Pixels:  []int{1, 0},
+				},
 				CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
 			},
 		},
@@ -41,6 +69,7 @@ func TestToPlantResponse(t *testing.T) {
 				ID:        0,
 				Author:    "",
 				ImageData: "",
+				ImageURL:  "/v1/plants/0/image.png",
 				CreatedAt: time.Time{},
 			},
 		},
@@ -52,11 +81,86 @@ func TestToPlantResponse(t *testing.T) {
 			result := ToPlantResponse(tt.plant)
 
 			// Assert
+			if tt.plant.Grid != nil {
+				// PNG для сетки рисуется на сервере и должен декодироваться обратно в ту же сетку
+				img, err := domain.DecodeImage(result.ImageData, domain.DefaultImageLimits)
+				assert.NoError(t, err)
+				assert.Equal(t, 20, img.Bounds().Dx())
+				result.ImageData = ""
+			}
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestToPlantResponseWithoutImage(t *testing.T) {
+	plant := domain.Plant{
+		ID:        42,
+		Author:    "test_author",
+		ImageData: "base64_image_data",
+		Grid:      &domain.Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{}}, Pixels: []uint8{0}},
+		CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	result := ToPlantResponseWithoutImage(plant)
+
+	assert.Equal(t, PlantResponse{
+		ID:        42,
+		Author:    "test_author",
+		ImageURL:  "/v1/plants/42/image.png",
+		CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	}, result)
+}
+
+func TestToPlantResponseWithoutImage_EditedPlant(t *testing.T) {
+	plant := domain.Plant{ID: 42, Author: "test_author", ImageVersion: 3, OwnerToken: "secret"}
+
+	result := ToPlantResponseWithoutImage(plant)
+
+	assert.Equal(t, "/v1/plants/42/image.png?v=3", result.ImageURL)
+	assert.Equal(t, "secret", result.OwnerToken)
+}
+
+func TestGridPayload_ToDomain(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       GridPayload
+		expectedError bool
+	}{
+		{
+			name:    "valid grid",
+			payload: GridPayload{Width: 2, Height: 1, Palette: []string{"#00000000", "#228b22"}, Pixels: []int{0, 1}},
+		},
+		{
+			name:          "invalid color",
+			payload:       GridPayload{Width: 1, Height: 1, Palette: []string{"green"}, Pixels: []int{0}},
+			expectedError: true,
+		},
+		{
+			name:          "pixel outside palette",
+			payload:       GridPayload{Width: 1, Height: 1, Palette: []string{"#228b22"}, Pixels: []int{1}},
+			expectedError: true,
+		},
+		{
+			name:          "negative pixel",
+			payload:       GridPayload{Width: 1, Height: 1, Palette: []string{"#228b22"}, Pixels: []int{-1}},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid, err := tt.payload.ToDomain()
+			if tt.expectedError {
+				assert.ErrorIs(t, err, domain.ErrGridInvalid)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.payload, ToGridPayload(grid))
+		})
+	}
+}
+
 func TestCreatePlantRequest_Validation(t *testing.T) {
 	tests := []struct {
 		name    string
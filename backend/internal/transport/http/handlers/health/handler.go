@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	healthcheck "github.com/heartmarshall/digital-forest/backend/internal/health"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ReadinessChecker - интерфейс проверки готовности сервиса (см. health.Checker).
+type ReadinessChecker interface {
+	Ready(ctx context.Context) healthcheck.Report
+}
+
+// HealthHandler - HTTP обработчик проб живости и готовности для оркестратора.
+type HealthHandler struct {
+	checker ReadinessChecker
+}
+
+// NewHealthHandler - конструктор для хендлера.
+func NewHealthHandler(checker ReadinessChecker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Liveness - обработчик для GET /healthz. Отвечает, пока процесс обслуживает запросы;
+// от базы и других зависимостей не зависит, чтобы их сбой не приводил к перезапуску сервиса.
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	cerror.WriteJSON(w, http.StatusOK, healthcheck.Report{Status: healthcheck.StatusOK})
+}
+
+// Readiness - обработчик для GET /readyz. Возвращает результат каждой проверки;
+// статус 503, если хотя бы один компонент не готов или сервис останавливается.
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != healthcheck.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	// Пробы повторяются часто, результат не должен кэшироваться.
+	w.Header().Set("Cache-Control", "no-store")
+	cerror.WriteJSON(w, status, report)
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	healthcheck "github.com/heartmarshall/digital-forest/backend/internal/health"
+)
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	// Arrange: liveness не выполняет проверки, даже если они падают
+	checker := healthcheck.New(healthcheck.WithCheck("database", func(context.Context) error {
+		return errors.New("connection refused")
+	}))
+	handler := NewHealthHandler(checker)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkErr       error
+		drain          bool
+		expectedStatus int
+		expectedBody   healthcheck.Status
+	}{
+		{name: "ready", expectedStatus: http.StatusOK, expectedBody: healthcheck.StatusOK},
+		{name: "component failing", checkErr: errors.New("connection refused"), expectedStatus: http.StatusServiceUnavailable, expectedBody: healthcheck.StatusFail},
+		{name: "draining", drain: true, expectedStatus: http.StatusServiceUnavailable, expectedBody: healthcheck.StatusDraining},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			checker := healthcheck.New(healthcheck.WithCheck("database", func(context.Context) error {
+				return tt.checkErr
+			}))
+			if tt.drain {
+				checker.Drain()
+			}
+			handler := NewHealthHandler(checker)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var report healthcheck.Report
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.expectedBody, report.Status)
+			if tt.drain {
+				assert.Empty(t, report.Components)
+				return
+			}
+			require.Contains(t, report.Components, "database")
+			if tt.checkErr != nil {
+				assert.Equal(t, tt.checkErr.Error(), report.Components["database"].Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package challenge
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ChallengeUseCase - интерфейс для use case выдачи задач proof-of-work.
+type ChallengeUseCase interface {
+	Issue(ctx context.Context) (challengeUseCase.Challenge, error)
+}
+
+// ChallengeHandler - HTTP обработчик для задач proof-of-work.
+type ChallengeHandler struct {
+	uc ChallengeUseCase
+}
+
+// NewChallengeHandler - конструктор для хендлера.
+func NewChallengeHandler(uc ChallengeUseCase) *ChallengeHandler {
+	return &ChallengeHandler{
+		uc: uc,
+	}
+}
+
+// IssueChallenge - обработчик для GET /v1/challenges
+func (h *ChallengeHandler) IssueChallenge(w http.ResponseWriter, r *http.Request) {
+	challenge, err := h.uc.Issue(r.Context())
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	// Каждая задача одноразовая, поэтому ответ нельзя кешировать.
+	w.Header().Set("Cache-Control", "no-store")
+	cerror.WriteJSON(w, http.StatusOK, dto.ChallengeResponse{
+		Challenge:  challenge.Token,
+		Algorithm:  "sha256",
+		Difficulty: challenge.Difficulty,
+		ExpiresAt:  challenge.ExpiresAt,
+	})
+}
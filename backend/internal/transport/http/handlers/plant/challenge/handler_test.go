@@ -0,0 +1,95 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockChallengeUseCase - мок для ChallengeUseCase
+type MockChallengeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockChallengeUseCase) Issue(ctx context.Context) (challengeUseCase.Challenge, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(challengeUseCase.Challenge), args.Error(1)
+}
+
+func TestChallengeHandler_IssueChallenge(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 16, 12, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockChallengeUseCase)
+		expectedStatus int
+	}{
+		{
+			name: "successful issue",
+			mockSetup: func(mockUC *MockChallengeUseCase) {
+				mockUC.On("Issue", mock.Anything).
+					Return(challengeUseCase.Challenge{Token: "token", Difficulty: 18, ExpiresAt: expiresAt}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "use case error",
+			mockSetup: func(mockUC *MockChallengeUseCase) {
+				mockUC.On("Issue", mock.Anything).Return(challengeUseCase.Challenge{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockChallengeUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewChallengeHandler(mockUC)
+			req := httptest.NewRequest(http.MethodGet, "/v1/challenges", nil)
+			rr := httptest.NewRecorder()
+
+			// Act
+			handler.IssueChallenge(rr, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockUC.AssertExpectations(t)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			var response dto.ChallengeResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, dto.ChallengeResponse{
+				Challenge:  "token",
+				Algorithm:  "sha256",
+				Difficulty: 18,
+				ExpiresAt:  expiresAt,
+			}, response)
+		})
+	}
+}
+
+func TestNewChallengeHandler(t *testing.T) {
+	// Arrange
+	mockUC := &MockChallengeUseCase{}
+
+	// Act
+	handler := NewChallengeHandler(mockUC)
+
+	// Assert
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
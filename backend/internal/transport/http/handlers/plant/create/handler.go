@@ -6,7 +6,10 @@ import (
 	"net/http"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
 	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
 )
 
 // Validator - интерфейс для валидации.
@@ -17,58 +20,77 @@ type Validator interface {
 // CreateUseCase - интерфейс для use case создания растения.
 type CreateUseCase interface {
 	Create(ctx context.Context, author, imageData string) (domain.Plant, error)
+	CreateFromGrid(ctx context.Context, author string, grid domain.Grid) (domain.Plant, error)
+}
+
+// ChallengeVerifier - интерфейс для проверки решения задачи proof-of-work.
+type ChallengeVerifier interface {
+	Verify(ctx context.Context, token, solution string) error
 }
 
 // CreateHandler - HTTP обработчик для создания растения.
 type CreateHandler struct {
-	uc        CreateUseCase
-	validator Validator
+	uc         CreateUseCase
+	validator  Validator
+	challenges ChallengeVerifier
 }
 
 // NewCreateHandler - конструктор для хендлера.
-func NewCreateHandler(uc CreateUseCase, validator Validator) *CreateHandler {
+// Если challenges не nil, анонимная посадка требует решенной задачи proof-of-work.
+func NewCreateHandler(uc CreateUseCase, validator Validator, challenges ChallengeVerifier) *CreateHandler {
 	return &CreateHandler{
-		uc:        uc,
-		validator: validator,
+		uc:         uc,
+		validator:  validator,
+		challenges: challenges,
 	}
 }
 
 // CreatePlant - обработчик для POST /v1/plants
 func (h *CreateHandler) CreatePlant(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreatePlantRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid JSON format"})
+	_, span := tracing.Start(r.Context(), "decode request")
+	err := json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
 		return
 	}
 
 	// Выполняем автоматическую валидацию
-	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
-		respondJSON(w, http.StatusBadRequest, validationErrors)
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
 		return
 	}
 
-	// Создаем растение через use case
-	plant, err := h.uc.Create(r.Context(), req.Author, req.ImageData)
+	// Задача проверяется до разбора изображения: это самая дешевая для сервера проверка.
+	// Вошедшие пользователи ее не решают - их сдерживают сессия и лимиты запросов.
+	if _, loggedIn := userDomain.FromContext(r.Context()); h.challenges != nil && !loggedIn {
+		if err := h.challenges.Verify(r.Context(), req.Challenge, req.Solution); err != nil {
+			cerror.Respond(w, r, err)
+			return
+		}
+	}
+
+	// Создаем растение через use case: из сетки, если она передана, иначе из PNG
+	var plant domain.Plant
+	if req.Grid != nil {
+		var grid domain.Grid
+		grid, err = req.Grid.ToDomain()
+		if err == nil {
+			plant, err = h.uc.CreateFromGrid(r.Context(), req.Author, grid)
+		}
+	} else {
+		plant, err = h.uc.Create(r.Context(), req.Author, req.ImageData)
+	}
 	if err != nil {
-		// В реальном приложении здесь можно добавить более детальную обработку ошибок
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to create plant"})
+		cerror.Respond(w, r, err)
 		return
 	}
 
 	// Преобразуем доменную модель в DTO для ответа
 	response := dto.ToPlantResponse(plant)
-	respondJSON(w, http.StatusCreated, response)
-}
-
-// respondJSON - хелпер для отправки JSON-ответов.
-func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
-	response, err := json.Marshal(payload)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	w.Write(response)
+	cerror.WriteJSON(w, http.StatusCreated, response)
 }
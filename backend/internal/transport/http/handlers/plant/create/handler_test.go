@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"image/color"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
 	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
 	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,6 +31,21 @@ func (m *MockCreateUseCase) Create(ctx context.Context, author, imageData string
 	return args.Get(0).(domain.Plant), args.Error(1)
 }
 
+func (m *MockCreateUseCase) CreateFromGrid(ctx context.Context, author string, grid domain.Grid) (domain.Plant, error) {
+	args := m.Called(ctx, author, grid)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+// MockChallengeVerifier - мок для ChallengeVerifier
+type MockChallengeVerifier struct {
+	mock.Mock
+}
+
+func (m *MockChallengeVerifier) Verify(ctx context.Context, token, solution string) error {
+	args := m.Called(ctx, token, solution)
+	return args.Error(0)
+}
+
 func TestCreateHandler_CreatePlant(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -53,6 +73,48 @@ func TestCreateHandler_CreatePlant(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedError:  false,
 		},
+		{
+			name: "successful plant creation from grid",
+			requestBody: dto.CreatePlantRequest{
+				Author: "test_author",
+				Grid: &dto.GridPayload{
+					Width:   2,
+					Height:  1,
+					Palette: []string{"transparent", "#228b22"},
+					Pixels:  []int{0, 1},
+				},
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				grid := domain.Grid{
+					Width:   2,
+					Height:  1,
+					Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+					Pixels:  []uint8{0, 1},
+				}
+				mockUC.On("CreateFromGrid", mock.Anything, "test_author", grid).
+					Return(domain.Plant{ID: 1, Author: "test_author", ImageData: "base64_image_data", Grid: &grid}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedError:  false,
+		},
+		{
+			name: "grid with unknown color",
+			requestBody: dto.CreatePlantRequest{
+				Author: "test_author",
+				Grid: &dto.GridPayload{
+					Width:   1,
+					Height:  1,
+					Palette: []string{"green"},
+					Pixels:  []int{0},
+				},
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  true,
+		},
 		{
 			name:        "invalid JSON",
 			requestBody: "invalid json",
@@ -90,6 +152,62 @@ func TestCreateHandler_CreatePlant(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  true,
 		},
+		{
+			name: "image is not base64",
+			requestBody: dto.CreatePlantRequest{
+				Author:    "test_author",
+				ImageData: "base64_image_data",
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Create", mock.Anything, "test_author", "base64_image_data").
+					Return(domain.Plant{}, fmt.Errorf("%w: bad input", domain.ErrImageEncoding))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name: "image is too large",
+			requestBody: dto.CreatePlantRequest{
+				Author:    "test_author",
+				ImageData: "base64_image_data",
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Create", mock.Anything, "test_author", "base64_image_data").
+					Return(domain.Plant{}, fmt.Errorf("%w: too big", domain.ErrImageTooLarge))
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  true,
+		},
+		{
+			name: "image is not aligned to the grid",
+			requestBody: dto.CreatePlantRequest{
+				Author:    "test_author",
+				ImageData: "base64_image_data",
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Create", mock.Anything, "test_author", "base64_image_data").
+					Return(domain.Plant{}, fmt.Errorf("%w: misaligned", domain.ErrImageGrid))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  true,
+		},
+		{
+			name: "duplicate image",
+			requestBody: dto.CreatePlantRequest{
+				Author:    "test_author",
+				ImageData: "base64_image_data",
+			},
+			mockSetup: func(mockUC *MockCreateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Create", mock.Anything, "test_author", "base64_image_data").
+					Return(domain.Plant{}, fmt.Errorf("%w: plant 3 is 0 bits away", domain.ErrDuplicate))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -99,7 +217,7 @@ func TestCreateHandler_CreatePlant(t *testing.T) {
 			mockValidator := testutil.NewMockValidator()
 			tt.mockSetup(mockUC, mockValidator)
 
-			handler := NewCreateHandler(mockUC, mockValidator)
+			handler := NewCreateHandler(mockUC, mockValidator, nil)
 
 			var reqBody []byte
 			if tt.requestBody != nil {
@@ -122,12 +240,11 @@ func TestCreateHandler_CreatePlant(t *testing.T) {
 				var response map[string]interface{}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				// For validation errors, the response contains field-specific errors
-				// For other errors, it contains an "error" field
+				assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
+				// Ошибки валидации приходят в fields
 				if tt.name == "validation error" {
-					assert.Contains(t, response, "Author")
-				} else {
-					assert.Contains(t, response, "error")
+					assert.Contains(t, response["fields"], "Author")
 				}
 			} else {
 				var response dto.PlantResponse
@@ -144,13 +261,110 @@ func TestCreateHandler_CreatePlant(t *testing.T) {
 	}
 }
 
+// TestCreateHandler_CreatePlant_AuthorRejected проверяет, что отклоненное имя автора
+// возвращается ошибкой поля в том же виде, что и ошибки валидации запроса.
+func TestCreateHandler_CreatePlant_AuthorRejected(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedMessage string
+	}{
+		{name: "empty", err: domain.ErrAuthorEmpty, expectedMessage: "field 'author' is required"},
+		{name: "too long", err: domain.ErrAuthorTooLong, expectedMessage: "field 'author' is too long (max: 255)"},
+		{name: "blocked", err: domain.ErrAuthorBlocked, expectedMessage: "field 'author' is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockCreateUseCase{}
+			mockValidator := testutil.NewMockValidator()
+			mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+			mockUC.On("Create", mock.Anything, "admin", "base64_image_data").Return(domain.Plant{}, tt.err)
+			handler := NewCreateHandler(mockUC, mockValidator, nil)
+
+			reqBody, _ := json.Marshal(dto.CreatePlantRequest{Author: "admin", ImageData: "base64_image_data"})
+			req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(reqBody))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CreatePlant(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response cerror.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, map[string]string{"author": tt.expectedMessage}, response.Fields)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateHandler_CreatePlant_Challenge(t *testing.T) {
+	tests := []struct {
+		name           string
+		loggedIn       bool
+		verifyErr      error
+		expectVerify   bool
+		expectedStatus int
+	}{
+		{name: "solved challenge", expectVerify: true, expectedStatus: http.StatusCreated},
+		{name: "logged in user skips challenge", loggedIn: true, expectedStatus: http.StatusCreated},
+		{name: "missing challenge", verifyErr: challengeUseCase.ErrRequired, expectVerify: true, expectedStatus: http.StatusBadRequest},
+		{name: "wrong solution", verifyErr: challengeUseCase.ErrUnsolved, expectVerify: true, expectedStatus: http.StatusForbidden},
+		{name: "replayed challenge", verifyErr: challengeUseCase.ErrReplayed, expectVerify: true, expectedStatus: http.StatusForbidden},
+		{name: "expired challenge", verifyErr: challengeUseCase.ErrExpired, expectVerify: true, expectedStatus: http.StatusForbidden},
+		{name: "verifier error", verifyErr: assert.AnError, expectVerify: true, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockCreateUseCase{}
+			mockValidator := testutil.NewMockValidator()
+			mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+			mockVerifier := &MockChallengeVerifier{}
+			if tt.expectVerify {
+				mockVerifier.On("Verify", mock.Anything, "token", "solution").Return(tt.verifyErr)
+			}
+			if tt.verifyErr == nil {
+				mockUC.On("Create", mock.Anything, "test_author", "base64_image_data").
+					Return(domain.Plant{ID: 1, Author: "test_author"}, nil)
+			}
+			handler := NewCreateHandler(mockUC, mockValidator, mockVerifier)
+
+			reqBody, _ := json.Marshal(dto.CreatePlantRequest{
+				Author:    "test_author",
+				ImageData: "base64_image_data",
+				Challenge: "token",
+				Solution:  "solution",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(reqBody))
+			if tt.loggedIn {
+				req = req.WithContext(userDomain.WithUser(req.Context(), userDomain.User{ID: 7}))
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CreatePlant(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+			mockVerifier.AssertExpectations(t)
+		})
+	}
+}
+
 func TestNewCreateHandler(t *testing.T) {
 	mockUC := &MockCreateUseCase{}
 	mockValidator := testutil.NewMockValidator()
+	mockVerifier := &MockChallengeVerifier{}
 
-	handler := NewCreateHandler(mockUC, mockValidator)
+	handler := NewCreateHandler(mockUC, mockValidator, mockVerifier)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, mockUC, handler.uc)
 	assert.Equal(t, mockValidator, handler.validator)
+	assert.Equal(t, mockVerifier, handler.challenges)
 }
@@ -0,0 +1,75 @@
+package duplicates
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+const defaultClusterLimit = 50
+const maxClusterLimit = 200
+
+// maxDistance - больше половины из 64 бит отличаются уже у случайных изображений.
+const maxDistance = 32
+
+// DuplicatesUseCase - интерфейс для use case поиска похожих растений.
+type DuplicatesUseCase interface {
+	Clusters(ctx context.Context, maxDistance, limit int) ([]domain.DuplicateCluster, error)
+}
+
+// DuplicatesHandler - HTTP обработчик списка групп похожих растений для модераторов.
+type DuplicatesHandler struct {
+	uc DuplicatesUseCase
+}
+
+// NewDuplicatesHandler - конструктор для хендлера.
+func NewDuplicatesHandler(uc DuplicatesUseCase) *DuplicatesHandler {
+	return &DuplicatesHandler{
+		uc: uc,
+	}
+}
+
+// ListClusters - обработчик для GET /v1/admin/duplicates
+func (h *DuplicatesHandler) ListClusters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultClusterLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid limit parameter. Must be a positive integer"))
+			return
+		}
+		limit = min(parsed, maxClusterLimit)
+	}
+
+	// Без параметра используется порог из настроек.
+	distance := -1
+	if distanceStr := query.Get("distance"); distanceStr != "" {
+		parsed, err := strconv.Atoi(distanceStr)
+		if err != nil || parsed < 0 || parsed > maxDistance {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid distance parameter. Must be an integer from 0 to 32"))
+			return
+		}
+		distance = parsed
+	}
+
+	clusters, err := h.uc.Clusters(r.Context(), distance, limit)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	responses := make([]dto.DuplicateClusterResponse, len(clusters))
+	for i, c := range clusters {
+		responses[i] = dto.ToDuplicateClusterResponse(c)
+	}
+	cerror.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"clusters": responses,
+		"count":    len(responses),
+	})
+}
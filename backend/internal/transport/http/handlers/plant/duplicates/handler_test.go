@@ -0,0 +1,128 @@
+package duplicates
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockDuplicatesUseCase - мок для DuplicatesUseCase
+type MockDuplicatesUseCase struct {
+	mock.Mock
+}
+
+func (m *MockDuplicatesUseCase) Clusters(ctx context.Context, maxDistance, limit int) ([]domain.DuplicateCluster, error) {
+	args := m.Called(ctx, maxDistance, limit)
+	return args.Get(0).([]domain.DuplicateCluster), args.Error(1)
+}
+
+func TestDuplicatesHandler_ListClusters(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	clusters := []domain.DuplicateCluster{{
+		Exact: true,
+		Plants: []domain.FingerprintedPlant{
+			{ID: 3, Author: "first", Status: domain.StatusVisible, ImageVersion: 1, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "abc"}},
+			{ID: 9, Author: "copy", Status: domain.StatusPending, ImageVersion: 2, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "abc"}},
+		},
+	}}
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*MockDuplicatesUseCase)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "configured distance and default limit",
+			mockSetup: func(mockUC *MockDuplicatesUseCase) {
+				mockUC.On("Clusters", mock.Anything, -1, 50).Return(clusters, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:        "explicit distance, limit capped",
+			queryParams: "?distance=0&limit=1000",
+			mockSetup: func(mockUC *MockDuplicatesUseCase) {
+				mockUC.On("Clusters", mock.Anything, 0, 200).Return([]domain.DuplicateCluster{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid distance",
+			queryParams:    "?distance=64",
+			mockSetup:      func(mockUC *MockDuplicatesUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			queryParams:    "?limit=abc",
+			mockSetup:      func(mockUC *MockDuplicatesUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "use case error",
+			mockSetup: func(mockUC *MockDuplicatesUseCase) {
+				mockUC.On("Clusters", mock.Anything, -1, 50).Return([]domain.DuplicateCluster{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockDuplicatesUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewDuplicatesHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/duplicates"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListClusters(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Clusters []dto.DuplicateClusterResponse `json:"clusters"`
+					Count    int                            `json:"count"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCount, response.Count)
+				if tt.expectedCount > 0 {
+					assert.True(t, response.Clusters[0].Exact)
+					assert.Equal(t, dto.DuplicatePlantResponse{
+						ID:          9,
+						Author:      "copy",
+						Status:      "pending",
+						ImageURL:    dto.ImageURL(9, 2),
+						ContentHash: "abc",
+						CreatedAt:   now,
+					}, response.Clusters[0].Plants[1])
+				}
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewDuplicatesHandler(t *testing.T) {
+	mockUC := &MockDuplicatesUseCase{}
+
+	handler := NewDuplicatesHandler(mockUC)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
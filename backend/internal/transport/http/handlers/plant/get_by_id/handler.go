@@ -0,0 +1,47 @@
+package get_by_id
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// GetByIDUseCase - интерфейс для use case получения растения по идентификатору.
+type GetByIDUseCase interface {
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+}
+
+// GetByIDHandler - HTTP обработчик для получения одного растения.
+type GetByIDHandler struct {
+	uc GetByIDUseCase
+}
+
+// NewGetByIDHandler - конструктор для хендлера.
+func NewGetByIDHandler(uc GetByIDUseCase) *GetByIDHandler {
+	return &GetByIDHandler{
+		uc: uc,
+	}
+}
+
+// GetPlant - обработчик для GET /v1/plants/{id}
+func (h *GetByIDHandler) GetPlant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return
+	}
+
+	plant, err := h.uc.GetByID(r.Context(), id)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusOK, dto.ToPlantResponse(plant))
+}
@@ -0,0 +1,119 @@
+package get_by_id
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGetByIDUseCase - мок для GetByIDUseCase
+type MockGetByIDUseCase struct {
+	mock.Mock
+}
+
+func (m *MockGetByIDUseCase) GetByID(ctx context.Context, id int) (domain.Plant, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func TestGetByIDHandler_GetPlant(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*MockGetByIDUseCase)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name: "successful get",
+			id:   "1",
+			mockSetup: func(mockUC *MockGetByIDUseCase) {
+				mockUC.On("GetByID", mock.Anything, 1).
+					Return(domain.Plant{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(mockUC *MockGetByIDUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name: "plant not found",
+			id:   "2",
+			mockSetup: func(mockUC *MockGetByIDUseCase) {
+				mockUC.On("GetByID", mock.Anything, 2).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name: "use case error",
+			id:   "3",
+			mockSetup: func(mockUC *MockGetByIDUseCase) {
+				mockUC.On("GetByID", mock.Anything, 3).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockGetByIDUseCase{}
+			tt.mockSetup(mockUC)
+
+			handler := NewGetByIDHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetPlant(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError {
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
+			} else {
+				var response dto.PlantResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, response.ID)
+				assert.Equal(t, "author1", response.Author)
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewGetByIDHandler(t *testing.T) {
+	mockUC := &MockGetByIDUseCase{}
+
+	handler := NewGetByIDHandler(mockUC)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
@@ -0,0 +1,109 @@
+package get_image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+const defaultImageScale = 1
+const maxImageScale = 32
+
+// cacheControl - изображение по адресу своей версии не меняется, но модератор может скрыть или удалить растение.
+// Поэтому кеши держат его недолго и затем перепроверяют по ETag: скрытое растение пропадает из них за max-age.
+const cacheControl = "public, max-age=300"
+
+// cacheControlRevalidate - по адресу устаревшей версии отдается текущее изображение, и кеш обязан его перепроверять.
+const cacheControlRevalidate = "public, no-cache"
+
+// GetImageUseCase - интерфейс для use case получения изображения растения.
+type GetImageUseCase interface {
+	GetImage(ctx context.Context, id, scale int) (getImageUseCase.Image, error)
+}
+
+// GetImageHandler - HTTP обработчик для получения изображения растения.
+type GetImageHandler struct {
+	uc GetImageUseCase
+}
+
+// NewGetImageHandler - конструктор для хендлера.
+func NewGetImageHandler(uc GetImageUseCase) *GetImageHandler {
+	return &GetImageHandler{
+		uc: uc,
+	}
+}
+
+// GetPlantImage - обработчик для GET /v1/plants/{id}/image.png
+func (h *GetImageHandler) GetPlantImage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return
+	}
+
+	scale := defaultImageScale
+	if scaleStr := r.URL.Query().Get("scale"); scaleStr != "" {
+		scale, err = strconv.Atoi(scaleStr)
+		if err != nil || scale <= 0 || scale > maxImageScale {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid scale parameter. Must be an integer from 1 to "+strconv.Itoa(maxImageScale)))
+			return
+		}
+	}
+
+	result, err := h.uc.GetImage(r.Context(), id, scale)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	image := result.PNG
+
+	// Сильный ETag по содержимому: одинаковые байты - одинаковый тег.
+	sum := sha256.Sum256(image)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if isCurrentVersion(r.URL.Query().Get("v"), result.Version) {
+		w.Header().Set("Cache-Control", cacheControl)
+	} else {
+		w.Header().Set("Cache-Control", cacheControlRevalidate)
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+}
+
+// isCurrentVersion проверяет, что запрошен адрес текущей версии изображения (см. dto.ImageURL):
+// у неизмененного растения это адрес без параметра v, у измененного - с v, равным версии.
+func isCurrentVersion(param string, version int) bool {
+	if param == "" {
+		return version <= 1
+	}
+	return param == strconv.Itoa(version)
+}
+
+// etagMatches проверяет заголовок If-None-Match: список тегов через запятую или "*".
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
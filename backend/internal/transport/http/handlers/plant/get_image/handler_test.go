@@ -0,0 +1,248 @@
+package get_image
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGetImageUseCase - мок для GetImageUseCase
+type MockGetImageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockGetImageUseCase) GetImage(ctx context.Context, id, scale int) (getImageUseCase.Image, error) {
+	args := m.Called(ctx, id, scale)
+	return args.Get(0).(getImageUseCase.Image), args.Error(1)
+}
+
+func TestGetImageHandler_GetPlantImage(t *testing.T) {
+	image := []byte("\x89PNG\r\n\x1a\nfake png body")
+	result := getImageUseCase.Image{PNG: image, Version: 1}
+	etag := `"ecf8b4c7b9d8f6c0a1b7e1c7c4e5cbd6"`
+
+	tests := []struct {
+		name           string
+		id             string
+		queryParams    string
+		ifNoneMatch    string
+		mockSetup      func(*MockGetImageUseCase)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name: "successful get with default scale",
+			id:   "1",
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 1, 1).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "successful get with custom scale",
+			id:          "1",
+			queryParams: "?scale=10",
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 1, 10).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "etag matches",
+			id:          "1",
+			ifNoneMatch: "__ETAG__",
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 1, 1).Return(result, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:        "etag does not match",
+			id:          "1",
+			ifNoneMatch: etag,
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 1, 1).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			mockSetup:      func(mockUC *MockGetImageUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "scale too large",
+			id:             "1",
+			queryParams:    "?scale=33",
+			mockSetup:      func(mockUC *MockGetImageUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "zero scale",
+			id:             "1",
+			queryParams:    "?scale=0",
+			mockSetup:      func(mockUC *MockGetImageUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name: "plant not found",
+			id:   "404",
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 404, 1).Return(getImageUseCase.Image{}, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name: "use case error",
+			id:   "1",
+			mockSetup: func(mockUC *MockGetImageUseCase) {
+				mockUC.On("GetImage", mock.Anything, 1, 1).Return(getImageUseCase.Image{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockGetImageUseCase{}
+			tt.mockSetup(mockUC)
+
+			handler := NewGetImageHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants/"+tt.id+"/image.png"+tt.queryParams, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tt.ifNoneMatch == "__ETAG__" {
+				// Сначала получаем ETag обычным запросом, затем повторяем запрос с ним
+				first := httptest.NewRecorder()
+				handler.GetPlantImage(first, req)
+				req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+			} else if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetPlantImage(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError {
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
+				assert.Empty(t, w.Header().Get("ETag"))
+			} else {
+				assert.NotEmpty(t, w.Header().Get("ETag"))
+				assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+				if tt.expectedStatus == http.StatusOK {
+					assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+					assert.Equal(t, image, w.Body.Bytes())
+				} else {
+					assert.Empty(t, w.Body.Bytes())
+				}
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewGetImageHandler(t *testing.T) {
+	mockUC := &MockGetImageUseCase{}
+
+	handler := NewGetImageHandler(mockUC)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
+
+func TestGetImageHandler_CacheControlByVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      int
+		queryParams  string
+		cacheControl string
+	}{
+		{name: "unchanged plant without version", version: 1, cacheControl: cacheControl},
+		{name: "current version", version: 2, queryParams: "?v=2", cacheControl: cacheControl},
+		{name: "edited plant without version", version: 2, cacheControl: cacheControlRevalidate},
+		{name: "stale version", version: 3, queryParams: "?v=2&scale=1", cacheControl: cacheControlRevalidate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockGetImageUseCase{}
+			mockUC.On("GetImage", mock.Anything, 1, 1).Return(getImageUseCase.Image{PNG: []byte("png"), Version: tt.version}, nil)
+			handler := NewGetImageHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants/1/image.png"+tt.queryParams, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetPlantImage(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestGetImageHandler_HiddenPlant(t *testing.T) {
+	// Arrange: растение отдано, затем модератор его скрыл и use case его больше не находит
+	mockUC := &MockGetImageUseCase{}
+	mockUC.On("GetImage", mock.Anything, 1, 1).Return(getImageUseCase.Image{PNG: []byte("png"), Version: 1}, nil).Once()
+	mockUC.On("GetImage", mock.Anything, 1, 1).Return(getImageUseCase.Image{}, domain.ErrNotFound).Once()
+	handler := NewGetImageHandler(mockUC)
+
+	request := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/plants/1/image.png", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.GetPlantImage(w, req)
+		return w
+	}
+
+	// Act
+	visible := request("")
+	hidden := request(visible.Header().Get("ETag"))
+
+	// Assert: кеш не может держать изображение вечно, а перепроверка скрытого растения не дает 304
+	assert.Equal(t, http.StatusOK, visible.Code)
+	assert.NotContains(t, visible.Header().Get("Cache-Control"), "immutable")
+	assert.Contains(t, visible.Header().Get("Cache-Control"), "max-age=300")
+	assert.Equal(t, http.StatusNotFound, hidden.Code)
+	assert.Empty(t, hidden.Header().Get("ETag"))
+	mockUC.AssertExpectations(t)
+}
@@ -2,20 +2,26 @@ package get_random
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
 	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
 )
 
 const defaultRandomCount = 15
 const maxRandomCount = 50
 
+// maxExcludeCount - сколько уже показанных растений клиент может перечислить в exclude.
+// Для длинной прокрутки предназначены seed и cursor.
+const maxExcludeCount = 500
+
 // GetRandomUseCase - интерфейс для use case получения случайных растений.
 type GetRandomUseCase interface {
-	GetRandom(ctx context.Context, count int) ([]domain.Plant, error)
+	GetRandom(ctx context.Context, filter domain.RandomFilter, cursor string) (getRandomUseCase.Page, error)
 }
 
 // GetRandomHandler - HTTP обработчик для получения случайных растений.
@@ -31,6 +37,8 @@ func NewGetRandomHandler(uc GetRandomUseCase) *GetRandomHandler {
 }
 
 // GetRandomPlants - обработчик для GET /v1/plants/random
+// С параметром seed выборка детерминирована и обходится страницами через cursor,
+// exclude - список id через запятую, которые не нужно возвращать.
 func (h *GetRandomHandler) GetRandomPlants(w http.ResponseWriter, r *http.Request) {
 	countStr := r.URL.Query().Get("count")
 	count := defaultRandomCount
@@ -39,9 +47,7 @@ func (h *GetRandomHandler) GetRandomPlants(w http.ResponseWriter, r *http.Reques
 		var err error
 		count, err = strconv.Atoi(countStr)
 		if err != nil || count <= 0 {
-			respondJSON(w, http.StatusBadRequest, map[string]string{
-				"error": "Invalid count parameter. Must be a positive integer",
-			})
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid count parameter. Must be a positive integer"))
 			return
 		}
 
@@ -50,32 +56,80 @@ func (h *GetRandomHandler) GetRandomPlants(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
-	plants, err := h.uc.GetRandom(r.Context(), count)
+	// inline=false убирает imageData и grid из ответа: клиент загрузит изображения по imageUrl.
+	inline := true
+	if inlineStr := r.URL.Query().Get("inline"); inlineStr != "" {
+		var err error
+		inline, err = strconv.ParseBool(inlineStr)
+		if err != nil {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid inline parameter. Must be true or false"))
+			return
+		}
+	}
+
+	filter := domain.RandomFilter{Count: count}
+
+	if seedStr := r.URL.Query().Get("seed"); seedStr != "" {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid seed parameter. Must be a 64-bit integer"))
+			return
+		}
+		filter.Seed = &seed
+	}
+
+	if excludeStr := r.URL.Query().Get("exclude"); excludeStr != "" {
+		exclude, err := parseExclude(excludeStr)
+		if err != nil {
+			cerror.Respond(w, r, err)
+			return
+		}
+		filter.Exclude = exclude
+	}
+
+	page, err := h.uc.GetRandom(r.Context(), filter, r.URL.Query().Get("cursor"))
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to get random plants"})
+		cerror.Respond(w, r, err)
 		return
 	}
 
-	responses := make([]dto.PlantResponse, len(plants))
-	for i, plant := range plants {
-		responses[i] = dto.ToPlantResponse(plant)
+	responses := make([]dto.PlantResponse, len(page.Plants))
+	for i, plant := range page.Plants {
+		if inline {
+			responses[i] = dto.ToPlantResponse(plant)
+		} else {
+			responses[i] = dto.ToPlantResponseWithoutImage(plant)
+		}
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
+	payload := map[string]interface{}{
 		"plants": responses,
 		"count":  len(responses),
-	})
+	}
+	if page.Seed != nil {
+		// Строкой, чтобы зерно не теряло точность в JavaScript.
+		payload["seed"] = strconv.FormatInt(*page.Seed, 10)
+	}
+	if page.NextCursor != "" {
+		payload["nextCursor"] = page.NextCursor
+	}
+	cerror.WriteJSON(w, http.StatusOK, payload)
 }
 
-// respondJSON - хелпер для отправки JSON-ответов.
-func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
-	response, err := json.Marshal(payload)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		return
+// parseExclude разбирает список id через запятую.
+func parseExclude(s string) ([]int, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) > maxExcludeCount {
+		return nil, cerror.New(cerror.Invalid, "Invalid exclude parameter. At most "+strconv.Itoa(maxExcludeCount)+" ids are allowed")
+	}
+
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, cerror.New(cerror.Invalid, "Invalid exclude parameter. Must be a comma-separated list of plant ids")
+		}
+		ids = append(ids, id)
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	w.Write(response)
+	return ids, nil
 }
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,9 +21,9 @@ type MockGetRandomUseCase struct {
 	mock.Mock
 }
 
-func (m *MockGetRandomUseCase) GetRandom(ctx context.Context, count int) ([]domain.Plant, error) {
-	args := m.Called(ctx, count)
-	return args.Get(0).([]domain.Plant), args.Error(1)
+func (m *MockGetRandomUseCase) GetRandom(ctx context.Context, filter domain.RandomFilter, cursor string) (getRandomUseCase.Page, error) {
+	args := m.Called(ctx, filter, cursor)
+	return args.Get(0).(getRandomUseCase.Page), args.Error(1)
 }
 
 func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
@@ -31,6 +34,8 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 		expectedStatus int
 		expectedCount  int
 		expectedError  bool
+		expectedSeed   string
+		expectedCursor string
 	}{
 		{
 			name:        "successful get with default count",
@@ -38,9 +43,9 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 			mockSetup: func(mockUC *MockGetRandomUseCase) {
 				expectedPlants := []domain.Plant{
 					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
-					{ID: 2, Author: "author2", ImageData: "data2", CreatedAt: time.Now()},
+					{ID: 1, Author: "author2", ImageData: "data1", CreatedAt: time.Now()},
 				}
-				mockUC.On("GetRandom", mock.Anything, 15).Return(expectedPlants, nil)
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 15}, "").Return(getRandomUseCase.Page{Plants: expectedPlants}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  2,
@@ -53,7 +58,7 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 				expectedPlants := []domain.Plant{
 					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
 				}
-				mockUC.On("GetRandom", mock.Anything, 5).Return(expectedPlants, nil)
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5}, "").Return(getRandomUseCase.Page{Plants: expectedPlants}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
@@ -84,17 +89,99 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 				expectedPlants := []domain.Plant{
 					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
 				}
-				mockUC.On("GetRandom", mock.Anything, 50).Return(expectedPlants, nil) // Should be capped at 50
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 50}, "").Return(getRandomUseCase.Page{Plants: expectedPlants}, nil) // Should be capped at 50
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  1,
 			expectedError:  false,
 		},
+		{
+			name:        "images by url only",
+			queryParams: "?count=5&inline=false",
+			mockSetup: func(mockUC *MockGetRandomUseCase) {
+				expectedPlants := []domain.Plant{
+					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
+				}
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5}, "").Return(getRandomUseCase.Page{Plants: expectedPlants}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+			expectedError:  false,
+		},
+		{
+			name:        "invalid inline parameter",
+			queryParams: "?inline=maybe",
+			mockSetup: func(mockUC *MockGetRandomUseCase) {
+				// No mocks needed for invalid inline
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:        "seeded page with next cursor",
+			queryParams: "?count=1&seed=-42&cursor=abc",
+			mockSetup: func(mockUC *MockGetRandomUseCase) {
+				seed := int64(-42)
+				expectedPlants := []domain.Plant{
+					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
+				}
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 1, Seed: &seed}, "abc").
+					Return(getRandomUseCase.Page{Plants: expectedPlants, Seed: &seed, NextCursor: "next"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+			expectedSeed:   "-42",
+			expectedCursor: "next",
+		},
+		{
+			name:        "exclude already seen plants",
+			queryParams: "?count=5&exclude=3,%204,5",
+			mockSetup: func(mockUC *MockGetRandomUseCase) {
+				expectedPlants := []domain.Plant{
+					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
+				}
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5, Exclude: []int{3, 4, 5}}, "").
+					Return(getRandomUseCase.Page{Plants: expectedPlants}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "invalid seed parameter",
+			queryParams:    "?seed=forest",
+			mockSetup:      func(mockUC *MockGetRandomUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid exclude parameter",
+			queryParams:    "?exclude=1,two",
+			mockSetup:      func(mockUC *MockGetRandomUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "too many excluded plants",
+			queryParams:    "?exclude=" + strings.Repeat("1,", maxExcludeCount) + "1",
+			mockSetup:      func(mockUC *MockGetRandomUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:        "invalid cursor",
+			queryParams: "?cursor=broken",
+			mockSetup: func(mockUC *MockGetRandomUseCase) {
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 15}, "broken").
+					Return(getRandomUseCase.Page{}, getRandomUseCase.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
 		{
 			name:        "use case error",
 			queryParams: "?count=5",
 			mockSetup: func(mockUC *MockGetRandomUseCase) {
-				mockUC.On("GetRandom", mock.Anything, 5).Return([]domain.Plant{}, assert.AnError)
+				mockUC.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5}, "").Return(getRandomUseCase.Page{}, assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  true,
@@ -122,7 +209,8 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 				var response map[string]interface{}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Contains(t, response, "error")
+				assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
 			} else {
 				var response map[string]interface{}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -130,6 +218,27 @@ func TestGetRandomHandler_GetRandomPlants(t *testing.T) {
 				assert.Contains(t, response, "plants")
 				assert.Contains(t, response, "count")
 				assert.Equal(t, float64(tt.expectedCount), response["count"])
+				if tt.expectedSeed != "" {
+					assert.Equal(t, tt.expectedSeed, response["seed"])
+				} else {
+					assert.NotContains(t, response, "seed")
+				}
+				if tt.expectedCursor != "" {
+					assert.Equal(t, tt.expectedCursor, response["nextCursor"])
+				} else {
+					assert.NotContains(t, response, "nextCursor")
+				}
+
+				plants := response["plants"].([]interface{})
+				for _, p := range plants {
+					plant := p.(map[string]interface{})
+					assert.Equal(t, "/v1/plants/1/image.png", plant["imageUrl"])
+					if tt.name == "images by url only" {
+						assert.NotContains(t, plant, "imageData")
+					} else {
+						assert.Equal(t, "data1", plant["imageData"])
+					}
+				}
 			}
 
 			mockUC.AssertExpectations(t)
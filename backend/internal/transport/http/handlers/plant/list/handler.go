@@ -0,0 +1,128 @@
+package list
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+const defaultListLimit = 20
+const maxListLimit = 100
+
+// ListUseCase - интерфейс для use case постраничного получения растений.
+type ListUseCase interface {
+	List(ctx context.Context, filter domain.ListFilter, cursor string) (listUseCase.Page, error)
+}
+
+// ListHandler - HTTP обработчик для списка растений.
+type ListHandler struct {
+	uc ListUseCase
+	// allowStatus разрешает фильтр status. Публичный список всегда показывает только видимые растения.
+	allowStatus bool
+}
+
+// NewListHandler - конструктор для хендлера.
+func NewListHandler(uc ListUseCase) *ListHandler {
+	return &ListHandler{
+		uc: uc,
+	}
+}
+
+// NewModerationListHandler - конструктор хендлера для модераторов: список понимает параметр status.
+func NewModerationListHandler(uc ListUseCase) *ListHandler {
+	return &ListHandler{
+		uc:          uc,
+		allowStatus: true,
+	}
+}
+
+// ListPlants - обработчик для GET /v1/plants
+func (h *ListHandler) ListPlants(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.ListFilter{
+		Author: query.Get("author"),
+		Limit:  defaultListLimit,
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid limit parameter. Must be a positive integer"))
+			return
+		}
+		filter.Limit = min(limit, maxListLimit)
+	}
+
+	if userIDStr := query.Get("userId"); userIDStr != "" {
+		userID, err := strconv.Atoi(userIDStr)
+		if err != nil || userID <= 0 {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid userId parameter. Must be a positive integer"))
+			return
+		}
+		filter.UserID = userID
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTime(query.Get("createdFrom")); err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid createdFrom parameter. Must be an RFC 3339 timestamp"))
+		return
+	}
+	if filter.CreatedTo, err = parseTime(query.Get("createdTo")); err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid createdTo parameter. Must be an RFC 3339 timestamp"))
+		return
+	}
+
+	if statusStr := query.Get("status"); statusStr != "" && h.allowStatus {
+		if filter.Status, err = domain.ParseStatus(statusStr); err != nil {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid status parameter. Must be one of visible, pending, hidden, deleted"))
+			return
+		}
+	}
+
+	// inline=false убирает imageData и grid из ответа: клиент загрузит изображения по imageUrl.
+	inline := true
+	if inlineStr := query.Get("inline"); inlineStr != "" {
+		if inline, err = strconv.ParseBool(inlineStr); err != nil {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid inline parameter. Must be true or false"))
+			return
+		}
+	}
+
+	page, err := h.uc.List(r.Context(), filter, query.Get("cursor"))
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	responses := make([]dto.PlantResponse, len(page.Plants))
+	for i, plant := range page.Plants {
+		if inline {
+			responses[i] = dto.ToPlantResponse(plant)
+		} else {
+			responses[i] = dto.ToPlantResponseWithoutImage(plant)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"plants": responses,
+		"count":  len(responses),
+	}
+	if page.NextCursor != "" {
+		payload["nextCursor"] = page.NextCursor
+	}
+	cerror.WriteJSON(w, http.StatusOK, payload)
+}
+
+// parseTime разбирает необязательный параметр в формате RFC 3339.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
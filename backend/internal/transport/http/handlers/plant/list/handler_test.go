@@ -0,0 +1,240 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockListUseCase - мок для ListUseCase
+type MockListUseCase struct {
+	mock.Mock
+}
+
+func (m *MockListUseCase) List(ctx context.Context, filter domain.ListFilter, cursor string) (listUseCase.Page, error) {
+	args := m.Called(ctx, filter, cursor)
+	return args.Get(0).(listUseCase.Page), args.Error(1)
+}
+
+func TestListHandler_ListPlants(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	page := listUseCase.Page{
+		Plants: []domain.Plant{
+			{ID: 2, Author: "author1", ImageData: "data2", CreatedAt: time.Now()},
+			{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
+		},
+		NextCursor: "next",
+	}
+
+	tests := []struct {
+		name               string
+		queryParams        string
+		mockSetup          func(*MockListUseCase)
+		expectedStatus     int
+		expectedCount      int
+		expectedNextCursor string
+		expectedError      bool
+	}{
+		{
+			name:        "successful list with defaults",
+			queryParams: "",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20}, "").Return(page, nil)
+			},
+			expectedStatus:     http.StatusOK,
+			expectedCount:      2,
+			expectedNextCursor: "next",
+		},
+		{
+			name:        "successful list with filters and cursor",
+			queryParams: "?author=author1&createdFrom=2023-01-01T00:00:00Z&createdTo=2023-02-01T00:00:00Z&limit=2&cursor=abc",
+			mockSetup: func(mockUC *MockListUseCase) {
+				filter := domain.ListFilter{Author: "author1", CreatedFrom: from, CreatedTo: to, Limit: 2}
+				mockUC.On("List", mock.Anything, filter, "abc").
+					Return(listUseCase.Page{Plants: page.Plants[:1]}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:        "limit exceeds maximum",
+			queryParams: "?limit=1000",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 100}, "").Return(page, nil)
+			},
+			expectedStatus:     http.StatusOK,
+			expectedCount:      2,
+			expectedNextCursor: "next",
+		},
+		{
+			name:           "invalid limit",
+			queryParams:    "?limit=0",
+			mockSetup:      func(mockUC *MockListUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:        "plants of a user",
+			queryParams: "?userId=7",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20, UserID: 7}, "").Return(page, nil)
+			},
+			expectedStatus:     http.StatusOK,
+			expectedCount:      2,
+			expectedNextCursor: "next",
+		},
+		{
+			name:           "invalid userId",
+			queryParams:    "?userId=alice",
+			mockSetup:      func(mockUC *MockListUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid createdFrom",
+			queryParams:    "?createdFrom=yesterday",
+			mockSetup:      func(mockUC *MockListUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid createdTo",
+			queryParams:    "?createdTo=2023-13-01",
+			mockSetup:      func(mockUC *MockListUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:        "invalid cursor",
+			queryParams: "?cursor=broken",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20}, "broken").
+					Return(listUseCase.Page{}, listUseCase.ErrInvalidCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:        "use case error",
+			queryParams: "",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20}, "").
+					Return(listUseCase.Page{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockListUseCase{}
+			tt.mockSetup(mockUC)
+
+			handler := NewListHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListPlants(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			if tt.expectedError {
+				assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
+			} else {
+				assert.Equal(t, float64(tt.expectedCount), response["count"])
+				if tt.expectedNextCursor != "" {
+					assert.Equal(t, tt.expectedNextCursor, response["nextCursor"])
+				} else {
+					assert.NotContains(t, response, "nextCursor")
+				}
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListHandler_StatusFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        func(ListUseCase) *ListHandler
+		queryParams    string
+		mockSetup      func(*MockListUseCase)
+		expectedStatus int
+	}{
+		{
+			name:        "public list ignores status",
+			handler:     NewListHandler,
+			queryParams: "?status=hidden",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20}, "").Return(listUseCase.Page{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "moderation list filters by status",
+			handler:     NewModerationListHandler,
+			queryParams: "?status=hidden",
+			mockSetup: func(mockUC *MockListUseCase) {
+				mockUC.On("List", mock.Anything, domain.ListFilter{Limit: 20, Status: domain.StatusHidden}, "").
+					Return(listUseCase.Page{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "moderation list rejects unknown status",
+			handler:        NewModerationListHandler,
+			queryParams:    "?status=gone",
+			mockSetup:      func(mockUC *MockListUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockListUseCase{}
+			tt.mockSetup(mockUC)
+			handler := tt.handler(mockUC)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ListPlants(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewListHandler(t *testing.T) {
+	mockUC := &MockListUseCase{}
+
+	handler := NewListHandler(mockUC)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
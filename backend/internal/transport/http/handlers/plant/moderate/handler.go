@@ -0,0 +1,130 @@
+package moderate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Validator - интерфейс для валидации.
+type Validator interface {
+	ValidateStruct(s interface{}) map[string]string
+}
+
+// ModerateUseCase - интерфейс для use case модерации растений.
+type ModerateUseCase interface {
+	Moderate(ctx context.Context, id int, action domain.ModerationAction, actor, reason string) (domain.Plant, error)
+	Log(ctx context.Context, id int) ([]domain.ModerationEvent, error)
+}
+
+// ModerateHandler - HTTP обработчик административных действий над растениями.
+// Ожидается, что маршруты защищены middleware.APIKeyAuth: из него берется имя модератора.
+type ModerateHandler struct {
+	uc        ModerateUseCase
+	validator Validator
+}
+
+// NewModerateHandler - конструктор для хендлера.
+func NewModerateHandler(uc ModerateUseCase, validator Validator) *ModerateHandler {
+	return &ModerateHandler{
+		uc:        uc,
+		validator: validator,
+	}
+}
+
+// HidePlant - обработчик для POST /v1/admin/plants/{id}/hide
+func (h *ModerateHandler) HidePlant(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, domain.ActionHide)
+}
+
+// RestorePlant - обработчик для POST /v1/admin/plants/{id}/restore
+func (h *ModerateHandler) RestorePlant(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, domain.ActionRestore)
+}
+
+// DeletePlant - обработчик для DELETE /v1/admin/plants/{id} (soft-delete)
+func (h *ModerateHandler) DeletePlant(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, domain.ActionDelete)
+}
+
+// PurgePlant - обработчик для POST /v1/admin/plants/{id}/purge
+func (h *ModerateHandler) PurgePlant(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, domain.ActionPurge)
+}
+
+// GetModerationLog - обработчик для GET /v1/admin/plants/{id}/moderation-log
+func (h *ModerateHandler) GetModerationLog(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := h.uc.Log(r.Context(), id)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	responses := make([]dto.ModerationEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = dto.ToModerationEventResponse(e)
+	}
+	cerror.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"events": responses,
+		"count":  len(responses),
+	})
+}
+
+// moderate разбирает запрос и выполняет действие модерации.
+// Тело запроса необязательно и может содержать причину действия.
+func (h *ModerateHandler) moderate(w http.ResponseWriter, r *http.Request, action domain.ModerationAction) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ModerationRequest
+	_, span := tracing.Start(r.Context(), "decode request")
+	err := json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil && !errors.Is(err, io.EOF) {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
+		return
+	}
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
+		return
+	}
+
+	plant, err := h.uc.Moderate(r.Context(), id, action, middleware.ActorFromContext(r.Context()), req.Reason)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusOK, dto.ToPlantResponseWithoutImage(plant))
+}
+
+// parseID читает id растения из пути. При ошибке ответ уже отправлен.
+func parseID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,233 @@
+package moderate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	moderateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/moderate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockModerateUseCase - мок для ModerateUseCase
+type MockModerateUseCase struct {
+	mock.Mock
+}
+
+func (m *MockModerateUseCase) Moderate(ctx context.Context, id int, action domain.ModerationAction, actor, reason string) (domain.Plant, error) {
+	args := m.Called(ctx, id, action, actor, reason)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func (m *MockModerateUseCase) Log(ctx context.Context, id int) ([]domain.ModerationEvent, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.ModerationEvent), args.Error(1)
+}
+
+// moderatorKey принимает только токен "secret".
+type moderatorKey struct{}
+
+func (moderatorKey) Authenticate(ctx context.Context, token string) (apikey.APIKey, error) {
+	if token != "secret" {
+		return apikey.APIKey{}, apikey.ErrNotFound
+	}
+	return apikey.APIKey{ID: 1, Name: "moderator", Scopes: []apikey.Scope{apikey.ScopePlantsModerate}}, nil
+}
+
+// serve выполняет запрос через middleware.APIKeyAuth, как это делает роутер.
+func serve(handler http.HandlerFunc, method, id string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/v1/admin/plants/"+id, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	middleware.APIKeyAuth(moderatorKey{})(handler).ServeHTTP(w, req)
+	return w
+}
+
+func TestModerateHandler_Actions(t *testing.T) {
+	hidden := domain.Plant{ID: 1, Author: "author", Status: domain.StatusHidden, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		handler        func(*ModerateHandler) http.HandlerFunc
+		method         string
+		id             string
+		body           string
+		mockSetup      func(*MockModerateUseCase, *testutil.MockValidator)
+		expectedStatus int
+	}{
+		{
+			name:    "hide with reason",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			body:    `{"reason":"spam"}`,
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 1, domain.ActionHide, "moderator", "spam").Return(hidden, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "restore without body",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.RestorePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 1, domain.ActionRestore, "moderator", "").Return(hidden, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "delete missing plant",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.DeletePlant },
+			method:  http.MethodDelete,
+			id:      "2",
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 2, domain.ActionDelete, "moderator", "").
+					Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:    "invalid transition",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 1, domain.ActionHide, "moderator", "").
+					Return(domain.Plant{}, domain.ErrInvalidTransition)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:    "actor required",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.PurgePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 1, domain.ActionPurge, "moderator", "").
+					Return(domain.Plant{}, moderateUseCase.ErrActorRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "reason too long",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			body:    `{"reason":"..."}`,
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(map[string]string{"Reason": "too long"})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid json",
+			handler:        func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:         http.MethodPost,
+			id:             "1",
+			body:           `{"reason":`,
+			mockSetup:      func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			handler:        func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:         http.MethodPost,
+			id:             "abc",
+			mockSetup:      func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "use case error",
+			handler: func(h *ModerateHandler) http.HandlerFunc { return h.HidePlant },
+			method:  http.MethodPost,
+			id:      "1",
+			mockSetup: func(mockUC *MockModerateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Moderate", mock.Anything, 1, domain.ActionHide, "moderator", "").
+					Return(domain.Plant{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockModerateUseCase{}
+			mockValidator := testutil.NewMockValidator()
+			tt.mockSetup(mockUC, mockValidator)
+			handler := NewModerateHandler(mockUC, mockValidator)
+
+			// Act
+			w := serve(tt.handler(handler), tt.method, tt.id, []byte(tt.body))
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "hidden", response["status"])
+			} else {
+				assert.NotEmpty(t, response)
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerateHandler_GetModerationLog(t *testing.T) {
+	// Arrange
+	mockUC := &MockModerateUseCase{}
+	mockUC.On("Log", mock.Anything, 1).Return([]domain.ModerationEvent{
+		{ID: 1, PlantID: 1, Action: domain.ActionHide, Actor: "moderator", FromStatus: domain.StatusVisible, ToStatus: domain.StatusHidden},
+	}, nil)
+	handler := NewModerateHandler(mockUC, testutil.NewMockValidator())
+
+	// Act
+	w := serve(handler.GetModerationLog, http.MethodGet, "1", nil)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Events []map[string]interface{} `json:"events"`
+		Count  int                      `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, "hide", response.Events[0]["action"])
+	assert.Equal(t, "moderator", response.Events[0]["actor"])
+	mockUC.AssertExpectations(t)
+}
+
+func TestNewModerateHandler(t *testing.T) {
+	mockUC := &MockModerateUseCase{}
+	mockValidator := testutil.NewMockValidator()
+
+	handler := NewModerateHandler(mockUC, mockValidator)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+	assert.Equal(t, mockValidator, handler.validator)
+}
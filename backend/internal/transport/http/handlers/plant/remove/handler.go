@@ -0,0 +1,51 @@
+package remove
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// RemoveUseCase - интерфейс для use case удаления растения автором.
+type RemoveUseCase interface {
+	Remove(ctx context.Context, id int, token string) error
+}
+
+// RemoveHandler - HTTP обработчик для удаления растения автором.
+type RemoveHandler struct {
+	uc RemoveUseCase
+}
+
+// NewRemoveHandler - конструктор для хендлера.
+func NewRemoveHandler(uc RemoveUseCase) *RemoveHandler {
+	return &RemoveHandler{
+		uc: uc,
+	}
+}
+
+// DeletePlant - обработчик для DELETE /v1/plants/{id}
+func (h *RemoveHandler) DeletePlant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return
+	}
+
+	token := r.Header.Get(dto.OwnerTokenHeader)
+	if token == "" {
+		cerror.Respond(w, r, cerror.New(cerror.Unauthorized, dto.OwnerTokenHeader+" header is required"))
+		return
+	}
+
+	if err := h.uc.Remove(r.Context(), id, token); err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
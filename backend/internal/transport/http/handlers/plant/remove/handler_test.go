@@ -0,0 +1,120 @@
+package remove
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRemoveUseCase - мок для RemoveUseCase
+type MockRemoveUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRemoveUseCase) Remove(ctx context.Context, id int, token string) error {
+	args := m.Called(ctx, id, token)
+	return args.Error(0)
+}
+
+func TestRemoveHandler_DeletePlant(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		token          string
+		mockSetup      func(*MockRemoveUseCase)
+		expectedStatus int
+	}{
+		{
+			name:  "deleted by owner",
+			id:    "1",
+			token: "secret",
+			mockSetup: func(mockUC *MockRemoveUseCase) {
+				mockUC.On("Remove", mock.Anything, 1, "secret").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:  "wrong token",
+			id:    "1",
+			token: "guess",
+			mockSetup: func(mockUC *MockRemoveUseCase) {
+				mockUC.On("Remove", mock.Anything, 1, "guess").Return(domain.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "plant not found",
+			id:    "2",
+			token: "secret",
+			mockSetup: func(mockUC *MockRemoveUseCase) {
+				mockUC.On("Remove", mock.Anything, 2, "secret").Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing token",
+			id:             "1",
+			mockSetup:      func(mockUC *MockRemoveUseCase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid id",
+			id:             "-1",
+			token:          "secret",
+			mockSetup:      func(mockUC *MockRemoveUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "use case error",
+			id:    "1",
+			token: "secret",
+			mockSetup: func(mockUC *MockRemoveUseCase) {
+				mockUC.On("Remove", mock.Anything, 1, "secret").Return(assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockRemoveUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewRemoveHandler(mockUC)
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/plants/"+tt.id, nil)
+			if tt.token != "" {
+				req.Header.Set("X-Owner-Token", tt.token)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.DeletePlant(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Empty(t, w.Body.Bytes())
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewRemoveHandler(t *testing.T) {
+	mockUC := &MockRemoveUseCase{}
+
+	handler := NewRemoveHandler(mockUC)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+}
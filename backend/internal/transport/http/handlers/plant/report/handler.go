@@ -0,0 +1,140 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+const defaultQueueLimit = 50
+const maxQueueLimit = 200
+
+// Validator - интерфейс для валидации.
+type Validator interface {
+	ValidateStruct(s interface{}) map[string]string
+}
+
+// ReportUseCase - интерфейс для use case жалоб на растения.
+type ReportUseCase interface {
+	Report(ctx context.Context, id int, reason domain.ReportReason, reporter string) error
+	Queue(ctx context.Context, limit int) ([]domain.ReportSummary, error)
+	Resolve(ctx context.Context, id int) (int, error)
+}
+
+// ReportHandler - HTTP обработчик жалоб на растения и очереди жалоб для модераторов.
+type ReportHandler struct {
+	uc        ReportUseCase
+	validator Validator
+}
+
+// NewReportHandler - конструктор для хендлера.
+func NewReportHandler(uc ReportUseCase, validator Validator) *ReportHandler {
+	return &ReportHandler{
+		uc:        uc,
+		validator: validator,
+	}
+}
+
+// CreateReport - обработчик для POST /v1/plants/{id}/reports
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateReportRequest
+	_, span := tracing.Start(r.Context(), "decode request")
+	err := json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
+		return
+	}
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
+		return
+	}
+
+	err = h.uc.Report(r.Context(), id, domain.ReportReason(req.Reason), reporterID(r))
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+// GetQueue - обработчик для GET /v1/admin/reports
+func (h *ReportHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	limit := defaultQueueLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid limit parameter. Must be a positive integer"))
+			return
+		}
+		limit = min(parsed, maxQueueLimit)
+	}
+
+	queue, err := h.uc.Queue(r.Context(), limit)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	responses := make([]dto.ReportSummaryResponse, len(queue))
+	for i, s := range queue {
+		responses[i] = dto.ToReportSummaryResponse(s)
+	}
+	cerror.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"plants": responses,
+		"count":  len(responses),
+	})
+}
+
+// ResolveReports - обработчик для POST /v1/admin/plants/{id}/reports/resolve
+func (h *ReportHandler) ResolveReports(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	resolved, err := h.uc.Resolve(r.Context(), id)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+	cerror.WriteJSON(w, http.StatusOK, map[string]int{"resolved": resolved})
+}
+
+// reporterID определяет, от кого пришла жалоба. RemoteAddr уже заменен middleware.RealIP,
+// поэтому за прокси это адрес клиента, а не прокси.
+func reporterID(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// parseID читает id растения из пути. При ошибке ответ уже отправлен.
+func parseID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return 0, false
+	}
+	return id, true
+}
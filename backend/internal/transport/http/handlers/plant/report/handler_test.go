@@ -0,0 +1,250 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReportUseCase - мок для ReportUseCase
+type MockReportUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReportUseCase) Report(ctx context.Context, id int, reason domain.ReportReason, reporter string) error {
+	args := m.Called(ctx, id, reason, reporter)
+	return args.Error(0)
+}
+
+func (m *MockReportUseCase) Queue(ctx context.Context, limit int) ([]domain.ReportSummary, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.ReportSummary), args.Error(1)
+}
+
+func (m *MockReportUseCase) Resolve(ctx context.Context, id int) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func withID(req *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestReportHandler_CreateReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		mockSetup      func(*MockReportUseCase, *testutil.MockValidator)
+		expectedStatus int
+	}{
+		{
+			name: "accepted",
+			id:   "1",
+			body: `{"reason":"spam"}`,
+			mockSetup: func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Report", mock.Anything, 1, domain.ReasonSpam, "ip:203.0.113.7").Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "already reported",
+			id:   "1",
+			body: `{"reason":"spam"}`,
+			mockSetup: func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Report", mock.Anything, 1, domain.ReasonSpam, mock.Anything).Return(domain.ErrAlreadyReported)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "plant not found",
+			id:   "2",
+			body: `{"reason":"spam"}`,
+			mockSetup: func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Report", mock.Anything, 2, domain.ReasonSpam, mock.Anything).Return(domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invalid reason",
+			id:   "1",
+			body: `{"reason":"boring"}`,
+			mockSetup: func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(map[string]string{"reason": "field 'reason' is not valid"})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid json",
+			id:             "1",
+			body:           `{"reason":`,
+			mockSetup:      func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			id:             "0",
+			body:           `{"reason":"spam"}`,
+			mockSetup:      func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "use case error",
+			id:   "1",
+			body: `{"reason":"spam"}`,
+			mockSetup: func(mockUC *MockReportUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Report", mock.Anything, 1, domain.ReasonSpam, mock.Anything).Return(assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockReportUseCase{}
+			mockValidator := testutil.NewMockValidator()
+			tt.mockSetup(mockUC, mockValidator)
+			handler := NewReportHandler(mockUC, mockValidator)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/plants/"+tt.id+"/reports", bytes.NewBufferString(tt.body))
+			req.RemoteAddr = "203.0.113.7:51234"
+			req = withID(req, tt.id)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.CreateReport(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportHandler_GetQueue(t *testing.T) {
+	queue := []domain.ReportSummary{{
+		PlantID:     7,
+		PlantStatus: domain.StatusHidden,
+		Count:       3,
+		Reasons:     map[domain.ReportReason]int{domain.ReasonSpam: 2, domain.ReasonOther: 1},
+		FirstAt:     time.Now().Add(-time.Hour),
+		LastAt:      time.Now(),
+	}}
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*MockReportUseCase)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "default limit",
+			mockSetup: func(mockUC *MockReportUseCase) {
+				mockUC.On("Queue", mock.Anything, 50).Return(queue, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:        "limit capped",
+			queryParams: "?limit=1000",
+			mockSetup: func(mockUC *MockReportUseCase) {
+				mockUC.On("Queue", mock.Anything, 200).Return([]domain.ReportSummary{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid limit",
+			queryParams:    "?limit=-1",
+			mockSetup:      func(mockUC *MockReportUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "use case error",
+			mockSetup: func(mockUC *MockReportUseCase) {
+				mockUC.On("Queue", mock.Anything, 50).Return([]domain.ReportSummary{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockReportUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewReportHandler(mockUC, testutil.NewMockValidator())
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/reports"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.GetQueue(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Plants []map[string]interface{} `json:"plants"`
+					Count  int                      `json:"count"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCount, response.Count)
+				if tt.expectedCount > 0 {
+					assert.Equal(t, "hidden", response.Plants[0]["status"])
+					assert.Equal(t, map[string]interface{}{"spam": float64(2), "other": float64(1)}, response.Plants[0]["reasons"])
+				}
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportHandler_ResolveReports(t *testing.T) {
+	// Arrange
+	mockUC := &MockReportUseCase{}
+	mockUC.On("Resolve", mock.Anything, 7).Return(3, nil)
+	handler := NewReportHandler(mockUC, testutil.NewMockValidator())
+
+	req := withID(httptest.NewRequest(http.MethodPost, "/v1/admin/plants/7/reports/resolve", nil), "7")
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.ResolveReports(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"resolved":3}`, w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestNewReportHandler(t *testing.T) {
+	mockUC := &MockReportUseCase{}
+	mockValidator := testutil.NewMockValidator()
+
+	handler := NewReportHandler(mockUC, mockValidator)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+	assert.Equal(t, mockValidator, handler.validator)
+}
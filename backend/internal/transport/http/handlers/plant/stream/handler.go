@@ -0,0 +1,215 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// DefaultHeartbeat - как часто в пустой поток отправляется служебное сообщение,
+// чтобы прокси и балансировщики не закрывали неактивное соединение.
+const DefaultHeartbeat = 25 * time.Second
+
+// sseRetry - через сколько миллисекунд EventSource переподключается после разрыва.
+const sseRetry = 3000
+
+// defaultWriteTimeout - сколько ждать отправки одного сообщения. Клиент, который перестал читать,
+// иначе навсегда занял бы горутину и соединение.
+const defaultWriteTimeout = 10 * time.Second
+
+// StreamUseCase - интерфейс для use case подписки на новые растения.
+type StreamUseCase interface {
+	Subscribe(ctx context.Context) (*events.Subscription, error)
+}
+
+// StreamHandler - HTTP обработчик потока новых растений.
+type StreamHandler struct {
+	uc             StreamUseCase
+	heartbeat      time.Duration
+	writeTimeout   time.Duration
+	originPatterns []string
+}
+
+// NewStreamHandler - конструктор для хендлера.
+// originPatterns - хосты, с которых разрешено открывать WebSocket (см. websocket.AcceptOptions).
+func NewStreamHandler(uc StreamUseCase, heartbeat time.Duration, originPatterns []string) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+	return &StreamHandler{
+		uc:             uc,
+		heartbeat:      heartbeat,
+		writeTimeout:   defaultWriteTimeout,
+		originPatterns: originPatterns,
+	}
+}
+
+// StreamPlants - обработчик для GET /v1/plants/stream
+// Обычный запрос получает поток Server-Sent Events, запрос с Upgrade: websocket - WebSocket.
+// В обоих случаях каждое новое растение отправляется как PlantResponse.
+func (h *StreamHandler) StreamPlants(w http.ResponseWriter, r *http.Request) {
+	// inline=false убирает imageData и grid из событий: клиент загрузит изображения по imageUrl.
+	inline := true
+	if inlineStr := r.URL.Query().Get("inline"); inlineStr != "" {
+		var err error
+		inline, err = strconv.ParseBool(inlineStr)
+		if err != nil {
+			cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid inline parameter. Must be true or false"))
+			return
+		}
+	}
+
+	sub, err := h.uc.Subscribe(r.Context())
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Unavailable, "Plant stream is not available"))
+		return
+	}
+	defer sub.Close()
+
+	encode := func(plant domain.Plant) ([]byte, error) {
+		if inline {
+			return json.Marshal(dto.ToPlantResponse(plant))
+		}
+		return json.Marshal(dto.ToPlantResponseWithoutImage(plant))
+	}
+
+	if isWebSocket(r) {
+		h.streamWebSocket(w, r, sub, encode)
+		return
+	}
+	h.streamSSE(w, r, sub, encode)
+}
+
+// streamSSE отправляет растения в формате text/event-stream.
+// Если клиент не успевал читать события, перед закрытием отправляется событие lagged:
+// клиент может дочитать пропущенное через GET /v1/plants.
+// Каждая запись ограничена writeTimeout: если клиент не читает поток, запись завершается ошибкой
+// и подписка закрывается.
+func (h *StreamHandler) streamSSE(w http.ResponseWriter, r *http.Request, sub *events.Subscription, encode func(domain.Plant) ([]byte, error)) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Отключает буферизацию в nginx
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+		return
+	}
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case plant, ok := <-sub.C:
+			if !ok {
+				if sub.Lagged() {
+					rc.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+					fmt.Fprint(w, "event: lagged\ndata: {}\n\n")
+					rc.Flush()
+				}
+				return
+			}
+			var data []byte
+			if data, err = encode(plant); err != nil {
+				return
+			}
+			if err = rc.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+				return
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: plant\ndata: %s\n\n", plant.ID, data)
+		case <-ticker.C:
+			if err = rc.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+				return
+			}
+			_, err = fmt.Fprint(w, ": ping\n\n")
+		}
+
+		if err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// streamWebSocket отправляет растения текстовыми сообщениями WebSocket.
+// Сообщения от клиента не ожидаются. Медленный клиент отключается с кодом 1013 (try again later),
+// при остановке сервера соединение закрывается с кодом 1001 (going away).
+func (h *StreamHandler) streamWebSocket(w http.ResponseWriter, r *http.Request, sub *events.Subscription, encode func(domain.Plant) ([]byte, error)) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.originPatterns})
+	if err != nil {
+		// Accept уже отправил клиенту ответ с ошибкой.
+		return
+	}
+	defer conn.CloseNow()
+
+	// CloseRead читает и отбрасывает входящие сообщения; контекст отменяется, когда клиент уходит.
+	ctx := conn.CloseRead(r.Context())
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case plant, ok := <-sub.C:
+			if !ok {
+				if sub.Lagged() {
+					conn.Close(websocket.StatusTryAgainLater, "client is too slow")
+				} else {
+					conn.Close(websocket.StatusGoingAway, "server is shutting down")
+				}
+				return
+			}
+			data, err := encode(plant)
+			if err != nil {
+				return
+			}
+			if err := writeWithTimeout(ctx, h.writeTimeout, func(ctx context.Context) error {
+				return conn.Write(ctx, websocket.MessageText, data)
+			}); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := writeWithTimeout(ctx, h.writeTimeout, conn.Ping); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeWithTimeout ограничивает время одной операции записи.
+func writeWithTimeout(ctx context.Context, timeout time.Duration, write func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return write(ctx)
+}
+
+// isWebSocket проверяет, просит ли клиент перейти на WebSocket.
+func isWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
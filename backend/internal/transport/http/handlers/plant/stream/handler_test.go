@@ -0,0 +1,229 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStreamUseCase - мок для StreamUseCase
+type MockStreamUseCase struct {
+	mock.Mock
+}
+
+func (m *MockStreamUseCase) Subscribe(ctx context.Context) (*events.Subscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*events.Subscription), args.Error(1)
+}
+
+// newStreamServer поднимает сервер с хендлером, подписки которого берутся из bus.
+func newStreamServer(t *testing.T, bus *events.PlantBus) *httptest.Server {
+	t.Helper()
+
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+	mockUC := &MockStreamUseCase{}
+	mockUC.On("Subscribe", mock.Anything).Return(sub, nil).Once()
+
+	server := httptest.NewServer(http.HandlerFunc(NewStreamHandler(mockUC, time.Minute, nil).StreamPlants))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testPlant(id int) domain.Plant {
+	return domain.Plant{ID: id, Author: "author", ImageData: "data", CreatedAt: time.Now()}
+}
+
+func TestStreamHandler_SSE(t *testing.T) {
+	// Arrange
+	bus := events.NewPlantBus(4)
+	server := newStreamServer(t, bus)
+
+	resp, err := http.Get(server.URL + "?inline=false")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Act
+	bus.Publish(testPlant(42))
+
+	// Assert
+	reader := bufio.NewReader(resp.Body)
+	var event, id, data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+
+	assert.Equal(t, "plant", event)
+	assert.Equal(t, "42", id)
+	var plant map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(data), &plant))
+	assert.Equal(t, float64(42), plant["id"])
+	assert.Equal(t, "/v1/plants/42/image.png", plant["imageUrl"])
+	assert.NotContains(t, plant, "imageData")
+
+	// Остановка шины завершает поток
+	bus.Close()
+	_, err = io.ReadAll(reader)
+	assert.NoError(t, err)
+}
+
+func TestStreamHandler_SSEClientNotReading(t *testing.T) {
+	// Arrange: клиент открывает поток и не читает его
+	bus := events.NewPlantBus(4)
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+	mockUC := &MockStreamUseCase{}
+	mockUC.On("Subscribe", mock.Anything).Return(sub, nil).Once()
+
+	handler := NewStreamHandler(mockUC, time.Minute, nil)
+	handler.writeTimeout = 100 * time.Millisecond
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		handler.StreamPlants(w, r)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\n\r\n", server.Listener.Addr())
+	require.NoError(t, err)
+
+	// Act: публикуем крупные растения, пока буферы сокета не заполнятся
+	plant := testPlant(1)
+	plant.ImageData = strings.Repeat("a", 1<<20)
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case <-done:
+			// Assert: запись не повисла, обработчик завершился
+			return
+		case <-timeout:
+			t.Fatal("handler is blocked on a client that does not read")
+		case <-time.After(10 * time.Millisecond):
+			bus.Publish(plant)
+		}
+	}
+}
+
+func TestStreamHandler_WebSocket(t *testing.T) {
+	// Arrange
+	bus := events.NewPlantBus(4)
+	server := newStreamServer(t, bus)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	// Act
+	bus.Publish(testPlant(7))
+
+	// Assert
+	msgType, data, err := conn.Read(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, websocket.MessageText, msgType)
+
+	var plant map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &plant))
+	assert.Equal(t, float64(7), plant["id"])
+	assert.Equal(t, "data", plant["imageData"])
+
+	// Остановка шины закрывает соединение с кодом going away
+	bus.Close()
+	_, _, err = conn.Read(ctx)
+	assert.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(err))
+}
+
+func TestStreamHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*MockStreamUseCase)
+		expectedStatus int
+	}{
+		{
+			name:           "invalid inline parameter",
+			queryParams:    "?inline=maybe",
+			mockSetup:      func(mockUC *MockStreamUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "stream stopped",
+			queryParams: "",
+			mockSetup: func(mockUC *MockStreamUseCase) {
+				mockUC.On("Subscribe", mock.Anything).Return(nil, events.ErrBusClosed)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockStreamUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewStreamHandler(mockUC, 0, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/plants/stream"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.StreamPlants(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, float64(tt.expectedStatus), response["status"])
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewStreamHandler(t *testing.T) {
+	mockUC := &MockStreamUseCase{}
+
+	handler := NewStreamHandler(mockUC, 0, []string{"localhost:3000"})
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+	assert.Equal(t, DefaultHeartbeat, handler.heartbeat)
+}
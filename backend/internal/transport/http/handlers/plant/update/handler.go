@@ -0,0 +1,89 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Validator - интерфейс для валидации.
+type Validator interface {
+	ValidateStruct(s interface{}) map[string]string
+}
+
+// UpdateUseCase - интерфейс для use case изменения растения автором.
+type UpdateUseCase interface {
+	Update(ctx context.Context, id int, token string, req updateUseCase.Request) (domain.Plant, error)
+}
+
+// UpdateHandler - HTTP обработчик для изменения растения автором.
+type UpdateHandler struct {
+	uc        UpdateUseCase
+	validator Validator
+}
+
+// NewUpdateHandler - конструктор для хендлера.
+func NewUpdateHandler(uc UpdateUseCase, validator Validator) *UpdateHandler {
+	return &UpdateHandler{
+		uc:        uc,
+		validator: validator,
+	}
+}
+
+// UpdatePlant - обработчик для PATCH /v1/plants/{id}
+func (h *UpdateHandler) UpdatePlant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid id parameter. Must be a positive integer"))
+		return
+	}
+
+	token := r.Header.Get(dto.OwnerTokenHeader)
+	if token == "" {
+		cerror.Respond(w, r, cerror.New(cerror.Unauthorized, dto.OwnerTokenHeader+" header is required"))
+		return
+	}
+
+	var req dto.UpdatePlantRequest
+	_, span := tracing.Start(r.Context(), "decode request")
+	err = json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
+		return
+	}
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
+		return
+	}
+
+	update := updateUseCase.Request{Author: req.Author, ImageData: req.ImageData}
+	if req.Grid != nil {
+		grid, err := req.Grid.ToDomain()
+		if err != nil {
+			cerror.Respond(w, r, err)
+			return
+		}
+		update.Grid = &grid
+	}
+
+	plant, err := h.uc.Update(r.Context(), id, token, update)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusOK, dto.ToPlantResponse(plant))
+}
@@ -0,0 +1,212 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUpdateUseCase - мок для UpdateUseCase
+type MockUpdateUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUpdateUseCase) Update(ctx context.Context, id int, token string, req updateUseCase.Request) (domain.Plant, error) {
+	args := m.Called(ctx, id, token, req)
+	return args.Get(0).(domain.Plant), args.Error(1)
+}
+
+func TestUpdateHandler_UpdatePlant(t *testing.T) {
+	updated := domain.Plant{ID: 1, Author: "new", ImageData: "data", ImageVersion: 2, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		id             string
+		token          string
+		body           string
+		mockSetup      func(*MockUpdateUseCase, *testutil.MockValidator)
+		expectedStatus int
+	}{
+		{
+			name:  "rename",
+			id:    "1",
+			token: "secret",
+			body:  `{"author":"new"}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "secret", mock.MatchedBy(func(req updateUseCase.Request) bool {
+					return req.Author != nil && *req.Author == "new" && req.Grid == nil && req.ImageData == ""
+				})).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "replace grid",
+			id:    "1",
+			token: "secret",
+			body:  `{"grid":{"width":1,"height":1,"palette":["#ff0000"],"pixels":[0]}}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "secret", mock.MatchedBy(func(req updateUseCase.Request) bool {
+					return req.Author == nil && req.Grid != nil && req.Grid.Width == 1
+				})).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "grid refers to missing color",
+			id:    "1",
+			token: "secret",
+			body:  `{"grid":{"width":1,"height":1,"palette":["#ff0000"],"pixels":[3]}}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:  "wrong token",
+			id:    "1",
+			token: "guess",
+			body:  `{"author":"new"}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "guess", mock.Anything).Return(domain.Plant{}, domain.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "author blocked",
+			id:    "1",
+			token: "secret",
+			body:  `{"author":"admin"}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "secret", mock.Anything).Return(domain.Plant{}, domain.ErrAuthorBlocked)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "plant not found",
+			id:    "2",
+			token: "secret",
+			body:  `{"author":"new"}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 2, "secret", mock.Anything).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "empty update",
+			id:    "1",
+			token: "secret",
+			body:  `{}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "secret", updateUseCase.Request{}).Return(domain.Plant{}, domain.ErrEmptyUpdate)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "validation error",
+			id:    "1",
+			token: "secret",
+			body:  `{"author":""}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(map[string]string{"author": "field 'author' is not valid"})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing token",
+			id:             "1",
+			body:           `{"author":"new"}`,
+			mockSetup:      func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid json",
+			id:             "1",
+			token:          "secret",
+			body:           `{"author":`,
+			mockSetup:      func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			id:             "abc",
+			token:          "secret",
+			body:           `{"author":"new"}`,
+			mockSetup:      func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "use case error",
+			id:    "1",
+			token: "secret",
+			body:  `{"author":"new"}`,
+			mockSetup: func(mockUC *MockUpdateUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Update", mock.Anything, 1, "secret", mock.Anything).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockUpdateUseCase{}
+			mockValidator := testutil.NewMockValidator()
+			tt.mockSetup(mockUC, mockValidator)
+			handler := NewUpdateHandler(mockUC, mockValidator)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/plants/"+tt.id, bytes.NewBufferString(tt.body))
+			if tt.token != "" {
+				req.Header.Set("X-Owner-Token", tt.token)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.UpdatePlant(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "new", response["author"])
+				assert.Equal(t, "/v1/plants/1/image.png?v=2", response["imageUrl"])
+				assert.NotContains(t, response, "ownerToken")
+			}
+
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewUpdateHandler(t *testing.T) {
+	mockUC := &MockUpdateUseCase{}
+	mockValidator := testutil.NewMockValidator()
+
+	handler := NewUpdateHandler(mockUC, mockValidator)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+	assert.Equal(t, mockValidator, handler.validator)
+}
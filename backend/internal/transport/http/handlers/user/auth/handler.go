@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// Validator - интерфейс для валидации.
+type Validator interface {
+	ValidateStruct(s interface{}) map[string]string
+}
+
+// AuthUseCase - интерфейс для use case регистрации и сессий.
+type AuthUseCase interface {
+	Register(ctx context.Context, username, password string) (userDomain.User, error)
+	Login(ctx context.Context, username, password string) (authUseCase.Login, error)
+	Logout(ctx context.Context, token string) error
+}
+
+// AuthHandler - HTTP обработчик регистрации, входа и выхода.
+type AuthHandler struct {
+	uc        AuthUseCase
+	validator Validator
+	// secureCookie добавляет cookie сессии атрибут Secure. Выключается только для локальной разработки по http.
+	secureCookie bool
+}
+
+// NewAuthHandler - конструктор для хендлера.
+func NewAuthHandler(uc AuthUseCase, validator Validator, secureCookie bool) *AuthHandler {
+	return &AuthHandler{
+		uc:           uc,
+		validator:    validator,
+		secureCookie: secureCookie,
+	}
+}
+
+// Register - обработчик для POST /v1/auth/register
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req dto.RegisterRequest
+	_, span := tracing.Start(r.Context(), "decode request")
+	err := json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
+		return
+	}
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
+		return
+	}
+
+	u, err := h.uc.Register(r.Context(), req.Username, req.Password)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusCreated, dto.ToUserResponse(u))
+}
+
+// Login - обработчик для POST /v1/auth/login.
+// Токен сессии уходит только в HttpOnly cookie, а CSRF-токен - в теле ответа.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	_, span := tracing.Start(r.Context(), "decode request")
+	err := json.NewDecoder(r.Body).Decode(&req)
+	span.End()
+	if err != nil {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid JSON format"))
+		return
+	}
+	_, span = tracing.Start(r.Context(), "validate request")
+	validationErrors := h.validator.ValidateStruct(req)
+	span.End()
+	if validationErrors != nil {
+		cerror.Respond(w, r, cerror.Validation(validationErrors))
+		return
+	}
+
+	login, err := h.uc.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	// Cookie прежней сессии сейчас будет перезаписана, поэтому саму сессию закрываем.
+	// Если это не удалось, она просто доживет до своего срока.
+	if _, ok := appMiddleware.SessionFromContext(r.Context()); ok {
+		_ = h.uc.Logout(r.Context(), sessionToken(r))
+	}
+
+	setSessionCookie(w, login, h.secureCookie)
+	cerror.WriteJSON(w, http.StatusOK, dto.ToSessionResponse(login.Session, login.User))
+}
+
+// Logout - обработчик для POST /v1/auth/logout. Выход без сессии тоже успешен.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if _, ok := appMiddleware.SessionFromContext(r.Context()); ok {
+		if err := h.uc.Logout(r.Context(), sessionToken(r)); err != nil {
+			cerror.Respond(w, r, &cerror.Error{Detail: "Failed to log out", Err: err})
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     appMiddleware.SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSession - обработчик для GET /v1/auth/session: текущий пользователь и CSRF-токен,
+// например чтобы страница восстановила их после перезагрузки.
+func (h *AuthHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	session, ok := appMiddleware.SessionFromContext(r.Context())
+	u, _ := userDomain.FromContext(r.Context())
+	if !ok {
+		cerror.Respond(w, r, cerror.New(cerror.Unauthorized, "Not logged in"))
+		return
+	}
+
+	cerror.WriteJSON(w, http.StatusOK, dto.ToSessionResponse(session, u))
+}
+
+// setSessionCookie выдает браузеру cookie новой сессии.
+func setSessionCookie(w http.ResponseWriter, login authUseCase.Login, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     appMiddleware.SessionCookie,
+		Value:    login.Token,
+		Path:     "/",
+		Expires:  login.Session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sessionToken возвращает токен сессии из cookie запроса.
+func sessionToken(r *http.Request) string {
+	cookie, err := r.Cookie(appMiddleware.SessionCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
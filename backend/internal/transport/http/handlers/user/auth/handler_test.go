@@ -0,0 +1,306 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuthUseCase - мок для AuthUseCase
+type MockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUseCase) Register(ctx context.Context, username, password string) (userDomain.User, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(userDomain.User), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Login(ctx context.Context, username, password string) (authUseCase.Login, error) {
+	args := m.Called(ctx, username, password)
+	return args.Get(0).(authUseCase.Login), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Logout(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// Authenticate позволяет использовать мок в middleware сессий: действует только токен "token".
+func (m *MockAuthUseCase) Authenticate(ctx context.Context, token string) (userDomain.Session, userDomain.User, error) {
+	if token != "token" {
+		return userDomain.Session{}, userDomain.User{}, userDomain.ErrSessionNotFound
+	}
+	return userDomain.Session{UserID: 7, CSRFToken: "csrf"}, userDomain.User{ID: 7, Username: "alice"}, nil
+}
+
+// serve пропускает запрос через middleware сессий, как это делает роутер.
+func serve(h http.HandlerFunc, uc *MockAuthUseCase, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	appMiddleware.Session(uc)(h).ServeHTTP(w, req)
+	return w
+}
+
+func withSession(req *http.Request) *http.Request {
+	req.AddCookie(&http.Cookie{Name: appMiddleware.SessionCookie, Value: "token"})
+	req.Header.Set(appMiddleware.CSRFHeader, "csrf")
+	return req
+}
+
+func TestAuthHandler_Register(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockAuthUseCase, *testutil.MockValidator)
+		expectedStatus int
+	}{
+		{
+			name: "registered",
+			body: `{"username":"alice","password":"correct horse"}`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Register", mock.Anything, "alice", "correct horse").Return(userDomain.User{ID: 1, Username: "alice"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "invalid json",
+			body: `{`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "validation error",
+			body: `{"username":"alice","password":"short"}`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(map[string]string{"password": "field 'password' is too short (min: 8)"})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid username",
+			body: `{"username":"a b c","password":"correct horse"}`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Register", mock.Anything, "a b c", "correct horse").Return(userDomain.User{}, userDomain.ErrInvalidUsername)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "username taken",
+			body: `{"username":"alice","password":"correct horse"}`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Register", mock.Anything, "alice", "correct horse").Return(userDomain.User{}, userDomain.ErrUsernameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "use case error",
+			body: `{"username":"alice","password":"correct horse"}`,
+			mockSetup: func(mockUC *MockAuthUseCase, mockValidator *testutil.MockValidator) {
+				mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+				mockUC.On("Register", mock.Anything, "alice", "correct horse").Return(userDomain.User{}, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := new(MockAuthUseCase)
+			mockValidator := testutil.NewMockValidator()
+			tt.mockSetup(mockUC, mockValidator)
+			handler := NewAuthHandler(mockUC, mockValidator, true)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/auth/register", strings.NewReader(tt.body))
+
+			// Act
+			w := serve(handler.Register, mockUC, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "correct horse")
+			mockUC.AssertExpectations(t)
+			mockValidator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	// Arrange
+	expiresAt := time.Date(2026, 10, 30, 12, 0, 0, 0, time.UTC)
+	login := authUseCase.Login{
+		User:    userDomain.User{ID: 7, Username: "alice", PasswordHash: "$argon2id$secret"},
+		Session: userDomain.Session{UserID: 7, CSRFToken: "new-csrf", ExpiresAt: expiresAt},
+		Token:   "new-token",
+	}
+
+	t.Run("logged in", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockValidator := testutil.NewMockValidator()
+		mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+		mockUC.On("Login", mock.Anything, "alice", "correct horse").Return(login, nil)
+		handler := NewAuthHandler(mockUC, mockValidator, true)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(`{"username":"alice","password":"correct horse"}`))
+
+		// Act
+		w := serve(handler.Login, mockUC, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dto.SessionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "alice", response.User.Username)
+		assert.Equal(t, "new-csrf", response.CSRFToken)
+		assert.NotContains(t, w.Body.String(), "new-token", "session token is only sent in the cookie")
+		assert.NotContains(t, w.Body.String(), "argon2id")
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, appMiddleware.SessionCookie, cookies[0].Name)
+		assert.Equal(t, "new-token", cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+		assert.Equal(t, expiresAt, cookies[0].Expires)
+	})
+
+	t.Run("replaces previous session", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockValidator := testutil.NewMockValidator()
+		mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+		mockUC.On("Login", mock.Anything, "alice", "correct horse").Return(login, nil)
+		mockUC.On("Logout", mock.Anything, "token").Return(nil)
+		handler := NewAuthHandler(mockUC, mockValidator, true)
+
+		req := withSession(httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(`{"username":"alice","password":"correct horse"}`)))
+
+		// Act
+		w := serve(handler.Login, mockUC, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		mockUC := new(MockAuthUseCase)
+		mockValidator := testutil.NewMockValidator()
+		mockValidator.On("ValidateStruct", mock.Anything).Return(nil)
+		mockUC.On("Login", mock.Anything, "alice", "wrong").Return(authUseCase.Login{}, userDomain.ErrInvalidCredentials)
+		handler := NewAuthHandler(mockUC, mockValidator, true)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(`{"username":"alice","password":"wrong"}`))
+
+		// Act
+		w := serve(handler.Login, mockUC, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	})
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		loggedIn       bool
+		mockSetup      func(*MockAuthUseCase)
+		expectedStatus int
+	}{
+		{
+			name:     "logged out",
+			loggedIn: true,
+			mockSetup: func(mockUC *MockAuthUseCase) {
+				mockUC.On("Logout", mock.Anything, "token").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "anonymous",
+			mockSetup:      func(mockUC *MockAuthUseCase) {},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "use case error",
+			loggedIn: true,
+			mockSetup: func(mockUC *MockAuthUseCase) {
+				mockUC.On("Logout", mock.Anything, "token").Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := new(MockAuthUseCase)
+			tt.mockSetup(mockUC)
+			handler := NewAuthHandler(mockUC, testutil.NewMockValidator(), true)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/auth/logout", nil)
+			if tt.loggedIn {
+				req = withSession(req)
+			}
+
+			// Act
+			w := serve(handler.Logout, mockUC, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusNoContent {
+				cookies := w.Result().Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, -1, cookies[0].MaxAge)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_GetSession(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	handler := NewAuthHandler(mockUC, testutil.NewMockValidator(), true)
+
+	// Act
+	loggedIn := serve(handler.GetSession, mockUC, withSession(httptest.NewRequest(http.MethodGet, "/v1/auth/session", nil)))
+	anonymous := serve(handler.GetSession, mockUC, httptest.NewRequest(http.MethodGet, "/v1/auth/session", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, loggedIn.Code)
+	var response dto.SessionResponse
+	require.NoError(t, json.Unmarshal(loggedIn.Body.Bytes(), &response))
+	assert.Equal(t, 7, response.User.ID)
+	assert.Equal(t, "csrf", response.CSRFToken)
+	assert.Equal(t, http.StatusUnauthorized, anonymous.Code)
+}
+
+func TestNewAuthHandler(t *testing.T) {
+	mockUC := new(MockAuthUseCase)
+	mockValidator := testutil.NewMockValidator()
+
+	handler := NewAuthHandler(mockUC, mockValidator, false)
+
+	assert.NotNil(t, handler)
+	assert.Equal(t, mockUC, handler.uc)
+	assert.Equal(t, mockValidator, handler.validator)
+	assert.False(t, handler.secureCookie)
+}
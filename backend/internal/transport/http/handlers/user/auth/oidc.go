@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// OIDCStateCookie - имя cookie, которая привязывает начатый вход через провайдера к браузеру.
+const OIDCStateCookie = "forest_oidc_state"
+
+// oidcCookiePath ограничивает cookie состояния маршрутами входа через провайдеров.
+const oidcCookiePath = "/v1/auth/oidc/"
+
+// OIDCLoginUseCase - интерфейс для use case входа через провайдеров OpenID Connect.
+type OIDCLoginUseCase interface {
+	Providers() []string
+	Begin(ctx context.Context, provider string, linkUserID int) (string, string, error)
+	Complete(ctx context.Context, provider, state, code string) (authUseCase.Login, error)
+}
+
+// SessionCloser закрывает сессию, которую заменяет вход через провайдера.
+type SessionCloser interface {
+	Logout(ctx context.Context, token string) error
+}
+
+// OIDCHandler - HTTP обработчик входа и привязки учетных записей через провайдеров OpenID Connect.
+type OIDCHandler struct {
+	uc       OIDCLoginUseCase
+	sessions SessionCloser
+	// afterLoginURL - куда браузер возвращается после успешного входа.
+	afterLoginURL string
+	secureCookie  bool
+}
+
+// NewOIDCHandler - конструктор для хендлера.
+func NewOIDCHandler(uc OIDCLoginUseCase, sessions SessionCloser, afterLoginURL string, secureCookie bool) *OIDCHandler {
+	if afterLoginURL == "" {
+		afterLoginURL = "/"
+	}
+	return &OIDCHandler{
+		uc:            uc,
+		sessions:      sessions,
+		afterLoginURL: afterLoginURL,
+		secureCookie:  secureCookie,
+	}
+}
+
+// ListProviders - обработчик для GET /v1/auth/oidc: имена провайдеров, через которые можно войти.
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	cerror.WriteJSON(w, http.StatusOK, map[string]interface{}{"providers": h.uc.Providers()})
+}
+
+// StartLogin - обработчик для GET /v1/auth/oidc/{provider}/login: перенаправляет браузер к провайдеру.
+// Это всегда вход, даже при открытой сессии: привязку по GET мог бы запустить чужой сайт простой ссылкой.
+func (h *OIDCHandler) StartLogin(w http.ResponseWriter, r *http.Request) {
+	redirectURL, ok := h.begin(w, r, 0)
+	if !ok {
+		return
+	}
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// StartLink - обработчик для POST /v1/auth/oidc/{provider}/link: привязка учетной записи провайдера
+// к текущему пользователю. Адрес провайдера возвращается в теле, а переходит по нему страница сама.
+func (h *OIDCHandler) StartLink(w http.ResponseWriter, r *http.Request) {
+	u, ok := userDomain.FromContext(r.Context())
+	if !ok {
+		cerror.Respond(w, r, cerror.New(cerror.Unauthorized, "Not logged in"))
+		return
+	}
+
+	redirectURL, ok := h.begin(w, r, u.ID)
+	if !ok {
+		return
+	}
+	cerror.WriteJSON(w, http.StatusOK, map[string]string{"redirectUrl": redirectURL})
+}
+
+// Callback - обработчик для GET /v1/auth/oidc/{provider}/callback, куда провайдер возвращает браузер с кодом.
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		h.clearStateCookie(w)
+		cerror.Respond(w, r, cerror.New(cerror.Unauthorized, "Login was rejected by the provider"))
+		return
+	}
+
+	// state из адреса должен совпасть с cookie: иначе этот callback начинался в другом браузере.
+	cookie, err := r.Cookie(OIDCStateCookie)
+	state := query.Get("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		cerror.Respond(w, r, cerror.New(cerror.Invalid, "Invalid or expired login state"))
+		return
+	}
+	h.clearStateCookie(w)
+
+	login, err := h.uc.Complete(r.Context(), chi.URLParam(r, "provider"), state, query.Get("code"))
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+
+	if _, ok := appMiddleware.SessionFromContext(r.Context()); ok {
+		_ = h.sessions.Logout(r.Context(), sessionToken(r))
+	}
+
+	setSessionCookie(w, login, h.secureCookie)
+	http.Redirect(w, r, h.afterLoginURL, http.StatusSeeOther)
+}
+
+// begin сохраняет состояние входа и выдает его браузеру в cookie.
+// При ошибке ответ уже отправлен и возвращается false.
+func (h *OIDCHandler) begin(w http.ResponseWriter, r *http.Request, linkUserID int) (string, bool) {
+	redirectURL, state, err := h.uc.Begin(r.Context(), chi.URLParam(r, "provider"), linkUserID)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return "", false
+	}
+
+	// SameSite=Lax: браузер вернется от провайдера обычным переходом по ссылке, и cookie должна прийти с ним.
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCStateCookie,
+		Value:    state,
+		Path:     oidcCookiePath,
+		MaxAge:   int(userDomain.LoginStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return redirectURL, true
+}
+
+// clearStateCookie удаляет cookie состояния: state одноразовый.
+func (h *OIDCHandler) clearStateCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCStateCookie,
+		Value:    "",
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	oidcLoginUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/oidc_login"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOIDCLoginUseCase - мок для OIDCLoginUseCase
+type MockOIDCLoginUseCase struct {
+	mock.Mock
+}
+
+func (m *MockOIDCLoginUseCase) Providers() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockOIDCLoginUseCase) Begin(ctx context.Context, provider string, linkUserID int) (string, string, error) {
+	args := m.Called(ctx, provider, linkUserID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockOIDCLoginUseCase) Complete(ctx context.Context, provider, state, code string) (authUseCase.Login, error) {
+	args := m.Called(ctx, provider, state, code)
+	return args.Get(0).(authUseCase.Login), args.Error(1)
+}
+
+// withProvider добавляет в запрос параметр маршрута {provider}.
+func withProvider(req *http.Request, provider string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("provider", provider)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func findCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestNewOIDCHandler(t *testing.T) {
+	// Arrange
+	mockUC := &MockOIDCLoginUseCase{}
+
+	// Act
+	handler := NewOIDCHandler(mockUC, &MockAuthUseCase{}, "", true)
+
+	// Assert
+	assert.NotNil(t, handler)
+	assert.Equal(t, "/", handler.afterLoginURL)
+}
+
+func TestOIDCHandler_ListProviders(t *testing.T) {
+	// Arrange
+	mockUC := &MockOIDCLoginUseCase{}
+	mockUC.On("Providers").Return([]string{"github", "google"})
+	handler := NewOIDCHandler(mockUC, &MockAuthUseCase{}, "/", true)
+	req := httptest.NewRequest(http.MethodGet, "/v1/auth/oidc", nil)
+
+	// Act
+	w := serve(handler.ListProviders, &MockAuthUseCase{}, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"providers":["github","google"]}`, w.Body.String())
+}
+
+func TestOIDCHandler_StartLogin(t *testing.T) {
+	tests := []struct {
+		name           string
+		loggedIn       bool
+		mockSetup      func(*MockOIDCLoginUseCase)
+		expectedStatus int
+	}{
+		{
+			name: "redirects to provider",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase) {
+				mockUC.On("Begin", mock.Anything, "google", 0).Return("https://idp.example.com/authorize", "state-1", nil)
+			},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name:     "logged in user still only logs in",
+			loggedIn: true,
+			mockSetup: func(mockUC *MockOIDCLoginUseCase) {
+				mockUC.On("Begin", mock.Anything, "google", 0).Return("https://idp.example.com/authorize", "state-1", nil)
+			},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name: "unknown provider",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase) {
+				mockUC.On("Begin", mock.Anything, "google", 0).Return("", "", oidcLoginUseCase.ErrUnknownProvider)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockOIDCLoginUseCase{}
+			tt.mockSetup(mockUC)
+			handler := NewOIDCHandler(mockUC, &MockAuthUseCase{}, "/", true)
+			req := withProvider(httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/google/login", nil), "google")
+			if tt.loggedIn {
+				req = withSession(req)
+			}
+
+			// Act
+			w := serve(handler.StartLogin, &MockAuthUseCase{}, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusFound {
+				assert.Equal(t, "https://idp.example.com/authorize", w.Header().Get("Location"))
+				cookie := findCookie(w, OIDCStateCookie)
+				require.NotNil(t, cookie)
+				assert.Equal(t, "state-1", cookie.Value)
+				assert.True(t, cookie.HttpOnly)
+				assert.True(t, cookie.Secure)
+				assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+			}
+			mockUC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCHandler_StartLink(t *testing.T) {
+	t.Run("links to current user", func(t *testing.T) {
+		// Arrange
+		mockUC := &MockOIDCLoginUseCase{}
+		mockUC.On("Begin", mock.Anything, "google", 7).Return("https://idp.example.com/authorize", "state-1", nil)
+		handler := NewOIDCHandler(mockUC, &MockAuthUseCase{}, "/", true)
+		req := withSession(withProvider(httptest.NewRequest(http.MethodPost, "/v1/auth/oidc/google/link", nil), "google"))
+
+		// Act
+		w := serve(handler.StartLink, &MockAuthUseCase{}, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"redirectUrl":"https://idp.example.com/authorize"}`, w.Body.String())
+		assert.NotNil(t, findCookie(w, OIDCStateCookie))
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("requires session", func(t *testing.T) {
+		// Arrange
+		mockUC := &MockOIDCLoginUseCase{}
+		handler := NewOIDCHandler(mockUC, &MockAuthUseCase{}, "/", true)
+		req := withProvider(httptest.NewRequest(http.MethodPost, "/v1/auth/oidc/google/link", nil), "google")
+
+		// Act
+		w := serve(handler.StartLink, &MockAuthUseCase{}, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	login := authUseCase.Login{
+		User:    userDomain.User{ID: 7, Username: "alice"},
+		Session: userDomain.Session{UserID: 7, ExpiresAt: time.Now().Add(time.Hour)},
+		Token:   "new-token",
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		stateCookie    string
+		loggedIn       bool
+		mockSetup      func(*MockOIDCLoginUseCase, *MockAuthUseCase)
+		expectedStatus int
+	}{
+		{
+			name:        "logged in",
+			query:       "?state=state-1&code=code-1",
+			stateCookie: "state-1",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {
+				mockUC.On("Complete", mock.Anything, "google", "state-1", "code-1").Return(login, nil)
+			},
+			expectedStatus: http.StatusSeeOther,
+		},
+		{
+			name:        "previous session is closed",
+			query:       "?state=state-1&code=code-1",
+			stateCookie: "state-1",
+			loggedIn:    true,
+			mockSetup: func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {
+				mockUC.On("Complete", mock.Anything, "google", "state-1", "code-1").Return(login, nil)
+				mockAuth.On("Logout", mock.Anything, "token").Return(nil)
+			},
+			expectedStatus: http.StatusSeeOther,
+		},
+		{
+			name:           "state cookie missing",
+			query:          "?state=state-1&code=code-1",
+			mockSetup:      func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "state does not match cookie",
+			query:          "?state=state-1&code=code-1",
+			stateCookie:    "state-2",
+			mockSetup:      func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "provider returned error",
+			query:          "?state=state-1&error=access_denied",
+			stateCookie:    "state-1",
+			mockSetup:      func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "state expired",
+			query:       "?state=state-1&code=code-1",
+			stateCookie: "state-1",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {
+				mockUC.On("Complete", mock.Anything, "google", "state-1", "code-1").Return(authUseCase.Login{}, userDomain.ErrLoginStateNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "login failed",
+			query:       "?state=state-1&code=code-1",
+			stateCookie: "state-1",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {
+				mockUC.On("Complete", mock.Anything, "google", "state-1", "code-1").Return(authUseCase.Login{}, oidcLoginUseCase.ErrLoginFailed)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "identity linked to another user",
+			query:       "?state=state-1&code=code-1",
+			stateCookie: "state-1",
+			mockSetup: func(mockUC *MockOIDCLoginUseCase, mockAuth *MockAuthUseCase) {
+				mockUC.On("Complete", mock.Anything, "google", "state-1", "code-1").Return(authUseCase.Login{}, userDomain.ErrIdentityLinked)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUC := &MockOIDCLoginUseCase{}
+			mockAuth := &MockAuthUseCase{}
+			tt.mockSetup(mockUC, mockAuth)
+			handler := NewOIDCHandler(mockUC, mockAuth, "/forest", true)
+			req := withProvider(httptest.NewRequest(http.MethodGet, "/v1/auth/oidc/google/callback"+tt.query, nil), "google")
+			if tt.stateCookie != "" {
+				req.AddCookie(&http.Cookie{Name: OIDCStateCookie, Value: tt.stateCookie})
+			}
+			if tt.loggedIn {
+				req = withSession(req)
+			}
+
+			// Act
+			w := serve(handler.Callback, mockAuth, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusSeeOther {
+				assert.Equal(t, "/forest", w.Header().Get("Location"))
+				session := findCookie(w, "forest_session")
+				require.NotNil(t, session)
+				assert.Equal(t, "new-token", session.Value)
+				state := findCookie(w, OIDCStateCookie)
+				require.NotNil(t, state)
+				assert.Equal(t, -1, state.MaxAge)
+			} else {
+				var problem cerror.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				assert.Equal(t, tt.expectedStatus, problem.Status)
+			}
+			mockUC.AssertExpectations(t)
+			mockAuth.AssertExpectations(t)
+		})
+	}
+}
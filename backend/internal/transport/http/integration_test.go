@@ -1,26 +1,61 @@
 package http
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+	"github.com/heartmarshall/digital-forest/backend/internal/health"
+	"github.com/heartmarshall/digital-forest/backend/internal/oidc"
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
 	"github.com/heartmarshall/digital-forest/backend/internal/repository/postgres"
 	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
 	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
 	createHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/create"
+	getImageHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_image"
 	getRandomHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_random"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	apiKeysUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/admin/api_keys"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
 	createUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/create"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+	getByIDUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_by_id"
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
 	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	moderateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/moderate"
+	removeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/remove"
+	reportUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/report"
+	streamUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/stream"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	oidcLoginUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/oidc_login"
+	"github.com/heartmarshall/digital-forest/backend/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// createAPIKey выпускает ключ административного API с указанными правами.
+func createAPIKey(t *testing.T, dbPool *pgxpool.Pool, scopes ...string) (*apiKeysUseCase.APIKeysUseCase, string) {
+	t.Helper()
+	keys := apiKeysUseCase.NewAPIKeysUseCase(postgres.NewAPIKeyRepo(dbPool))
+	_, token, err := keys.Create(context.Background(), "integration", scopes)
+	require.NoError(t, err)
+	return keys, token
+}
+
 // MockValidator для интеграционных тестов
 type mockValidator struct{}
 
@@ -35,7 +70,7 @@ func (m *mockValidator) ValidateStruct(s interface{}) map[string]string {
 	if req.Author == "" {
 		errors["Author"] = "required"
 	}
-	if req.ImageData == "" {
+	if req.ImageData == "" && req.Grid == nil {
 		errors["ImageData"] = "required"
 	}
 	if len(req.Author) > 255 {
@@ -61,18 +96,20 @@ func TestHTTPIntegration(t *testing.T) {
 
 	// Create a new chi router for testing
 	router := chi.NewRouter()
-	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator)
+	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator, nil)
 	getRandomHandlerInstance := getRandomHandler.NewGetRandomHandler(getRandomUC)
+	getImageHandlerInstance := getImageHandler.NewGetImageHandler(getImageUseCase.NewGetImageUseCase(plantRepo))
 	router.Route("/v1/plants", func(r chi.Router) {
 		r.Post("/", createHandlerInstance.CreatePlant)
 		r.Get("/random", getRandomHandlerInstance.GetRandomPlants)
+		r.Get("/{id}/image.png", getImageHandlerInstance.GetPlantImage)
 	})
 
 	t.Run("full API workflow", func(t *testing.T) {
 		// Step 1: Create a plant
 		createReq := dto.CreatePlantRequest{
 			Author:    "integration_test_author",
-			ImageData: "integration_test_data",
+			ImageData: testutil.GenerateImageData(1),
 		}
 
 		reqBody, _ := json.Marshal(createReq)
@@ -89,13 +126,57 @@ func TestHTTPIntegration(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotZero(t, createResponse.ID)
 		assert.Equal(t, createReq.Author, createResponse.Author)
-		assert.Equal(t, createReq.ImageData, createResponse.ImageData)
+		// Изображение хранится как сетка 16x16, а imageData рисуется сервером заново
+		require.NotNil(t, createResponse.Grid)
+		assert.Equal(t, 16, createResponse.Grid.Width)
+		assert.Equal(t, 16, createResponse.Grid.Height)
+		assert.NotEmpty(t, createResponse.ImageData)
+
+		// Растение можно создать и напрямую из сетки
+		gridReq := dto.CreatePlantRequest{
+			Author: "integration_grid_author",
+			Grid: &dto.GridPayload{
+				Width:   2,
+				Height:  2,
+				Palette: []string{"transparent", "#228b22"},
+				Pixels:  []int{0, 1, 1, 0},
+			},
+		}
+
+		reqBody, _ = json.Marshal(gridReq)
+		req = httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var gridResponse dto.PlantResponse
+		err = json.Unmarshal(w.Body.Bytes(), &gridResponse)
+		require.NoError(t, err)
+		require.NotNil(t, gridResponse.Grid)
+		assert.Equal(t, []int{0, 1, 1, 0}, gridResponse.Grid.Pixels)
+
+		// Изображение растения отдается отдельным кешируемым PNG
+		req = httptest.NewRequest(http.MethodGet, gridResponse.ImageURL+"?scale=10", nil)
+		w = httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		req = httptest.NewRequest(http.MethodGet, "/v1/plants/999999/image.png", nil)
+		w = httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
 
 		// Step 2: Create more plants for random selection
 		for i := 0; i < 3; i++ {
 			createReq := dto.CreatePlantRequest{
 				Author:    fmt.Sprintf("author_%d", i),
-				ImageData: fmt.Sprintf("data_%d", i),
+				ImageData: testutil.GenerateImageData(10 + i),
 			}
 
 			reqBody, _ := json.Marshal(createReq)
@@ -204,7 +285,7 @@ func TestHTTPIntegrationConcurrency(t *testing.T) {
 
 	// Create a new chi router for testing
 	router := chi.NewRouter()
-	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator)
+	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator, nil)
 	getRandomHandlerInstance := getRandomHandler.NewGetRandomHandler(getRandomUC)
 	router.Route("/v1/plants", func(r chi.Router) {
 		r.Post("/", createHandlerInstance.CreatePlant)
@@ -220,7 +301,7 @@ func TestHTTPIntegrationConcurrency(t *testing.T) {
 			go func(i int) {
 				createReq := dto.CreatePlantRequest{
 					Author:    fmt.Sprintf("concurrent_author_%d", i),
-					ImageData: fmt.Sprintf("concurrent_data_%d", i),
+					ImageData: testutil.GenerateImageData(100 + i),
 				}
 
 				reqBody, _ := json.Marshal(createReq)
@@ -264,3 +345,684 @@ func TestHTTPIntegrationConcurrency(t *testing.T) {
 		assert.GreaterOrEqual(t, response["count"], float64(10))
 	})
 }
+
+func TestHTTPIntegrationStream(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	bus := events.NewPlantBus(events.DefaultSubscriberBuffer)
+	server := httptest.NewServer(NewRouter(UseCases{
+		Create:    createUseCase.NewCreateUseCase(plantRepo, createUseCase.WithPublisher(bus)),
+		GetRandom: getRandomUseCase.NewGetRandomUseCase(plantRepo),
+		GetImage:  getImageUseCase.NewGetImageUseCase(plantRepo),
+		Stream:    streamUseCase.NewStreamUseCase(bus),
+	}))
+	defer server.Close()
+	defer bus.Close()
+
+	t.Run("created plant is pushed to stream", func(t *testing.T) {
+		stream, err := http.Get(server.URL + "/v1/plants/stream")
+		require.NoError(t, err)
+		defer stream.Body.Close()
+		require.Equal(t, http.StatusOK, stream.StatusCode)
+		require.Eventually(t, func() bool { return bus.Subscribers() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+		body, _ := json.Marshal(dto.CreatePlantRequest{Author: "stream_author", ImageData: testutil.GenerateImageData(5)})
+		resp, err := http.Post(server.URL+"/v1/plants", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var created dto.PlantResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+		reader := bufio.NewReader(stream.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+				var pushed dto.PlantResponse
+				require.NoError(t, json.Unmarshal([]byte(data), &pushed))
+				assert.Equal(t, created.ID, pushed.ID)
+				assert.Equal(t, "stream_author", pushed.Author)
+				break
+			}
+		}
+	})
+}
+
+func TestHTTPIntegrationModeration(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	keys, token := createAPIKey(t, dbPool, "plants:moderate")
+	router := NewRouter(UseCases{
+		Create:    createUseCase.NewCreateUseCase(plantRepo),
+		GetRandom: getRandomUseCase.NewGetRandomUseCase(plantRepo),
+		GetByID:   getByIDUseCase.NewGetByIDUseCase(plantRepo),
+		List:      listUseCase.NewListUseCase(plantRepo),
+		Moderate:  moderateUseCase.NewModerateUseCase(plantRepo),
+		APIKeys:   keys,
+	})
+
+	do := func(method, path string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if admin {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	body, _ := json.Marshal(dto.CreatePlantRequest{Author: "moderated", ImageData: testutil.GenerateImageData(5)})
+	req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	plantPath := fmt.Sprintf("/v1/plants/%d", created.ID)
+	adminPath := fmt.Sprintf("/v1/admin/plants/%d", created.ID)
+
+	t.Run("admin routes require key with scope", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, do(http.MethodPost, adminPath+"/hide", false).Code)
+
+		_, statsToken := createAPIKey(t, dbPool, "stats:read")
+		req := httptest.NewRequest(http.MethodPost, adminPath+"/hide", nil)
+		req.Header.Set("Authorization", "Bearer "+statsToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("hidden plant disappears from public endpoints", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, adminPath+"/hide", true).Code)
+
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, plantPath, false).Code)
+
+		var page map[string]interface{}
+		require.NoError(t, json.Unmarshal(do(http.MethodGet, "/v1/plants/random?count=15", false).Body.Bytes(), &page))
+		assert.Equal(t, float64(0), page["count"])
+
+		require.NoError(t, json.Unmarshal(do(http.MethodGet, "/v1/admin/plants?status=hidden", true).Body.Bytes(), &page))
+		assert.Equal(t, float64(1), page["count"])
+
+		assert.Equal(t, http.StatusConflict, do(http.MethodPost, adminPath+"/hide", true).Code)
+	})
+
+	t.Run("restore and audit log", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, adminPath+"/restore", true).Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, plantPath, false).Code)
+
+		var log struct {
+			Events []dto.ModerationEventResponse `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal(do(http.MethodGet, adminPath+"/moderation-log", true).Body.Bytes(), &log))
+		require.Len(t, log.Events, 2)
+		assert.Equal(t, "hide", log.Events[0].Action)
+		assert.Equal(t, "restore", log.Events[1].Action)
+		assert.Equal(t, "integration", log.Events[1].Actor)
+	})
+
+	t.Run("purge removes the plant", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, adminPath+"/purge", true).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, adminPath+"/restore", true).Code)
+	})
+}
+
+func TestHTTPIntegrationReports(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	keys, token := createAPIKey(t, dbPool, "plants:moderate")
+	router := NewRouter(UseCases{
+		Create:    createUseCase.NewCreateUseCase(plantRepo),
+		GetRandom: getRandomUseCase.NewGetRandomUseCase(plantRepo),
+		GetByID:   getByIDUseCase.NewGetByIDUseCase(plantRepo),
+		Moderate:  moderateUseCase.NewModerateUseCase(plantRepo),
+		Report:    reportUseCase.NewReportUseCase(plantRepo, reportUseCase.WithThreshold(2)),
+		APIKeys:   keys,
+	})
+
+	body, _ := json.Marshal(dto.CreatePlantRequest{Author: "reported", ImageData: testutil.GenerateImageData(5)})
+	req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	report := func(ip string) int {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/v1/plants/%d/reports", created.ID),
+			strings.NewReader(`{"reason":"offensive"}`))
+		req.Header.Set("X-Forwarded-For", ip)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	admin := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act & Assert
+	assert.Equal(t, http.StatusAccepted, report("203.0.113.1"))
+	assert.Equal(t, http.StatusConflict, report("203.0.113.1"), "same reporter is counted once")
+	assert.Equal(t, http.StatusAccepted, report("203.0.113.2"))
+
+	// Порог достигнут: растение скрыто и ждет модератора
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/plants/%d", created.ID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var queue struct {
+		Plants []dto.ReportSummaryResponse `json:"plants"`
+	}
+	require.NoError(t, json.Unmarshal(admin(http.MethodGet, "/v1/admin/reports").Body.Bytes(), &queue))
+	require.Len(t, queue.Plants, 1)
+	assert.Equal(t, created.ID, queue.Plants[0].PlantID)
+	assert.Equal(t, "hidden", queue.Plants[0].Status)
+	assert.Equal(t, 2, queue.Plants[0].Reasons["offensive"])
+
+	// Модератор вернул растение - жалобы рассмотрены
+	require.Equal(t, http.StatusOK, admin(http.MethodPost, fmt.Sprintf("/v1/admin/plants/%d/restore", created.ID)).Code)
+	require.NoError(t, json.Unmarshal(admin(http.MethodGet, "/v1/admin/reports").Body.Bytes(), &queue))
+	assert.Empty(t, queue.Plants)
+}
+
+func TestHTTPIntegrationOwnership(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	router := NewRouter(UseCases{
+		Create:   createUseCase.NewCreateUseCase(plantRepo),
+		GetByID:  getByIDUseCase.NewGetByIDUseCase(plantRepo),
+		GetImage: getImageUseCase.NewGetImageUseCase(plantRepo),
+		Update:   updateUseCase.NewUpdateUseCase(plantRepo),
+		Remove:   removeUseCase.NewRemoveUseCase(plantRepo),
+	})
+
+	body, _ := json.Marshal(dto.CreatePlantRequest{Author: "owner", ImageData: testutil.GenerateImageData(5)})
+	req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotEmpty(t, created.OwnerToken)
+
+	send := func(method, token, payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, fmt.Sprintf("/v1/plants/%d", created.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set(dto.OwnerTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act & Assert: токен не попадает в ответы для остальных
+	w = send(http.MethodGet, "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.OwnerToken)
+
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPatch, "", `{"author":"thief"}`).Code)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPatch, "wrong", `{"author":"thief"}`).Code)
+
+	// Новый рисунок меняет версию изображения, а вместе с ней и imageUrl
+	grid := `{"grid":{"width":2,"height":2,"palette":["#000000","#ffffff"],"pixels":[0,1,1,0]}}`
+	w = send(http.MethodPatch, created.OwnerToken, grid)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, "owner", updated.Author)
+	assert.NotEqual(t, created.ImageURL, updated.ImageURL)
+
+	w = send(http.MethodPatch, created.OwnerToken, `{"author":"renamed"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, "renamed", updated.Author)
+
+	assert.Equal(t, http.StatusForbidden, send(http.MethodDelete, "wrong", "").Code)
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, created.OwnerToken, "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "", "").Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, created.OwnerToken, "").Code)
+}
+
+func TestHTTPIntegrationAuth(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	router := NewRouter(UseCases{
+		Create: createUseCase.NewCreateUseCase(plantRepo),
+		List:   listUseCase.NewListUseCase(plantRepo),
+		Auth:   authUseCase.NewAuthUseCase(postgres.NewUserRepo(dbPool)),
+	})
+
+	var cookie *http.Cookie
+	send := func(method, path, body, csrf string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		if csrf != "" {
+			req.Header.Set("X-CSRF-Token", csrf)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	plantBody, _ := json.Marshal(dto.CreatePlantRequest{Author: "gardener", ImageData: testutil.GenerateImageData(3)})
+
+	// Act & Assert: регистрация и вход
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/v1/auth/register", `{"username":"Gardener","password":"correct horse"}`, "").Code)
+	assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/v1/auth/register", `{"username":"gardener","password":"correct horse"}`, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/v1/auth/login", `{"username":"gardener","password":"wrong horse"}`, "").Code)
+
+	w := send(http.MethodPost, "/v1/auth/login", `{"username":"gardener","password":"correct horse"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var session dto.SessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	require.Len(t, w.Result().Cookies(), 1)
+	cookie = w.Result().Cookies()[0]
+
+	// Без CSRF-токена запрос с cookie отклоняется
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/v1/plants", string(plantBody), "").Code)
+
+	w = send(http.MethodPost, "/v1/plants", string(plantBody), session.CSRFToken)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var owned dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &owned))
+	assert.Equal(t, session.User.ID, owned.UserID)
+
+	w = send(http.MethodGet, fmt.Sprintf("/v1/plants?userId=%d", session.User.ID), "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Plants []dto.PlantResponse `json:"plants"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Plants, 1)
+	assert.Equal(t, owned.ID, page.Plants[0].ID)
+
+	// После выхода сессия недействительна, а посадка снова анонимная
+	require.Equal(t, http.StatusNoContent, send(http.MethodPost, "/v1/auth/logout", "", session.CSRFToken).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/v1/auth/session", "", "").Code)
+
+	w = send(http.MethodPost, "/v1/plants", string(plantBody), "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var anonymous dto.PlantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &anonymous))
+	assert.Zero(t, anonymous.UserID)
+}
+
+func TestHTTPIntegrationOIDC(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	issuer := testutil.NewMockOIDCIssuer(t)
+	provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Name:         "mock",
+		Issuer:       issuer.Issuer(),
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  "http://forest.test/v1/auth/oidc/mock/callback",
+	})
+	require.NoError(t, err)
+
+	userRepo := postgres.NewUserRepo(dbPool)
+	router := NewRouter(UseCases{
+		Auth:      authUseCase.NewAuthUseCase(userRepo),
+		OIDCLogin: oidcLoginUseCase.NewOIDCLoginUseCase(userRepo, []oidcLoginUseCase.Provider{provider}),
+	}, WithAfterLoginURL("/forest"))
+
+	send := func(method, path string, cookies []*http.Cookie, csrf string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		if csrf != "" {
+			req.Header.Set("X-CSRF-Token", csrf)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	cookieNamed := func(w *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, c := range w.Result().Cookies() {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("cookie %s was not set", name)
+		return nil
+	}
+	// authorize проходит страницу провайдера и возвращает адрес callback с кодом.
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	authorize := func(providerURL string) string {
+		resp, err := noRedirect.Get(providerURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		callback, err := resp.Location()
+		require.NoError(t, err)
+		return callback.RequestURI()
+	}
+	// login выполняет вход через провайдера и возвращает cookie сессии.
+	login := func() *http.Cookie {
+		w := send(http.MethodGet, "/v1/auth/oidc/mock/login", nil, "")
+		require.Equal(t, http.StatusFound, w.Code)
+		state := cookieNamed(w, "forest_oidc_state")
+
+		w = send(http.MethodGet, authorize(w.Header().Get("Location")), []*http.Cookie{state}, "")
+		require.Equal(t, http.StatusSeeOther, w.Code, w.Body.String())
+		assert.Equal(t, "/forest", w.Header().Get("Location"))
+		return cookieNamed(w, "forest_session")
+	}
+	currentUser := func(session *http.Cookie) dto.SessionResponse {
+		w := send(http.MethodGet, "/v1/auth/session", []*http.Cookie{session}, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp dto.SessionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// Act & Assert: первый вход создает пользователя, повторный входит в него же
+	w := send(http.MethodGet, "/v1/auth/oidc", nil, "")
+	assert.JSONEq(t, `{"providers":["mock"]}`, w.Body.String())
+
+	first := currentUser(login())
+	assert.Equal(t, "mock.user", first.User.Username)
+	assert.Equal(t, first.User.ID, currentUser(login()).User.ID)
+
+	// Callback нельзя повторить: state одноразовый
+	w = send(http.MethodGet, "/v1/auth/oidc/mock/login", nil, "")
+	state := cookieNamed(w, "forest_oidc_state")
+	callback := authorize(w.Header().Get("Location"))
+	require.Equal(t, http.StatusSeeOther, send(http.MethodGet, callback, []*http.Cookie{state}, "").Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, callback, []*http.Cookie{state}, "").Code)
+
+	// Callback без cookie состояния отклоняется: вход начинался в другом браузере
+	w = send(http.MethodGet, "/v1/auth/oidc/mock/login", nil, "")
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodGet, authorize(w.Header().Get("Location")), nil, "").Code)
+
+	// Привязка второй учетной записи провайдера к уже вошедшему пользователю
+	session := login()
+	csrf := currentUser(session).CSRFToken
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/v1/auth/oidc/mock/link", []*http.Cookie{session}, "").Code)
+
+	issuer.SetUser("second-subject", "second", "second@example.com")
+	w = send(http.MethodPost, "/v1/auth/oidc/mock/link", []*http.Cookie{session}, csrf)
+	require.Equal(t, http.StatusOK, w.Code)
+	var link struct {
+		RedirectURL string `json:"redirectUrl"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	w = send(http.MethodGet, authorize(link.RedirectURL), []*http.Cookie{session, cookieNamed(w, "forest_oidc_state")}, "")
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, first.User.ID, currentUser(cookieNamed(w, "forest_session")).User.ID)
+
+	// Теперь вход второй учетной записью приводит к тому же пользователю
+	assert.Equal(t, first.User.ID, currentUser(login()).User.ID)
+}
+
+func TestHTTPIntegrationRateLimit(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	router := NewRouter(UseCases{
+		Create: createUseCase.NewCreateUseCase(plantRepo),
+	}, WithRateLimits(postgres.NewRateLimitStore(dbPool), map[string][]appMiddleware.RateLimitRule{
+		RouteCreatePlant: {
+			{By: appMiddleware.RateLimitByIP, Limit: ratelimit.Limit{Requests: 10, Period: time.Minute}},
+			{By: appMiddleware.RateLimitByAuthor, Limit: ratelimit.Limit{Requests: 2, Period: time.Hour}},
+		},
+	}))
+
+	create := func(author, ip string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.CreatePlantRequest{Author: author, ImageData: testutil.GenerateImageData(5)})
+		req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Real-IP", ip)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act & Assert: автор ограничен независимо от адреса
+	require.Equal(t, http.StatusCreated, create("flooder", "10.0.0.1").Code)
+	w := create("Flooder", "10.0.0.2")
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = create("flooder", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+
+	// Отклоненный запрос не создал растение
+	var count int
+	require.NoError(t, dbPool.QueryRow(context.Background(), "SELECT COUNT(*) FROM plants").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	// Адрес ограничен независимо от автора
+	for i := range 9 {
+		require.Equal(t, http.StatusCreated, create(fmt.Sprintf("author-%d", i), "10.0.0.1").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, create("someone-else", "10.0.0.1").Code)
+}
+
+func TestHTTPIntegrationChallenge(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	router := NewRouter(UseCases{
+		Create: createUseCase.NewCreateUseCase(plantRepo),
+		Challenge: challengeUseCase.NewChallengeUseCase([]byte("secret"), postgres.NewSpentChallengeStore(dbPool), plantRepo,
+			challengeUseCase.WithDifficulty(8, 12),
+		),
+	})
+
+	issue := func() dto.ChallengeResponse {
+		req := httptest.NewRequest(http.MethodGet, "/v1/challenges", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var challenge dto.ChallengeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &challenge))
+		return challenge
+	}
+	create := func(challenge, solution string) int {
+		body, _ := json.Marshal(dto.CreatePlantRequest{
+			Author:    "pow_author",
+			ImageData: testutil.GenerateImageData(5),
+			Challenge: challenge,
+			Solution:  solution,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Act & Assert
+	challenge := issue()
+	assert.Equal(t, 8, challenge.Difficulty)
+	assert.Equal(t, "sha256", challenge.Algorithm)
+
+	assert.Equal(t, http.StatusBadRequest, create("", ""))
+
+	solution := hashcash.Solve(challenge.Challenge, challenge.Difficulty)
+	assert.Equal(t, http.StatusCreated, create(challenge.Challenge, solution))
+	assert.Equal(t, http.StatusForbidden, create(challenge.Challenge, solution), "solution must not be reused")
+}
+
+func TestHTTPIntegrationIdempotency(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	router := NewRouter(UseCases{
+		Create: createUseCase.NewCreateUseCase(plantRepo),
+	}, WithIdempotency(postgres.NewIdempotencyStore(dbPool), time.Hour))
+
+	imageData := testutil.GenerateImageData(5)
+	create := func(key, author string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.CreatePlantRequest{Author: author, ImageData: imageData})
+		req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	first := create("2d8b8a2c-51c4-4a8e-9a57-0f3b4d2f8e11", "retrying_author")
+	retry := create("2d8b8a2c-51c4-4a8e-9a57-0f3b4d2f8e11", "retrying_author")
+	changed := create("2d8b8a2c-51c4-4a8e-9a57-0f3b4d2f8e11", "another_author")
+
+	// Assert: повтор вернул тот же ответ вместе с ownerToken, а растение одно
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusUnprocessableEntity, changed.Code)
+
+	var created dto.PlantResponse
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &created))
+	require.NotEmpty(t, created.OwnerToken)
+
+	var count int
+	require.NoError(t, dbPool.QueryRow(context.Background(), "SELECT COUNT(*) FROM plants").Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// В базе нет ни ключа, ни открытого ответа
+	var stored []byte
+	require.NoError(t, dbPool.QueryRow(context.Background(), "SELECT body FROM idempotency_keys").Scan(&stored))
+	assert.NotContains(t, string(stored), created.OwnerToken)
+}
+
+func TestHTTPIntegrationDuplicates(t *testing.T) {
+	// Arrange
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	plantRepo := postgres.NewPlantRepo(dbPool)
+	keys, token := createAPIKey(t, dbPool, "plants:moderate")
+	newRouter := func(mode domain.DuplicateMode) http.Handler {
+		return NewRouter(UseCases{
+			Create:     createUseCase.NewCreateUseCase(plantRepo, createUseCase.WithDuplicateCheck(mode, domain.DefaultDuplicateDistance)),
+			Duplicates: duplicatesUseCase.NewDuplicatesUseCase(plantRepo),
+			APIKeys:    keys,
+		})
+	}
+	create := func(router http.Handler, variant int) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.CreatePlantRequest{Author: "copycat", ImageData: testutil.GenerateImageData(variant)})
+		req := httptest.NewRequest(http.MethodPost, "/v1/plants", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("reject mode refuses a copy", func(t *testing.T) {
+		router := newRouter(domain.DuplicateReject)
+
+		require.Equal(t, http.StatusCreated, create(router, 1).Code)
+		assert.Equal(t, http.StatusConflict, create(router, 1).Code)
+		assert.Equal(t, http.StatusCreated, create(router, 2).Code)
+	})
+
+	t.Run("flag mode sends a copy to moderation", func(t *testing.T) {
+		router := newRouter(domain.DuplicateFlag)
+
+		w := create(router, 1)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created dto.PlantResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, "pending", created.Status)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/admin/duplicates", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Clusters []dto.DuplicateClusterResponse `json:"clusters"`
+			Count    int                            `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Equal(t, 1, response.Count)
+		assert.True(t, response.Clusters[0].Exact)
+		require.Len(t, response.Clusters[0].Plants, 2)
+		assert.Equal(t, created.ID, response.Clusters[0].Plants[1].ID)
+	})
+}
+
+func TestHTTPIntegrationHealth(t *testing.T) {
+	// Arrange: тестовая база создана встроенными миграциями, поэтому схема на последней версии
+	dbPool, _, container := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, dbPool, container)
+
+	latest, err := postgres.LatestMigration(migrations.FS)
+	require.NoError(t, err)
+	checker := health.New(
+		health.WithCheck("database", health.Ping(dbPool, time.Second)),
+		health.WithCheck("migrations", health.MigrationVersion(postgres.NewMigrationRepo(dbPool), latest)),
+	)
+	router := NewRouter(UseCases{}, WithHealth(checker))
+	probe := func(path string) (*httptest.ResponseRecorder, health.Report) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w, report
+	}
+
+	// Act
+	live, _ := probe("/healthz")
+	ready, report := probe("/readyz")
+	checker.Drain()
+	draining, drainingReport := probe("/readyz")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, live.Code)
+
+	assert.Equal(t, http.StatusOK, ready.Code)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Components["database"].Status)
+	assert.Equal(t, health.StatusOK, report.Components["migrations"].Status)
+
+	assert.Equal(t, http.StatusServiceUnavailable, draining.Code)
+	assert.Equal(t, health.StatusDraining, drainingReport.Status)
+}
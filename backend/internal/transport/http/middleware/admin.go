@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+type actorKey struct{}
+
+// APIKeyAuthenticator находит ключ административного API по токену.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (apikey.APIKey, error)
+}
+
+// APIKeyAuth пропускает только запросы с действующим ключом в заголовке "Authorization: Bearer <token>"
+// и сохраняет ключ в контексте (см. apikey.FromContext). Права ключа проверяет RequireScope.
+// Если auth == nil, административный API выключен и все запросы получают 403.
+//
+// Модератором в журнале считается имя ключа (см. ActorFromContext): подписаться чужим именем нельзя,
+// поэтому каждому модератору выпускается свой ключ.
+func APIKeyAuth(auth APIKeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auth == nil {
+				cerror.Respond(w, r, cerror.New(cerror.Forbidden, "Admin API is disabled"))
+				return
+			}
+
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			key, err := auth.Authenticate(r.Context(), strings.TrimSpace(token))
+			if errors.Is(err, apikey.ErrNotFound) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				cerror.Respond(w, r, cerror.New(cerror.Unauthorized, "Invalid or missing API key"))
+				return
+			}
+			if err != nil {
+				cerror.Respond(w, r, err)
+				return
+			}
+
+			ctx := apikey.WithKey(r.Context(), key)
+			ctx = context.WithValue(ctx, actorKey{}, key.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope пропускает только запросы, ключ которых (см. APIKeyAuth) имеет право scope.
+func RequireScope(scope apikey.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := apikey.FromContext(r.Context())
+			if !ok || !key.HasScope(scope) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin", error="insufficient_scope", scope="`+string(scope)+`"`)
+				cerror.Respond(w, r, cerror.New(cerror.Forbidden, "API key lacks scope "+string(scope)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ActorFromContext возвращает имя модератора - имя ключа, проверенного APIKeyAuth, - или пустую строку.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
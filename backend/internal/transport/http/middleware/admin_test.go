@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKeys принимает только токен "secret".
+type fakeKeys struct {
+	err error
+}
+
+func (f fakeKeys) Authenticate(ctx context.Context, token string) (apikey.APIKey, error) {
+	if f.err != nil {
+		return apikey.APIKey{}, f.err
+	}
+	if token != "secret" {
+		return apikey.APIKey{}, apikey.ErrNotFound
+	}
+	return apikey.APIKey{ID: 1, Name: "ci", Scopes: []apikey.Scope{apikey.ScopePlantsModerate}}, nil
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		auth           APIKeyAuthenticator
+		authorization  string
+		expectedStatus int
+		expectedActor  string
+	}{
+		{
+			name:           "valid key",
+			auth:           fakeKeys{},
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+			expectedActor:  "ci",
+		},
+		{
+			name:           "wrong key",
+			auth:           fakeKeys{},
+			authorization:  "Bearer guess",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing key",
+			auth:           fakeKeys{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "storage error",
+			auth:           fakeKeys{err: errors.New("db down")},
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "admin api disabled",
+			auth:           nil,
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var actor string
+			handler := APIKeyAuth(tt.auth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor = ActorFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/admin/plants/1/hide", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			// Модератор не может подписаться чужим именем: в журнал попадает имя ключа
+			req.Header.Set("X-Moderator", "alice")
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedActor, actor)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scope          apikey.Scope
+		expectedStatus int
+	}{
+		{name: "scope granted", scope: apikey.ScopePlantsModerate, expectedStatus: http.StatusOK},
+		{name: "scope missing", scope: apikey.ScopeStatsRead, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := APIKeyAuth(fakeKeys{})(RequireScope(tt.scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+			req := httptest.NewRequest(http.MethodGet, "/v1/admin/plants", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), `scope="stats:read"`)
+			}
+		})
+	}
+
+	t.Run("without key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RequireScope(apikey.ScopeStatsRead)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
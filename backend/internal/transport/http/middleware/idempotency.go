@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	plantDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+const (
+	// IdempotencyKeyHeader - заголовок с ключом идемпотентности, который клиент повторяет при каждой попытке.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader отмечает ответ, взятый из сохраненного, а не полученный заново.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Ключ должен быть случайным (например, UUID): по нему расшифровывается сохраненный ответ.
+const (
+	minIdempotencyKeyLength = 16
+	maxIdempotencyKeyLength = 255
+)
+
+// maxIdempotentBodyBytes - сколько тела запроса Idempotency готова прочитать в память:
+// изображение наибольшего допустимого размера в base64 и запас на остальной JSON.
+var maxIdempotentBodyBytes = int64(plantDomain.DefaultImageLimits.MaxBytes)*4/3 + 64*1024
+
+// Idempotency выполняет запрос с заголовком Idempotency-Key не больше одного раза за ttl.
+// Успешный (2xx) ответ сохраняется, и повтор с тем же ключом и тем же телом получает его без повторного выполнения.
+// Тот же ключ с другим телом - 422, повтор во время выполнения первого запроса - 409.
+// Неуспешные ответы не сохраняются: после них запрос можно повторить с тем же ключом.
+// Запросы без заголовка проходят как обычно; при недоступном хранилище тоже.
+// Тело запроса с заголовком читается целиком, поэтому оно ограничено maxIdempotentBodyBytes, больше - 413.
+func Idempotency(store idempotency.Store, route string, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !validIdempotencyKey(key) {
+				cerror.Respond(w, r, cerror.New(cerror.Invalid, "Idempotency-Key must be 16 to 255 printable ASCII characters, e.g. a UUID"))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				cerror.Respond(w, r, cerror.New(cerror.TooLarge, "Request body is too large"))
+				return
+			}
+			if err != nil {
+				cerror.Respond(w, r, cerror.New(cerror.Invalid, "Failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Ключи разных пользователей не пересекаются; анонимные делят одно пространство.
+			scope := route + ":anonymous"
+			if u, ok := userDomain.FromContext(r.Context()); ok {
+				scope = route + ":user:" + strconv.Itoa(u.ID)
+			}
+			keyHash := idempotency.HashKey(scope, key)
+			sum := sha256.Sum256(body)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			record, started, err := store.Begin(r.Context(), keyHash, fingerprint, ttl)
+			if err != nil {
+				logger.FromContext(r.Context()).Error("idempotency failed", slog.String("route", route), slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !started {
+				replay(w, r, key, fingerprint, record)
+				return
+			}
+
+			// Клиент мог отключиться, не дождавшись ответа, - именно ради этого случая ответ и сохраняется,
+			// поэтому отмена контекста запроса не должна мешать записи.
+			ctx := context.WithoutCancel(r.Context())
+			// Ключ освобождается при любом исходе, кроме сохраненного успешного ответа, в том числе
+			// при панике обработчика: иначе повторы получали бы 409, пока запись не устареет.
+			stored := false
+			defer func() {
+				if stored {
+					return
+				}
+				if err := store.Release(ctx, keyHash); err != nil {
+					logger.FromContext(r.Context()).Error("idempotency failed", slog.String("route", route), slog.Any("error", err))
+				}
+			}()
+
+			rec := &recordingWriter{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			if rec.status < 200 || rec.status >= 300 {
+				return
+			}
+			sealed, err := idempotency.Seal(key, rec.body.Bytes())
+			if err == nil {
+				err = store.Complete(ctx, keyHash, rec.status, sealed)
+			}
+			if err != nil {
+				logger.FromContext(r.Context()).Error("idempotency failed", slog.String("route", route), slog.Any("error", err))
+				return
+			}
+			stored = true
+		})
+	}
+}
+
+// replay отвечает на повтор запроса по существующей записи.
+func replay(w http.ResponseWriter, r *http.Request, key, fingerprint string, record idempotency.Record) {
+	if record.Fingerprint != fingerprint {
+		cerror.Respond(w, r, cerror.New(cerror.Unprocessable, "Idempotency-Key was already used with a different request body"))
+		return
+	}
+	if record.Status == 0 {
+		w.Header().Set("Retry-After", "1")
+		cerror.Respond(w, r, cerror.New(cerror.Conflict, "A request with this Idempotency-Key is still in progress"))
+		return
+	}
+
+	body, err := idempotency.Open(key, record.Body)
+	if err != nil {
+		cerror.Respond(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	w.Write(body)
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) < minIdempotencyKeyLength || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// recordingWriter передает ответ клиенту и одновременно запоминает его статус и тело.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+)
+
+const testIdempotencyKey = "9b2f6a0e-4c1d-4f7e-8a3b-1d2c3e4f5a6b"
+
+// brokenIdempotencyStore - хранилище, которое всегда недоступно.
+type brokenIdempotencyStore struct{}
+
+func (brokenIdempotencyStore) Begin(ctx context.Context, keyHash, fingerprint string, ttl time.Duration) (idempotency.Record, bool, error) {
+	return idempotency.Record{}, false, errors.New("db is down")
+}
+
+func (brokenIdempotencyStore) Complete(ctx context.Context, keyHash string, status int, body []byte) error {
+	return errors.New("db is down")
+}
+
+func (brokenIdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	return errors.New("db is down")
+}
+
+// countingHandler отвечает кодом status и номером вызова в теле.
+func countingHandler(status int, calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]int32{"call": n})
+	})
+}
+
+func newIdempotentRequest(key, body string, u *userDomain.User) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/plants", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if u != nil {
+		req = req.WithContext(userDomain.WithUser(req.Context(), *u))
+	}
+	return req
+}
+
+func TestIdempotency(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		store    idempotency.Store
+		requests []*http.Request
+		// expectedStatuses и expectedBodies - ответы на запросы по порядку.
+		expectedStatuses []int
+		expectedBodies   []string
+		expectedCalls    int32
+	}{
+		{
+			name:   "replay returns original response",
+			status: http.StatusCreated,
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, nil),
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedBodies:   []string{`{"call":1}`, `{"call":1}`},
+			expectedCalls:    1,
+		},
+		{
+			name:   "same key with different body",
+			status: http.StatusCreated,
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, nil),
+				newIdempotentRequest(testIdempotencyKey, `{"author":"b"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			expectedCalls:    1,
+		},
+		{
+			name:   "requests without key are not deduplicated",
+			status: http.StatusCreated,
+			requests: []*http.Request{
+				newIdempotentRequest("", `{"author":"a"}`, nil),
+				newIdempotentRequest("", `{"author":"a"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedBodies:   []string{`{"call":1}`, `{"call":2}`},
+			expectedCalls:    2,
+		},
+		{
+			name:   "failed response is not stored",
+			status: http.StatusUnprocessableEntity,
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, nil),
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusUnprocessableEntity, http.StatusUnprocessableEntity},
+			expectedBodies:   []string{`{"call":1}`, `{"call":2}`},
+			expectedCalls:    2,
+		},
+		{
+			name:   "keys of different users do not collide",
+			status: http.StatusCreated,
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, &userDomain.User{ID: 1}),
+				newIdempotentRequest(testIdempotencyKey, `{"author":"a"}`, &userDomain.User{ID: 2}),
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedBodies:   []string{`{"call":1}`, `{"call":2}`},
+			expectedCalls:    2,
+		},
+		{
+			name:             "short key",
+			status:           http.StatusCreated,
+			requests:         []*http.Request{newIdempotentRequest("1", `{}`, nil)},
+			expectedStatuses: []int{http.StatusBadRequest},
+		},
+		{
+			name:   "body too large",
+			status: http.StatusCreated,
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{"imageData":"`+strings.Repeat("A", int(maxIdempotentBodyBytes))+`"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusRequestEntityTooLarge},
+		},
+		{
+			name:   "store error fails open",
+			status: http.StatusCreated,
+			store:  brokenIdempotencyStore{},
+			requests: []*http.Request{
+				newIdempotentRequest(testIdempotencyKey, `{}`, nil),
+				newIdempotentRequest(testIdempotencyKey, `{}`, nil),
+			},
+			expectedStatuses: []int{http.StatusCreated, http.StatusCreated},
+			expectedCalls:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := tt.store
+			if store == nil {
+				store = idempotency.NewMemoryStore()
+			}
+			var calls atomic.Int32
+			handler := Idempotency(store, "create_plant", time.Hour)(countingHandler(tt.status, &calls))
+
+			for i, req := range tt.requests {
+				// Act
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatuses[i], rr.Code, "request %d", i)
+				if tt.expectedBodies != nil {
+					assert.JSONEq(t, tt.expectedBodies[i], rr.Body.String(), "request %d", i)
+				}
+			}
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestIdempotency_ReplayHeaders(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	handler := Idempotency(idempotency.NewMemoryStore(), "create_plant", time.Hour)(countingHandler(http.StatusCreated, &calls))
+
+	// Act
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+
+	// Assert
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	// Arrange
+	store := idempotency.NewMemoryStore()
+	entered, release := make(chan struct{}), make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := Idempotency(store, "create_plant", time.Hour)(slow)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+	}()
+	<-entered
+
+	// Act
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+	close(release)
+	<-done
+
+	// Assert
+	require.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+}
+
+func TestIdempotency_HandlerPanic(t *testing.T) {
+	// Arrange: первый вызов обработчика паникует, второй отвечает как обычно
+	store := idempotency.NewMemoryStore()
+	var calls atomic.Int32
+	next := countingHandler(http.StatusCreated, &calls)
+	flaky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Load() == 0 {
+			calls.Add(1)
+			panic("boom")
+		}
+		next.ServeHTTP(w, r)
+	})
+	handler := Idempotency(store, "create_plant", time.Hour)(flaky)
+
+	// Act
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+	})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newIdempotentRequest(testIdempotencyKey, `{}`, nil))
+
+	// Assert: ключ освобожден, повтор выполняется, а не получает 409
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, int32(2), calls.Load())
+}
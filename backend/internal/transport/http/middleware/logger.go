@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// Logger кладет в контекст запроса логгер с request_id, методом, путем и адресом клиента
+// (и trace_id, если запрос трассируется), а по завершении запроса пишет запись с маршрутом, статусом и длительностью.
+// Подключается после RequestID, RealIP и Tracing, чтобы их значения уже были в запросе.
+func Logger(base *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			l := base.With(
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("remote_ip", clientIP(r)),
+			)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				l = l.With(slog.String("trace_id", sc.TraceID().String()))
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(logger.WithContext(r.Context(), l)))
+
+			status := responseStatus(ww)
+			attrs := []slog.Attr{
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int("bytes", ww.BytesWritten()),
+			}
+			if route := routePattern(r); route != "" {
+				attrs = append(attrs, slog.String("route", route))
+			}
+			if id := chi.URLParam(r, "id"); id != "" {
+				attrs = append(attrs, slog.String("plant_id", id))
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			l.LogAttrs(r.Context(), level, "request completed", attrs...)
+		})
+	}
+}
+
+// LogRoute добавляет к логгеру запроса шаблон маршрута и идентификатор растения из пути.
+// Подключается внутри группы маршрутов (r.Group или r.With): там маршрут запроса уже выбран,
+// поэтому записи use cases и репозиториев тоже получают route и plant_id.
+// Запросы, переданные во вложенный роутер (шаблон оканчивается на "/*"), пропускаются: маршрут добавит его LogRoute.
+func LogRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routePattern(r)
+		if strings.HasSuffix(route, "/*") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var args []any
+		if route != "" {
+			args = append(args, slog.String("route", route))
+		}
+		if id := chi.URLParam(r, "id"); id != "" {
+			args = append(args, slog.String("plant_id", id))
+		}
+		if len(args) > 0 {
+			r = r.WithContext(logger.With(r.Context(), args...))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recoverer перехватывает панику обработчика, пишет ее со стеком в логгер запроса и отвечает 500.
+// http.ErrAbortHandler пробрасывается дальше: им обработчик прерывает ответ намеренно.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := fmt.Errorf("panic: %v\n%s", rec, debug.Stack())
+			// Соединение WebSocket уже перехвачено, ответить на него нельзя.
+			if r.Header.Get("Connection") == "Upgrade" {
+				logger.FromContext(r.Context()).Error("request failed", slog.Any("error", err))
+				return
+			}
+			cerror.Respond(w, r, err)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP возвращает адрес клиента. После middleware.RealIP в RemoteAddr может оказаться адрес без порта.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// logRecords разбирает записи JSON-логгера по одной на строку.
+func logRecords(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedLevel  string
+		// expectedHandlerLog - атрибуты записи, которую обработчик пишет через logger.FromContext; nil - записи нет.
+		expectedHandlerLog map[string]any
+		expectedRoute      string
+		expectedPlantID    string
+	}{
+		{
+			name:           "plant route",
+			method:         http.MethodGet,
+			path:           "/v1/plants/42",
+			expectedStatus: http.StatusOK,
+			expectedLevel:  "INFO",
+			expectedHandlerLog: map[string]any{
+				"msg": "plant loaded", "route": "/v1/plants/{id}", "plant_id": "42", "remote_ip": "192.0.2.1",
+			},
+			expectedRoute:   "/v1/plants/{id}",
+			expectedPlantID: "42",
+		},
+		{
+			name:           "nested router",
+			method:         http.MethodPost,
+			path:           "/v1/admin/plants/7/hide",
+			expectedStatus: http.StatusOK,
+			expectedLevel:  "INFO",
+			expectedHandlerLog: map[string]any{
+				"msg": "plant loaded", "route": "/v1/admin/plants/{id}/hide", "plant_id": "7",
+			},
+			expectedRoute:   "/v1/admin/plants/{id}/hide",
+			expectedPlantID: "7",
+		},
+		{
+			name:           "server error",
+			method:         http.MethodPost,
+			path:           "/v1/plants",
+			expectedStatus: http.StatusInternalServerError,
+			expectedLevel:  "ERROR",
+			expectedHandlerLog: map[string]any{
+				"msg": "request failed", "route": "/v1/plants", "error": "Failed to create plant: " + assert.AnError.Error(),
+			},
+			expectedRoute: "/v1/plants",
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/wp-admin/install.php",
+			expectedStatus: http.StatusNotFound,
+			expectedLevel:  "INFO",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+			base := slog.New(slog.NewJSONHandler(&out, nil))
+
+			loadPlant := func(w http.ResponseWriter, r *http.Request) {
+				logger.FromContext(r.Context()).Info("plant loaded")
+			}
+			router := chi.NewRouter()
+			router.Use(middleware.RequestID)
+			router.Use(Logger(base))
+			router.Route("/v1", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(LogRoute)
+					r.Get("/plants/{id}", loadPlant)
+					r.Post("/plants", func(w http.ResponseWriter, r *http.Request) {
+						cerror.Respond(w, r, &cerror.Error{Detail: "Failed to create plant", Err: assert.AnError})
+					})
+					r.Route("/admin", func(r chi.Router) {
+						r.Group(func(r chi.Router) {
+							r.Use(LogRoute)
+							r.Post("/plants/{id}/hide", loadPlant)
+						})
+					})
+				})
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			require.Equal(t, tt.expectedStatus, w.Code)
+			records := logRecords(t, &out)
+			expectedRecords := 1
+			if tt.expectedHandlerLog != nil {
+				expectedRecords = 2
+			}
+			require.Len(t, records, expectedRecords)
+
+			if tt.expectedHandlerLog != nil {
+				for key, value := range tt.expectedHandlerLog {
+					assert.Equal(t, value, records[0][key], key)
+				}
+				assert.NotEmpty(t, records[0]["request_id"])
+			}
+
+			completed := records[len(records)-1]
+			assert.Equal(t, "request completed", completed["msg"])
+			assert.Equal(t, tt.expectedLevel, completed["level"])
+			assert.EqualValues(t, tt.expectedStatus, completed["status"])
+			assert.Equal(t, tt.method, completed["method"])
+			assert.Equal(t, tt.path, completed["path"])
+			assert.NotEmpty(t, completed["request_id"])
+			assert.Contains(t, completed, "latency")
+			if tt.expectedRoute != "" {
+				assert.Equal(t, tt.expectedRoute, completed["route"])
+			} else {
+				assert.NotContains(t, completed, "route")
+			}
+			if tt.expectedPlantID != "" {
+				assert.Equal(t, tt.expectedPlantID, completed["plant_id"])
+			} else {
+				assert.NotContains(t, completed, "plant_id")
+			}
+		})
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&out, nil))
+
+	router := chi.NewRouter()
+	router.Use(Logger(base))
+	router.Use(Recoverer)
+	router.Get("/v1/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	})
+
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/plants/1", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, cerror.ContentType, w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), "nil map")
+
+	records := logRecords(t, &out)
+	require.Len(t, records, 2)
+	assert.Equal(t, "request failed", records[0]["msg"])
+	assert.Contains(t, records[0]["error"], "panic: nil map")
+	assert.Contains(t, records[0]["error"], "goroutine")
+	assert.EqualValues(t, http.StatusInternalServerError, records[1]["status"])
+}
+
+func TestRecoverer_AbortHandler(t *testing.T) {
+	// Arrange
+	handler := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	// Act & Assert
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
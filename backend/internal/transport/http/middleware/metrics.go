@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute - метка маршрута для запросов, не попавших ни в один маршрут.
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics записывает метрики HTTP-запросов (см. metrics.HTTP).
+type HTTPMetrics interface {
+	Started()
+	Finished(route, method string, status int, elapsed time.Duration)
+}
+
+// Metrics записывает каждый запрос в m с шаблоном маршрута chi, например /v1/plants/{id}.
+// Middleware подключается к корневому роутеру: полный шаблон известен только после обработки запроса.
+func Metrics(m HTTPMetrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			m.Started()
+
+			defer func() {
+				route := routePattern(r)
+				if route == "" {
+					route = unmatchedRoute
+				}
+				m.Finished(route, r.Method, responseStatus(ww), time.Since(start))
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// routePattern возвращает шаблон маршрута chi, по которому обработан запрос, или пустую строку.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
+// responseStatus возвращает статус ответа. Статус не записан, если обработчик ничего не ответил
+// или соединение перехвачено (WebSocket); тогда net/http считает ответ успешным.
+func responseStatus(ww middleware.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedRequest - запрос, записанный recordingMetrics.
+type recordedRequest struct {
+	route  string
+	method string
+	status int
+}
+
+// recordingMetrics запоминает записанные запросы.
+type recordingMetrics struct {
+	inFlight int
+	requests []recordedRequest
+}
+
+func (m *recordingMetrics) Started() {
+	m.inFlight++
+}
+
+func (m *recordingMetrics) Finished(route, method string, status int, elapsed time.Duration) {
+	m.inFlight--
+	m.requests = append(m.requests, recordedRequest{route: route, method: method, status: status})
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected recordedRequest
+	}{
+		{
+			name:     "route pattern instead of path",
+			method:   http.MethodGet,
+			path:     "/v1/plants/42",
+			expected: recordedRequest{route: "/v1/plants/{id}", method: http.MethodGet, status: http.StatusOK},
+		},
+		{
+			name:     "status of error response",
+			method:   http.MethodDelete,
+			path:     "/v1/plants/42",
+			expected: recordedRequest{route: "/v1/plants/{id}", method: http.MethodDelete, status: http.StatusForbidden},
+		},
+		{
+			name:     "handler without explicit status",
+			method:   http.MethodPost,
+			path:     "/v1/plants",
+			expected: recordedRequest{route: "/v1/plants", method: http.MethodPost, status: http.StatusOK},
+		},
+		{
+			name:     "unknown path",
+			method:   http.MethodGet,
+			path:     "/wp-admin/install.php",
+			expected: recordedRequest{route: unmatchedRoute, method: http.MethodGet, status: http.StatusNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			m := &recordingMetrics{}
+			router := chi.NewRouter()
+			router.Use(Metrics(m))
+			router.Route("/v1", func(r chi.Router) {
+				r.Get("/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("{}"))
+				})
+				r.Delete("/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				})
+				r.Post("/plants", func(w http.ResponseWriter, r *http.Request) {})
+			})
+
+			// Act
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Assert
+			assert.Equal(t, []recordedRequest{tt.expected}, m.requests)
+			assert.Zero(t, m.inFlight)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// RateLimitKey - признак, по которому запросы попадают в одну корзину.
+type RateLimitKey string
+
+const (
+	// RateLimitByIP - адрес клиента после middleware.RealIP.
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitBySession - вошедший пользователь. Анонимные запросы этим правилом не ограничиваются.
+	RateLimitBySession RateLimitKey = "session"
+	// RateLimitByAuthor - поле author в JSON-теле запроса без учета регистра. Запросы без автора не ограничиваются.
+	RateLimitByAuthor RateLimitKey = "author"
+)
+
+// maxAuthorPeekBytes - сколько байт тела читается в поисках автора. У тела длиннее правило по автору не применяется.
+const maxAuthorPeekBytes = 8 << 20
+
+// ParseRateLimitKey проверяет название признака из конфигурации.
+func ParseRateLimitKey(s string) (RateLimitKey, error) {
+	switch key := RateLimitKey(s); key {
+	case RateLimitByIP, RateLimitBySession, RateLimitByAuthor:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unknown rate limit key %q", s)
+	}
+}
+
+// RateLimitRule - лимит для запросов маршрута с одинаковым значением признака By.
+type RateLimitRule struct {
+	By    RateLimitKey
+	Limit ratelimit.Limit
+}
+
+// RateLimit ограничивает частоту запросов к маршруту route корзинами токенов из store.
+// Запрос должен пройти все правила; правила проверяются по порядку до первого отказа.
+// Отказ - 429 с Retry-After. Каждый ответ получает заголовки RateLimit-Limit, RateLimit-Remaining
+// и RateLimit-Reset самого строгого из правил.
+//
+// Если хранилище недоступно, запрос пропускается: ограничитель не должен ронять создание растений вместе с собой.
+func RateLimit(store ratelimit.Store, route string, rules []RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				tightest ratelimit.Result
+				policy   ratelimit.Limit
+				found    bool
+			)
+			for _, rule := range rules {
+				value, ok := rateLimitValue(r, rule.By)
+				if !ok {
+					continue
+				}
+
+				result, err := store.Take(r.Context(), route+":"+string(rule.By)+":"+value, rule.Limit)
+				if err != nil {
+					logger.FromContext(r.Context()).Error("rate limit failed", slog.String("route", route), slog.Any("error", err))
+					continue
+				}
+				if !found || !result.Allowed || result.Remaining < tightest.Remaining {
+					tightest, policy, found = result, rule.Limit, true
+				}
+				if !result.Allowed {
+					break
+				}
+			}
+
+			if found {
+				setRateLimitHeaders(w, tightest, policy)
+				if !tightest.Allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+					cerror.Respond(w, r, cerror.New(cerror.RateLimited, "Too many requests, please slow down"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitValue возвращает значение признака для запроса или false, если правило к запросу не относится.
+func rateLimitValue(r *http.Request, by RateLimitKey) (string, bool) {
+	switch by {
+	case RateLimitByIP:
+		ip := clientIP(r)
+		return ip, ip != ""
+	case RateLimitBySession:
+		u, ok := userDomain.FromContext(r.Context())
+		if !ok {
+			return "", false
+		}
+		return strconv.Itoa(u.ID), true
+	case RateLimitByAuthor:
+		author := peekAuthor(r)
+		return author, author != ""
+	default:
+		return "", false
+	}
+}
+
+// peekAuthor читает поле author из JSON-тела и возвращает тело на место для обработчика.
+func peekAuthor(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuthorPeekBytes+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxAuthorPeekBytes {
+		return ""
+	}
+
+	var payload struct {
+		Author string `json:"author"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(payload.Author))
+}
+
+// setRateLimitHeaders выставляет заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers).
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result, limit ratelimit.Limit) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, ceilSeconds(limit.Period), limit.Capacity()))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
+)
+
+// failingStore - хранилище, которое всегда недоступно.
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("db is down")
+}
+
+func TestParseRateLimitKey(t *testing.T) {
+	for _, s := range []string{"ip", "session", "author"} {
+		key, err := ParseRateLimitKey(s)
+		assert.NoError(t, err)
+		assert.Equal(t, RateLimitKey(s), key)
+	}
+
+	_, err := ParseRateLimitKey("cookie")
+	assert.Error(t, err)
+}
+
+func TestRateLimit(t *testing.T) {
+	oncePerMinute := ratelimit.Limit{Requests: 1, Period: time.Minute}
+
+	tests := []struct {
+		name     string
+		rules    []RateLimitRule
+		requests []*http.Request
+		// expectedStatuses - статус каждого запроса по порядку.
+		expectedStatuses []int
+	}{
+		{
+			name:  "same ip is limited",
+			rules: []RateLimitRule{{By: RateLimitByIP, Limit: oncePerMinute}},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1:1000", "", nil),
+				newRateLimitRequest("1.1.1.1:2000", "", nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:  "different ips have separate buckets",
+			rules: []RateLimitRule{{By: RateLimitByIP, Limit: oncePerMinute}},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1:1000", "", nil),
+				newRateLimitRequest("2.2.2.2", "", nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:  "author is matched case-insensitively",
+			rules: []RateLimitRule{{By: RateLimitByAuthor, Limit: oncePerMinute}},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1", `{"author":"Alice"}`, nil),
+				newRateLimitRequest("2.2.2.2", `{"author":" alice "}`, nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:  "request without author skips author rule",
+			rules: []RateLimitRule{{By: RateLimitByAuthor, Limit: oncePerMinute}},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1", `{}`, nil),
+				newRateLimitRequest("1.1.1.1", `not json`, nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:  "session rule skips anonymous requests",
+			rules: []RateLimitRule{{By: RateLimitBySession, Limit: oncePerMinute}},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1", "", &userDomain.User{ID: 7}),
+				newRateLimitRequest("2.2.2.2", "", &userDomain.User{ID: 7}),
+				newRateLimitRequest("1.1.1.1", "", nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name: "every rule must pass",
+			rules: []RateLimitRule{
+				{By: RateLimitByIP, Limit: ratelimit.Limit{Requests: 10, Period: time.Minute}},
+				{By: RateLimitByAuthor, Limit: oncePerMinute},
+			},
+			requests: []*http.Request{
+				newRateLimitRequest("1.1.1.1", `{"author":"alice"}`, nil),
+				newRateLimitRequest("1.1.1.1", `{"author":"alice"}`, nil),
+				newRateLimitRequest("1.1.1.1", `{"author":"bob"}`, nil),
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := RateLimit(ratelimit.NewMemoryStore(), "create_plant", tt.rules)(echoBody())
+
+			for i, req := range tt.requests {
+				// Act
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatuses[i], rr.Code, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestRateLimit_Headers(t *testing.T) {
+	// Arrange
+	limit := ratelimit.Limit{Requests: 2, Period: time.Minute, Burst: 1}
+	handler := RateLimit(ratelimit.NewMemoryStore(), "create_plant", []RateLimitRule{{By: RateLimitByIP, Limit: limit}})(echoBody())
+
+	// Act
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, newRateLimitRequest("1.1.1.1", "", nil))
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newRateLimitRequest("1.1.1.1", "", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60;burst=1", first.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, first.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "30", second.Header().Get("Retry-After"))
+	assert.Contains(t, second.Body.String(), "Too many requests")
+}
+
+func TestRateLimit_BodyIsPreserved(t *testing.T) {
+	// Arrange
+	handler := RateLimit(ratelimit.NewMemoryStore(), "create_plant", []RateLimitRule{
+		{By: RateLimitByAuthor, Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}},
+	})(echoBody())
+	body := `{"author":"alice","imageData":"AAAA"}`
+
+	// Act
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRateLimitRequest("1.1.1.1", body, nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, body, rr.Body.String())
+}
+
+func TestRateLimit_StoreErrorFailsOpen(t *testing.T) {
+	// Arrange
+	handler := RateLimit(failingStore{}, "create_plant", []RateLimitRule{
+		{By: RateLimitByIP, Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}},
+	})(echoBody())
+
+	// Act
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRateLimitRequest("1.1.1.1", "", nil))
+
+	// Assert
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+}
+
+// echoBody возвращает тело запроса, чтобы проверить, что middleware его не съел.
+func echoBody() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+}
+
+func newRateLimitRequest(remoteAddr, body string, u *userDomain.User) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/plants", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if u != nil {
+		req = req.WithContext(userDomain.WithUser(req.Context(), *u))
+	}
+	return req
+}
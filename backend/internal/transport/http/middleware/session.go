@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// SessionCookie - имя cookie с токеном сессии.
+const SessionCookie = "forest_session"
+
+// CSRFHeader - заголовок, в котором страница повторяет CSRF-токен своей сессии.
+const CSRFHeader = "X-CSRF-Token"
+
+// SessionAuthenticator находит сессию по токену из cookie.
+type SessionAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (userDomain.Session, userDomain.User, error)
+}
+
+type sessionKey struct{}
+
+// Session загружает сессию из cookie SessionCookie и кладет ее пользователя в контекст (см. userDomain.FromContext).
+// Запрос без cookie или с недействительной сессией обрабатывается как анонимный.
+//
+// Cookie браузер отправляет сам, в том числе со страниц чужих сайтов, поэтому небезопасные методы
+// с действующей сессией принимаются, только если в CSRFHeader повторен CSRF-токен сессии.
+// Запросы без сессии ничего не получают от cookie, и для них проверка не нужна.
+func Session(auth SessionAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(SessionCookie)
+			if err != nil || cookie.Value == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			session, u, err := auth.Authenticate(r.Context(), cookie.Value)
+			if errors.Is(err, userDomain.ErrSessionNotFound) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				cerror.Respond(w, r, err)
+				return
+			}
+
+			if !isSafeMethod(r.Method) && !session.CheckCSRF(r.Header.Get(CSRFHeader)) {
+				cerror.Respond(w, r, cerror.New(cerror.Forbidden, "Invalid or missing CSRF token"))
+				return
+			}
+
+			ctx := userDomain.WithUser(r.Context(), u)
+			ctx = context.WithValue(ctx, sessionKey{}, session)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SessionFromContext возвращает сессию, загруженную Session. Для анонимных запросов ok равно false.
+func SessionFromContext(ctx context.Context) (userDomain.Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(userDomain.Session)
+	return session, ok
+}
+
+// isSafeMethod сообщает, что метод по RFC 9110 не меняет состояние сервера.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
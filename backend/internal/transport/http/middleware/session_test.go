@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAuthenticator знает одну сессию с токеном "token".
+type stubAuthenticator struct {
+	err error
+}
+
+func (s stubAuthenticator) Authenticate(ctx context.Context, token string) (userDomain.Session, userDomain.User, error) {
+	if s.err != nil {
+		return userDomain.Session{}, userDomain.User{}, s.err
+	}
+	if token != "token" {
+		return userDomain.Session{}, userDomain.User{}, userDomain.ErrSessionNotFound
+	}
+	return userDomain.Session{UserID: 7, CSRFToken: "csrf"}, userDomain.User{ID: 7, Username: "alice"}, nil
+}
+
+func TestSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		cookie         string
+		csrf           string
+		authErr        error
+		expectedStatus int
+		expectedUserID int
+	}{
+		{
+			name:           "anonymous request",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "safe method needs no csrf token",
+			method:         http.MethodGet,
+			cookie:         "token",
+			expectedStatus: http.StatusOK,
+			expectedUserID: 7,
+		},
+		{
+			name:           "unsafe method with csrf token",
+			method:         http.MethodPost,
+			cookie:         "token",
+			csrf:           "csrf",
+			expectedStatus: http.StatusOK,
+			expectedUserID: 7,
+		},
+		{
+			name:           "unsafe method without csrf token",
+			method:         http.MethodPost,
+			cookie:         "token",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unsafe method with wrong csrf token",
+			method:         http.MethodDelete,
+			cookie:         "token",
+			csrf:           "guess",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "expired session is anonymous",
+			method:         http.MethodPost,
+			cookie:         "expired",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "session store error",
+			method:         http.MethodGet,
+			cookie:         "token",
+			authErr:        errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var userID int
+			var hasSession bool
+			handler := Session(stubAuthenticator{err: tt.authErr})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				u, _ := userDomain.FromContext(r.Context())
+				userID = u.ID
+				_, hasSession = SessionFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(tt.method, "/v1/plants", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookie, Value: tt.cookie})
+			}
+			if tt.csrf != "" {
+				req.Header.Set(CSRFHeader, tt.csrf)
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedUserID, userID)
+			assert.Equal(t, tt.expectedUserID != 0, hasSession)
+		})
+	}
+}
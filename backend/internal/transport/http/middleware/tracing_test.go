@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+)
+
+func TestTracing(t *testing.T) {
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		traceparent    string
+		expectedName   string
+		expectedStatus int64
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "route pattern in name",
+			method:         http.MethodGet,
+			path:           "/v1/plants/42",
+			expectedName:   "GET /v1/plants/{id}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "continues incoming trace",
+			method:         http.MethodGet,
+			path:           "/v1/plants/42",
+			traceparent:    "00-" + traceID + "-" + parentSpanID + "-01",
+			expectedName:   "GET /v1/plants/{id}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "server error",
+			method:         http.MethodPost,
+			path:           "/v1/plants",
+			expectedName:   "POST /v1/plants",
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   codes.Error,
+		},
+		{
+			name:           "unknown path",
+			method:         http.MethodGet,
+			path:           "/wp-admin/install.php",
+			expectedName:   "GET",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			recorder := testutil.RecordSpans(t)
+
+			var handlerSpan trace.SpanContext
+			router := chi.NewRouter()
+			router.Use(Tracing())
+			router.Route("/v1", func(r chi.Router) {
+				r.Get("/plants/{id}", func(w http.ResponseWriter, r *http.Request) {
+					handlerSpan = trace.SpanContextFromContext(r.Context())
+				})
+				r.Post("/plants", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+
+			// Act
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tt.expectedName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, tt.expectedCode, span.Status().Code)
+			assert.Contains(t, span.Attributes(), attribute.Int64("http.response.status_code", tt.expectedStatus))
+
+			if tt.traceparent != "" {
+				assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+				assert.Equal(t, parentSpanID, span.Parent().SpanID().String())
+				assert.True(t, span.Parent().IsRemote())
+			}
+			if tt.method == http.MethodGet && tt.expectedStatus == http.StatusOK {
+				// Обработчик получает контекст со span запроса
+				assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+			}
+		})
+	}
+}
@@ -1,53 +1,373 @@
 package http
 
 import (
+	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/heartmarshall/digital-forest/backend/internal/health"
+	"github.com/heartmarshall/digital-forest/backend/internal/idempotency"
+	"github.com/heartmarshall/digital-forest/backend/internal/metrics"
+	"github.com/heartmarshall/digital-forest/backend/internal/ratelimit"
+	"github.com/heartmarshall/digital-forest/backend/internal/tracing"
+	"github.com/heartmarshall/digital-forest/backend/internal/transport/http/dto"
+	healthHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/health"
+	challengeHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/challenge"
 	createHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/create"
+	duplicatesHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/duplicates"
+	getByIDHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_by_id"
+	getImageHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_image"
 	getRandomHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_random"
+	listHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/list"
+	moderateHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/moderate"
+	removeHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/remove"
+	reportHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/report"
+	streamHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/stream"
+	updateHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/update"
+	authHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/user/auth"
+	appMiddleware "github.com/heartmarshall/digital-forest/backend/internal/transport/http/middleware"
+	apiKeysUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/admin/api_keys"
+	challengeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/challenge"
 	createUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/create"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+	getByIDUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_by_id"
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
 	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	moderateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/moderate"
+	removeUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/remove"
+	reportUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/report"
+	streamUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/stream"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	oidcLoginUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/oidc_login"
 )
 
+// allowedOrigins - источники фронтенда при локальной разработке (CORS и WebSocket).
+var allowedOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000", "http://localhost:5173"}
+
+// Имена маршрутов, для которых в конфигурации можно задать лимиты запросов.
+const (
+	RouteCreatePlant  = "create_plant"
+	RouteReportPlant  = "report_plant"
+	RouteAuthLogin    = "auth_login"
+	RouteAuthRegister = "auth_register"
+)
+
+// RateLimitedRoutes - все маршруты, которые понимает WithRateLimits.
+var RateLimitedRoutes = []string{RouteCreatePlant, RouteReportPlant, RouteAuthLogin, RouteAuthRegister}
+
+// UseCases - набор use cases, от которых зависят handlers роутера.
+type UseCases struct {
+	Create     *createUseCase.CreateUseCase
+	GetRandom  *getRandomUseCase.GetRandomUseCase
+	GetImage   *getImageUseCase.GetImageUseCase
+	GetByID    *getByIDUseCase.GetByIDUseCase
+	List       *listUseCase.ListUseCase
+	Stream     *streamUseCase.StreamUseCase
+	Moderate   *moderateUseCase.ModerateUseCase
+	Report     *reportUseCase.ReportUseCase
+	Update     *updateUseCase.UpdateUseCase
+	Remove     *removeUseCase.RemoveUseCase
+	Duplicates *duplicatesUseCase.DuplicatesUseCase
+	Auth       *authUseCase.AuthUseCase
+	OIDCLogin  *oidcLoginUseCase.OIDCLoginUseCase
+	APIKeys    *apiKeysUseCase.APIKeysUseCase
+	// Challenge включает proof-of-work для анонимной посадки; nil - проверка выключена.
+	Challenge *challengeUseCase.ChallengeUseCase
+}
+
+// routerOptions - настройки роутера, не связанные с use cases.
+type routerOptions struct {
+	insecureCookie bool
+	afterLoginURL  string
+	limitStore     ratelimit.Store
+	limits         map[string][]appMiddleware.RateLimitRule
+	idempotency    idempotency.Store
+	idempotencyTTL time.Duration
+	metrics        *metrics.Metrics
+	tracing        bool
+	logger         *slog.Logger
+	health         *health.Checker
+}
+
+// RouterOption настраивает роутер.
+type RouterOption func(*routerOptions)
+
+// WithInsecureCookies убирает у cookie сессии атрибут Secure, чтобы вход работал по http при локальной разработке.
+func WithInsecureCookies() RouterOption {
+	return func(o *routerOptions) {
+		o.insecureCookie = true
+	}
+}
+
+// WithAfterLoginURL задает адрес, куда браузер возвращается после входа через провайдера OpenID Connect. По умолчанию "/".
+func WithAfterLoginURL(url string) RouterOption {
+	return func(o *routerOptions) {
+		o.afterLoginURL = url
+	}
+}
+
+// WithRateLimits ограничивает частоту запросов к маршрутам из RateLimitedRoutes.
+// Маршруты без правил не ограничиваются.
+func WithRateLimits(store ratelimit.Store, limits map[string][]appMiddleware.RateLimitRule) RouterOption {
+	return func(o *routerOptions) {
+		o.limitStore = store
+		o.limits = limits
+	}
+}
+
+// WithIdempotency включает заголовок Idempotency-Key для POST /v1/plants: ответы хранятся ttl.
+func WithIdempotency(store idempotency.Store, ttl time.Duration) RouterOption {
+	return func(o *routerOptions) {
+		o.idempotency = store
+		o.idempotencyTTL = ttl
+	}
+}
+
+// WithMetrics включает метрики HTTP-запросов и use cases.
+// Сам роутер метрики не отдает: они публикуются отдельным внутренним сервером (см. metrics.Metrics.Server).
+func WithMetrics(m *metrics.Metrics) RouterOption {
+	return func(o *routerOptions) {
+		o.metrics = m
+	}
+}
+
+// WithTracing включает spans OpenTelemetry для HTTP-запросов и use cases.
+// Родительский span берется из заголовка traceparent запроса; spans отправляет глобальный TracerProvider (см. tracing.Setup).
+func WithTracing() RouterOption {
+	return func(o *routerOptions) {
+		o.tracing = true
+	}
+}
+
+// WithLogger задает логгер, от которого порождаются логгеры запросов. По умолчанию slog.Default().
+func WithLogger(l *slog.Logger) RouterOption {
+	return func(o *routerOptions) {
+		o.logger = l
+	}
+}
+
+// WithHealth включает GET /readyz: проверку готовности сервиса набором проверок c.
+// GET /healthz отвечает всегда.
+func WithHealth(c *health.Checker) RouterOption {
+	return func(o *routerOptions) {
+		o.health = c
+	}
+}
+
+// idempotent возвращает middleware ключей идемпотентности или пустой middleware, если они выключены.
+func (o routerOptions) idempotent(route string) func(http.Handler) http.Handler {
+	if o.idempotency == nil || o.idempotencyTTL <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return appMiddleware.Idempotency(o.idempotency, route, o.idempotencyTTL)
+}
+
+// rateLimit возвращает middleware ограничения для маршрута или пустой middleware, если лимитов нет.
+func (o routerOptions) rateLimit(route string) func(http.Handler) http.Handler {
+	rules := o.limits[route]
+	if o.limitStore == nil || len(rules) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return appMiddleware.RateLimit(o.limitStore, route, rules)
+}
+
 // NewRouter создает новый роутер, регистрирует все маршруты и middleware.
-func NewRouter(createUC *createUseCase.CreateUseCase, getRandomUC *getRandomUseCase.GetRandomUseCase) http.Handler {
+func NewRouter(uc UseCases, opts ...RouterOption) http.Handler {
+	options := routerOptions{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// Создаем экземпляр валидатора
 	validator := NewValidator()
 
+	// Use cases в том виде, в котором их получают handlers: с метриками или трассировкой они обернуты декораторами.
+	var (
+		createUC     createHandler.CreateUseCase         = uc.Create
+		getRandomUC  getRandomHandler.GetRandomUseCase   = uc.GetRandom
+		getImageUC   getImageHandler.GetImageUseCase     = uc.GetImage
+		getByIDUC    getByIDHandler.GetByIDUseCase       = uc.GetByID
+		listUC       listHandler.ListUseCase             = uc.List
+		moderateUC   moderateHandler.ModerateUseCase     = uc.Moderate
+		reportUC     reportHandler.ReportUseCase         = uc.Report
+		updateUC     updateHandler.UpdateUseCase         = uc.Update
+		removeUC     removeHandler.RemoveUseCase         = uc.Remove
+		duplicatesUC duplicatesHandler.DuplicatesUseCase = uc.Duplicates
+		authUC       authHandler.AuthUseCase             = uc.Auth
+	)
+	var obs useCaseObservers
+	if options.tracing {
+		obs = append(obs, tracing.UseCases{})
+	}
+	if options.metrics != nil {
+		obs = append(obs, options.metrics.UseCases)
+	}
+	if len(obs) > 0 {
+		createUC = observedCreate{next: createUC, obs: obs}
+		getRandomUC = observedGetRandom{next: getRandomUC, obs: obs}
+		getImageUC = observedGetImage{next: getImageUC, obs: obs}
+		getByIDUC = observedGetByID{next: getByIDUC, obs: obs}
+		listUC = observedList{next: listUC, obs: obs}
+		moderateUC = observedModerate{next: moderateUC, obs: obs}
+		reportUC = observedReport{next: reportUC, obs: obs}
+		updateUC = observedUpdate{next: updateUC, obs: obs}
+		removeUC = observedRemove{next: removeUC, obs: obs}
+		duplicatesUC = observedDuplicates{next: duplicatesUC, obs: obs}
+		authUC = observedAuth{next: authUC, obs: obs}
+	}
+
 	// Создаем handlers для каждого use case
-	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator)
+	// Typed nil не должен попасть в интерфейс: хендлер отличает выключенную проверку по nil.
+	var challenges createHandler.ChallengeVerifier
+	if uc.Challenge != nil {
+		challenges = uc.Challenge
+	}
+	createHandlerInstance := createHandler.NewCreateHandler(createUC, validator, challenges)
+	challengeHandlerInstance := challengeHandler.NewChallengeHandler(uc.Challenge)
 	getRandomHandlerInstance := getRandomHandler.NewGetRandomHandler(getRandomUC)
+	getImageHandlerInstance := getImageHandler.NewGetImageHandler(getImageUC)
+	getByIDHandlerInstance := getByIDHandler.NewGetByIDHandler(getByIDUC)
+	listHandlerInstance := listHandler.NewListHandler(listUC)
+	streamHandlerInstance := streamHandler.NewStreamHandler(uc.Stream, streamHandler.DefaultHeartbeat, originHosts(allowedOrigins))
+	moderateHandlerInstance := moderateHandler.NewModerateHandler(moderateUC, validator)
+	moderationListHandlerInstance := listHandler.NewModerationListHandler(listUC)
+	reportHandlerInstance := reportHandler.NewReportHandler(reportUC, validator)
+	updateHandlerInstance := updateHandler.NewUpdateHandler(updateUC, validator)
+	removeHandlerInstance := removeHandler.NewRemoveHandler(removeUC)
+	duplicatesHandlerInstance := duplicatesHandler.NewDuplicatesHandler(duplicatesUC)
+	authHandlerInstance := authHandler.NewAuthHandler(authUC, validator, !options.insecureCookie)
+	oidcHandlerInstance := authHandler.NewOIDCHandler(uc.OIDCLogin, uc.Auth, options.afterLoginURL, !options.insecureCookie)
+	// Typed nil не должен попасть в интерфейс: без проверок регистрируется только /healthz.
+	var readiness healthHandler.ReadinessChecker
+	if options.health != nil {
+		readiness = options.health
+	}
+	healthHandlerInstance := healthHandler.NewHealthHandler(readiness)
 
 	router := chi.NewRouter()
 
 	// Настройка Middleware
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
-	router.Use(middleware.Logger)
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(60 * time.Second))
+	if options.tracing {
+		router.Use(appMiddleware.Tracing())
+	}
+	// Метрики снаружи Recoverer, чтобы запросы, завершившиеся паникой, тоже попадали в них с ответом 500.
+	if options.metrics != nil {
+		router.Use(appMiddleware.Metrics(options.metrics.HTTP))
+	}
+	// Логгер запроса получает trace_id, поэтому подключается после Tracing.
+	router.Use(appMiddleware.Logger(options.logger))
+	router.Use(appMiddleware.Recoverer)
 
 	// Настройка CORS для локальной разработки
 	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"http://localhost:3000", "http://127.0.0.1:3000", "http://localhost:5173"},
+		AllowedOrigins: allowedOrigins,
 
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{
+			"Accept", "Authorization", "Content-Type", appMiddleware.CSRFHeader, "If-None-Match",
+			dto.OwnerTokenHeader, appMiddleware.IdempotencyKeyHeader,
+			"traceparent", "tracestate",
+		},
+		ExposedHeaders: []string{
+			"Link", "ETag", "Retry-After", appMiddleware.IdempotentReplayedHeader,
+			"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+		},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	// Пробы оркестратора: живость процесса и готовность принимать запросы.
+	router.Get("/healthz", healthHandlerInstance.Liveness)
+	if options.health != nil {
+		router.Get("/readyz", healthHandlerInstance.Readiness)
+	}
+
 	// Группа роутов для нашего API v1
 	router.Route("/v1", func(r chi.Router) {
-		r.Post("/plants", createHandlerInstance.CreatePlant)
-		r.Get("/plants/random", getRandomHandlerInstance.GetRandomPlants)
+		// Сессия из cookie доступна всем маршрутам: при ней растения записываются на пользователя.
+		if uc.Auth != nil {
+			r.Use(appMiddleware.Session(uc.Auth))
+		}
+
+		// Поток живет, пока клиент подключен, поэтому общий таймаут запроса к нему не применяется.
+		r.With(appMiddleware.LogRoute).Get("/plants/stream", streamHandlerInstance.StreamPlants)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(60 * time.Second))
+			// Маршрут группы уже выбран, поэтому логгер запроса получает route и plant_id.
+			r.Use(appMiddleware.LogRoute)
+
+			r.Get("/plants", listHandlerInstance.ListPlants)
+			if uc.Challenge != nil {
+				r.Get("/challenges", challengeHandlerInstance.IssueChallenge)
+			}
+			// Повтор с тем же Idempotency-Key отвечает сохраненным ответом и не расходует лимит.
+			r.With(options.idempotent(RouteCreatePlant), options.rateLimit(RouteCreatePlant)).
+				Post("/plants", createHandlerInstance.CreatePlant)
+			r.Get("/plants/random", getRandomHandlerInstance.GetRandomPlants)
+			r.Get("/plants/{id}", getByIDHandlerInstance.GetPlant)
+			r.Patch("/plants/{id}", updateHandlerInstance.UpdatePlant)
+			r.Delete("/plants/{id}", removeHandlerInstance.DeletePlant)
+			r.Get("/plants/{id}/image.png", getImageHandlerInstance.GetPlantImage)
+			r.With(options.rateLimit(RouteReportPlant)).Post("/plants/{id}/reports", reportHandlerInstance.CreateReport)
+
+			r.With(options.rateLimit(RouteAuthRegister)).Post("/auth/register", authHandlerInstance.Register)
+			r.With(options.rateLimit(RouteAuthLogin)).Post("/auth/login", authHandlerInstance.Login)
+			r.Post("/auth/logout", authHandlerInstance.Logout)
+			r.Get("/auth/session", authHandlerInstance.GetSession)
+			r.Get("/auth/oidc", oidcHandlerInstance.ListProviders)
+			r.Get("/auth/oidc/{provider}/login", oidcHandlerInstance.StartLogin)
+			r.Post("/auth/oidc/{provider}/link", oidcHandlerInstance.StartLink)
+			r.Get("/auth/oidc/{provider}/callback", oidcHandlerInstance.Callback)
+
+			// Модерация: каждое действие записывается в moderation_log с именем модератора.
+			r.Route("/admin", func(r chi.Router) {
+				// Без use case ключей административный API выключен (typed nil не должен попасть в интерфейс).
+				var keys appMiddleware.APIKeyAuthenticator
+				if uc.APIKeys != nil {
+					keys = uc.APIKeys
+				}
+				r.Use(appMiddleware.APIKeyAuth(keys))
+				r.Use(appMiddleware.RequireScope(apikey.ScopePlantsModerate))
+
+				// Подроутер выбирает маршрут после своих middleware: route и plant_id добавляются во вложенной группе.
+				r.Group(func(r chi.Router) {
+					r.Use(appMiddleware.LogRoute)
+
+					r.Get("/plants", moderationListHandlerInstance.ListPlants)
+					r.Post("/plants/{id}/hide", moderateHandlerInstance.HidePlant)
+					r.Post("/plants/{id}/restore", moderateHandlerInstance.RestorePlant)
+					r.Delete("/plants/{id}", moderateHandlerInstance.DeletePlant)
+					r.Post("/plants/{id}/purge", moderateHandlerInstance.PurgePlant)
+					r.Get("/plants/{id}/moderation-log", moderateHandlerInstance.GetModerationLog)
+					r.Get("/reports", reportHandlerInstance.GetQueue)
+					r.Post("/plants/{id}/reports/resolve", reportHandlerInstance.ResolveReports)
+					r.Get("/duplicates", duplicatesHandlerInstance.ListClusters)
+				})
+			})
+		})
 	})
 
 	return router
 }
+
+// originHosts превращает источники вида http://host:port в шаблоны хостов для websocket.AcceptOptions.
+func originHosts(origins []string) []string {
+	hosts := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Host)
+		}
+	}
+	return hosts
+}
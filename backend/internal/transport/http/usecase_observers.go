@@ -0,0 +1,223 @@
+package http
+
+import (
+	"context"
+
+	plantDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	createHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/create"
+	duplicatesHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/duplicates"
+	getByIDHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_by_id"
+	getImageHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_image"
+	getRandomHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/get_random"
+	listHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/list"
+	moderateHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/moderate"
+	removeHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/remove"
+	reportHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/report"
+	updateHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/plant/update"
+	authHandler "github.com/heartmarshall/digital-forest/backend/internal/transport/http/handlers/user/auth"
+	getImageUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_image"
+	getRandomUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/get_random"
+	listUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/list"
+	updateUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/update"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+)
+
+// Декораторы use cases для метрик и трассировки: каждый вызов передается UseCaseObserver под своим именем,
+// а сами use cases о них ничего не знают.
+
+// UseCaseObserver наблюдает за вызовами use cases (см. metrics.UseCases и tracing.UseCases).
+// Observe вызывается перед use case и может заменить его контекст, например добавив span;
+// возвращенная функция вызывается с ошибкой use case.
+type UseCaseObserver interface {
+	Observe(ctx context.Context, name string) (context.Context, func(err error))
+}
+
+// useCaseObservers передает каждый вызов всем наблюдателям по порядку.
+type useCaseObservers []UseCaseObserver
+
+func (obs useCaseObservers) Observe(ctx context.Context, name string) (context.Context, func(err error)) {
+	dones := make([]func(error), len(obs))
+	for i, o := range obs {
+		ctx, dones[i] = o.Observe(ctx, name)
+	}
+	return ctx, func(err error) {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+}
+
+type observedCreate struct {
+	next createHandler.CreateUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedCreate) Create(ctx context.Context, author, imageData string) (plantDomain.Plant, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.create")
+	plant, err := u.next.Create(ctx, author, imageData)
+	done(err)
+	return plant, err
+}
+
+func (u observedCreate) CreateFromGrid(ctx context.Context, author string, grid plantDomain.Grid) (plantDomain.Plant, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.create_from_grid")
+	plant, err := u.next.CreateFromGrid(ctx, author, grid)
+	done(err)
+	return plant, err
+}
+
+type observedGetRandom struct {
+	next getRandomHandler.GetRandomUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedGetRandom) GetRandom(ctx context.Context, filter plantDomain.RandomFilter, cursor string) (getRandomUseCase.Page, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.get_random")
+	page, err := u.next.GetRandom(ctx, filter, cursor)
+	done(err)
+	return page, err
+}
+
+type observedGetImage struct {
+	next getImageHandler.GetImageUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedGetImage) GetImage(ctx context.Context, id, scale int) (getImageUseCase.Image, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.get_image")
+	image, err := u.next.GetImage(ctx, id, scale)
+	done(err)
+	return image, err
+}
+
+type observedGetByID struct {
+	next getByIDHandler.GetByIDUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedGetByID) GetByID(ctx context.Context, id int) (plantDomain.Plant, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.get_by_id")
+	plant, err := u.next.GetByID(ctx, id)
+	done(err)
+	return plant, err
+}
+
+type observedList struct {
+	next listHandler.ListUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedList) List(ctx context.Context, filter plantDomain.ListFilter, cursor string) (listUseCase.Page, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.list")
+	page, err := u.next.List(ctx, filter, cursor)
+	done(err)
+	return page, err
+}
+
+type observedModerate struct {
+	next moderateHandler.ModerateUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedModerate) Moderate(ctx context.Context, id int, action plantDomain.ModerationAction, actor, reason string) (plantDomain.Plant, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.moderate")
+	plant, err := u.next.Moderate(ctx, id, action, actor, reason)
+	done(err)
+	return plant, err
+}
+
+func (u observedModerate) Log(ctx context.Context, id int) ([]plantDomain.ModerationEvent, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.moderation_log")
+	events, err := u.next.Log(ctx, id)
+	done(err)
+	return events, err
+}
+
+type observedReport struct {
+	next reportHandler.ReportUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedReport) Report(ctx context.Context, id int, reason plantDomain.ReportReason, reporter string) error {
+	ctx, done := u.obs.Observe(ctx, "plant.report")
+	err := u.next.Report(ctx, id, reason, reporter)
+	done(err)
+	return err
+}
+
+func (u observedReport) Queue(ctx context.Context, limit int) ([]plantDomain.ReportSummary, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.report_queue")
+	queue, err := u.next.Queue(ctx, limit)
+	done(err)
+	return queue, err
+}
+
+func (u observedReport) Resolve(ctx context.Context, id int) (int, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.resolve_reports")
+	resolved, err := u.next.Resolve(ctx, id)
+	done(err)
+	return resolved, err
+}
+
+type observedUpdate struct {
+	next updateHandler.UpdateUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedUpdate) Update(ctx context.Context, id int, token string, req updateUseCase.Request) (plantDomain.Plant, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.update")
+	plant, err := u.next.Update(ctx, id, token, req)
+	done(err)
+	return plant, err
+}
+
+type observedRemove struct {
+	next removeHandler.RemoveUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedRemove) Remove(ctx context.Context, id int, token string) error {
+	ctx, done := u.obs.Observe(ctx, "plant.remove")
+	err := u.next.Remove(ctx, id, token)
+	done(err)
+	return err
+}
+
+type observedDuplicates struct {
+	next duplicatesHandler.DuplicatesUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedDuplicates) Clusters(ctx context.Context, maxDistance, limit int) ([]plantDomain.DuplicateCluster, error) {
+	ctx, done := u.obs.Observe(ctx, "plant.duplicate_clusters")
+	clusters, err := u.next.Clusters(ctx, maxDistance, limit)
+	done(err)
+	return clusters, err
+}
+
+type observedAuth struct {
+	next authHandler.AuthUseCase
+	obs  UseCaseObserver
+}
+
+func (u observedAuth) Register(ctx context.Context, username, password string) (userDomain.User, error) {
+	ctx, done := u.obs.Observe(ctx, "user.register")
+	user, err := u.next.Register(ctx, username, password)
+	done(err)
+	return user, err
+}
+
+func (u observedAuth) Login(ctx context.Context, username, password string) (authUseCase.Login, error) {
+	ctx, done := u.obs.Observe(ctx, "user.login")
+	login, err := u.next.Login(ctx, username, password)
+	done(err)
+	return login, err
+}
+
+func (u observedAuth) Logout(ctx context.Context, token string) error {
+	ctx, done := u.obs.Observe(ctx, "user.logout")
+	err := u.next.Logout(ctx, token)
+	done(err)
+	return err
+}
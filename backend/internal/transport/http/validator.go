@@ -41,8 +41,14 @@ func (v *Validator) ValidateStruct(s interface{}) map[string]string {
 		switch fieldErr.Tag() {
 		case "required":
 			errorMessages[fieldName] = fmt.Sprintf("field '%s' is required", fieldName)
+		case "min":
+			errorMessages[fieldName] = fmt.Sprintf("field '%s' is too short (min: %s)", fieldName, fieldErr.Param())
 		case "max":
 			errorMessages[fieldName] = fmt.Sprintf("field '%s' is too long (max: %s)", fieldName, fieldErr.Param())
+		case "required_without":
+			errorMessages[fieldName] = fmt.Sprintf("field '%s' is required when '%s' is not set", fieldName, strings.ToLower(fieldErr.Param()))
+		case "excluded_with":
+			errorMessages[fieldName] = fmt.Sprintf("field '%s' cannot be used together with '%s'", fieldName, strings.ToLower(fieldErr.Param()))
 		default:
 			errorMessages[fieldName] = fmt.Sprintf("field '%s' is not valid", fieldName)
 		}
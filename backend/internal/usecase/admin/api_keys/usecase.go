@@ -0,0 +1,65 @@
+package api_keys
+
+import (
+	"context"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+)
+
+// APIKeyRepository определяет контракт для слоя данных.
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, k apikey.APIKey) (apikey.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]apikey.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, at time.Time) error
+	UseAPIKey(ctx context.Context, hash string) (apikey.APIKey, error)
+}
+
+// APIKeysUseCase - это конкретная реализация управления ключами административного API и их проверки.
+type APIKeysUseCase struct {
+	repo APIKeyRepository
+}
+
+// NewAPIKeysUseCase - конструктор для APIKeysUseCase.
+func NewAPIKeysUseCase(r APIKeyRepository) *APIKeysUseCase {
+	return &APIKeysUseCase{repo: r}
+}
+
+// Create - сценарий использования для выпуска ключа. Токен возвращается только здесь:
+// в базе остается лишь его хэш, и восстановить токен потом нельзя.
+func (uc *APIKeysUseCase) Create(ctx context.Context, name string, scopes []string) (apikey.APIKey, string, error) {
+	parsed, err := apikey.ParseScopes(scopes)
+	if err != nil {
+		return apikey.APIKey{}, "", err
+	}
+
+	k, token, err := apikey.New(name, parsed, time.Now().UTC())
+	if err != nil {
+		return apikey.APIKey{}, "", err
+	}
+
+	created, err := uc.repo.CreateAPIKey(ctx, k)
+	if err != nil {
+		return apikey.APIKey{}, "", err
+	}
+	return created, token, nil
+}
+
+// List - сценарий использования для просмотра всех ключей, включая отозванные.
+func (uc *APIKeysUseCase) List(ctx context.Context) ([]apikey.APIKey, error) {
+	return uc.repo.ListAPIKeys(ctx)
+}
+
+// Revoke - сценарий использования для отзыва ключа. Отозванный ключ перестает действовать сразу.
+func (uc *APIKeysUseCase) Revoke(ctx context.Context, id int) error {
+	return uc.repo.RevokeAPIKey(ctx, id, time.Now().UTC())
+}
+
+// Authenticate - сценарий использования для проверки токена из заголовка Authorization.
+// Если токен не принадлежит действующему ключу, возвращается apikey.ErrNotFound.
+func (uc *APIKeysUseCase) Authenticate(ctx context.Context, token string) (apikey.APIKey, error) {
+	if token == "" {
+		return apikey.APIKey{}, apikey.ErrNotFound
+	}
+	return uc.repo.UseAPIKey(ctx, apikey.HashToken(token))
+}
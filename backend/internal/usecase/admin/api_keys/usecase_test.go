@@ -0,0 +1,161 @@
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/domain/apikey"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKeysUseCase(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockAPIKeyRepository()
+
+	// Act
+	useCase := NewAPIKeysUseCase(mockRepo)
+
+	// Assert
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
+
+func TestAPIKeysUseCase_Create(t *testing.T) {
+	repoErr := errors.New("db down")
+
+	tests := []struct {
+		name          string
+		keyName       string
+		scopes        []string
+		mockSetup     func(*testutil.MockAPIKeyRepository)
+		expectedError error
+	}{
+		{
+			name:    "successful creation",
+			keyName: "ci",
+			scopes:  []string{"stats:read", "plants:moderate"},
+			mockSetup: func(mockRepo *testutil.MockAPIKeyRepository) {
+				mockRepo.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(k apikey.APIKey) bool {
+					return k.Name == "ci" && k.Hash != "" &&
+						assert.ObjectsAreEqual([]apikey.Scope{apikey.ScopePlantsModerate, apikey.ScopeStatsRead}, k.Scopes)
+				})).Return(apikey.APIKey{ID: 1, Name: "ci"}, nil)
+			},
+		},
+		{
+			name:          "unknown scope",
+			keyName:       "ci",
+			scopes:        []string{"plants:delete"},
+			mockSetup:     func(mockRepo *testutil.MockAPIKeyRepository) {},
+			expectedError: apikey.ErrInvalidScope,
+		},
+		{
+			name:          "empty name",
+			keyName:       "",
+			scopes:        []string{"stats:read"},
+			mockSetup:     func(mockRepo *testutil.MockAPIKeyRepository) {},
+			expectedError: apikey.ErrInvalidName,
+		},
+		{
+			name:    "repository error",
+			keyName: "ci",
+			scopes:  []string{"stats:read"},
+			mockSetup: func(mockRepo *testutil.MockAPIKeyRepository) {
+				mockRepo.On("CreateAPIKey", mock.Anything, mock.Anything).Return(apikey.APIKey{}, repoErr)
+			},
+			expectedError: repoErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockAPIKeyRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewAPIKeysUseCase(mockRepo)
+
+			// Act
+			key, token, err := useCase.Create(context.Background(), tt.keyName, tt.scopes)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, token)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 1, key.ID)
+				assert.Contains(t, token, apikey.TokenPrefix)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeysUseCase_Revoke(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockAPIKeyRepository()
+	mockRepo.On("RevokeAPIKey", mock.Anything, 3, mock.Anything).Return(apikey.ErrNotFound)
+	useCase := NewAPIKeysUseCase(mockRepo)
+
+	// Act
+	err := useCase.Revoke(context.Background(), 3)
+
+	// Assert
+	assert.ErrorIs(t, err, apikey.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeysUseCase_Authenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		mockSetup     func(*testutil.MockAPIKeyRepository)
+		expectedError error
+	}{
+		{
+			name:  "valid token",
+			token: "dfk_abc_secret",
+			mockSetup: func(mockRepo *testutil.MockAPIKeyRepository) {
+				mockRepo.On("UseAPIKey", mock.Anything, apikey.HashToken("dfk_abc_secret")).Return(apikey.APIKey{ID: 1}, nil)
+			},
+		},
+		{
+			name:  "unknown token",
+			token: "dfk_abc_guess",
+			mockSetup: func(mockRepo *testutil.MockAPIKeyRepository) {
+				mockRepo.On("UseAPIKey", mock.Anything, apikey.HashToken("dfk_abc_guess")).Return(apikey.APIKey{}, apikey.ErrNotFound)
+			},
+			expectedError: apikey.ErrNotFound,
+		},
+		{
+			name:          "empty token",
+			token:         "",
+			mockSetup:     func(mockRepo *testutil.MockAPIKeyRepository) {},
+			expectedError: apikey.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockAPIKeyRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewAPIKeysUseCase(mockRepo)
+
+			// Act
+			key, err := useCase.Authenticate(context.Background(), tt.token)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 1, key.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
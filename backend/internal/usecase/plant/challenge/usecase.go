@@ -0,0 +1,186 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+var (
+	// ErrRequired - запрос не содержит задачи или решения.
+	ErrRequired = cerror.New(cerror.Invalid, "proof-of-work challenge and solution are required, see GET /v1/challenges")
+	// ErrInvalid - задачу выдал не этот сервер или ее изменили.
+	ErrInvalid = cerror.New(cerror.Forbidden, "invalid challenge")
+	// ErrExpired - срок задачи истек, нужно получить новую.
+	ErrExpired = cerror.New(cerror.Forbidden, "challenge expired")
+	// ErrUnsolved - решение не подходит к задаче.
+	ErrUnsolved = cerror.New(cerror.Forbidden, "challenge solution is incorrect")
+	// ErrReplayed - решение этой задачи уже использовано.
+	ErrReplayed = cerror.New(cerror.Forbidden, "challenge already used")
+)
+
+const (
+	// DefaultBaseDifficulty - сложность при обычной нагрузке, в ведущих нулевых битах.
+	// 2^18 хэшей - доли секунды в браузере и заметные затраты при массовой рассылке.
+	DefaultBaseDifficulty = 18
+	// DefaultMaxDifficulty - предел сложности, чтобы посадка оставалась возможной и во время атаки.
+	DefaultMaxDifficulty = 24
+	// DefaultTTL - сколько действует выданная задача.
+	DefaultTTL = 5 * time.Minute
+	// DefaultWindow - за какой период считаются недавно посаженные растения.
+	DefaultWindow = 10 * time.Minute
+	// DefaultStep - сколько растений за окно поднимает сложность на бит; каждое удвоение добавляет еще бит.
+	DefaultStep = 50
+
+	// difficultyRefresh - как часто пересчитывается сложность: подсчет растений не должен идти на каждую задачу.
+	difficultyRefresh = 10 * time.Second
+)
+
+// VolumeCounter считает растения, посаженные начиная с момента since.
+type VolumeCounter interface {
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+}
+
+// Challenge - выданная задача proof-of-work.
+type Challenge struct {
+	Token      string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+// ChallengeUseCase выдает и проверяет задачи proof-of-work для анонимной посадки.
+// Задачи подписаны HMAC и проверяются без обращения к базе; хранится только список
+// уже использованных задач, чтобы одно решение нельзя было предъявить дважды.
+type ChallengeUseCase struct {
+	signer hashcash.Signer
+	spent  hashcash.SpentStore
+	volume VolumeCounter
+
+	base, max    int
+	ttl, window  time.Duration
+	step         int
+	now          func() time.Time
+	mu           sync.Mutex
+	difficulty   int
+	difficultyAt time.Time
+}
+
+// Option настраивает ChallengeUseCase.
+type Option func(*ChallengeUseCase)
+
+// WithDifficulty задает сложность при обычной нагрузке и ее предел.
+func WithDifficulty(base, max int) Option {
+	return func(uc *ChallengeUseCase) {
+		uc.base, uc.max = base, max
+	}
+}
+
+// WithScaling задает окно подсчета недавних растений и число растений за окно, с которого сложность растет.
+// step меньше 1 отключает рост сложности.
+func WithScaling(window time.Duration, step int) Option {
+	return func(uc *ChallengeUseCase) {
+		uc.window, uc.step = window, step
+	}
+}
+
+// WithTTL задает срок действия задачи.
+func WithTTL(ttl time.Duration) Option {
+	return func(uc *ChallengeUseCase) {
+		uc.ttl = ttl
+	}
+}
+
+// NewChallengeUseCase - конструктор для ChallengeUseCase. У всех реплик должен быть один secret.
+func NewChallengeUseCase(secret []byte, spent hashcash.SpentStore, volume VolumeCounter, opts ...Option) *ChallengeUseCase {
+	uc := &ChallengeUseCase{
+		signer: hashcash.NewSigner(secret),
+		spent:  spent,
+		volume: volume,
+		base:   DefaultBaseDifficulty,
+		max:    DefaultMaxDifficulty,
+		ttl:    DefaultTTL,
+		window: DefaultWindow,
+		step:   DefaultStep,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Issue - сценарий использования для выдачи новой задачи.
+func (uc *ChallengeUseCase) Issue(ctx context.Context) (Challenge, error) {
+	difficulty, err := uc.currentDifficulty(ctx)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	puzzle, token := uc.signer.Issue(difficulty, uc.now().Add(uc.ttl))
+	return Challenge{Token: token, Difficulty: puzzle.Difficulty, ExpiresAt: puzzle.ExpiresAt}, nil
+}
+
+// Verify - сценарий использования для проверки решения. Сложность берется из подписанной задачи,
+// поэтому рост сложности не отменяет уже выданные задачи.
+// Принятое решение сразу отмечается использованным.
+func (uc *ChallengeUseCase) Verify(ctx context.Context, token, solution string) error {
+	if token == "" || solution == "" {
+		return ErrRequired
+	}
+
+	puzzle, err := uc.signer.Parse(token)
+	if err != nil {
+		return ErrInvalid
+	}
+	if !puzzle.ExpiresAt.After(uc.now()) {
+		return ErrExpired
+	}
+	if !hashcash.Check(token, solution, puzzle.Difficulty) {
+		return ErrUnsolved
+	}
+
+	if err := uc.spent.Spend(ctx, puzzle.ID, puzzle.ExpiresAt); err != nil {
+		if errors.Is(err, hashcash.ErrReplayed) {
+			return ErrReplayed
+		}
+		return fmt.Errorf("ChallengeUseCase - Verify - Spend: %w", err)
+	}
+	return nil
+}
+
+// currentDifficulty возвращает сложность по числу растений за последнее окно, пересчитывая ее не чаще difficultyRefresh.
+func (uc *ChallengeUseCase) currentDifficulty(ctx context.Context) (int, error) {
+	if uc.step < 1 {
+		return uc.base, nil
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+	if !uc.difficultyAt.IsZero() && now.Sub(uc.difficultyAt) < difficultyRefresh {
+		return uc.difficulty, nil
+	}
+
+	volume, err := uc.volume.CountCreatedSince(ctx, now.Add(-uc.window))
+	if err != nil {
+		return 0, fmt.Errorf("ChallengeUseCase - currentDifficulty - CountCreatedSince: %w", err)
+	}
+	uc.difficulty, uc.difficultyAt = uc.scale(volume), now
+	return uc.difficulty, nil
+}
+
+// scale добавляет к базовой сложности бит, когда растений за окно становится step,
+// и еще по биту на каждое удвоение: работа спамера растет вместе с его объемом.
+func (uc *ChallengeUseCase) scale(volume int) int {
+	difficulty := uc.base
+	for v := volume; v >= uc.step && difficulty < uc.max; v /= 2 {
+		difficulty++
+	}
+	return difficulty
+}
@@ -0,0 +1,172 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/hashcash"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenSpentStore - хранилище использованных задач, которое всегда недоступно.
+type brokenSpentStore struct{}
+
+func (brokenSpentStore) Spend(ctx context.Context, id string, expiresAt time.Time) error {
+	return errors.New("db is down")
+}
+
+func TestNewChallengeUseCase(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+
+	// Act
+	uc := NewChallengeUseCase([]byte("secret"), hashcash.NewMemorySpentStore(), mockRepo)
+
+	// Assert
+	assert.NotNil(t, uc)
+	assert.Equal(t, DefaultBaseDifficulty, uc.base)
+	assert.Equal(t, DefaultMaxDifficulty, uc.max)
+	assert.Equal(t, DefaultTTL, uc.ttl)
+}
+
+func TestChallengeUseCase_Issue(t *testing.T) {
+	tests := []struct {
+		name               string
+		volume             int
+		volumeErr          error
+		expectedDifficulty int
+		expectedError      bool
+	}{
+		{name: "quiet forest", volume: 49, expectedDifficulty: 10},
+		{name: "step reached", volume: 50, expectedDifficulty: 11},
+		{name: "volume doubled", volume: 100, expectedDifficulty: 12},
+		{name: "capped at max", volume: 100000, expectedDifficulty: 14},
+		{name: "count error", volumeErr: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+			mockRepo := testutil.NewMockPlantRepository()
+			mockRepo.On("CountCreatedSince", mock.Anything, now.Add(-10*time.Minute)).Return(tt.volume, tt.volumeErr).Once()
+			uc := NewChallengeUseCase([]byte("secret"), hashcash.NewMemorySpentStore(), mockRepo,
+				WithDifficulty(10, 14), WithScaling(10*time.Minute, 50), WithTTL(time.Minute))
+			uc.now = func() time.Time { return now }
+
+			// Act
+			challenge, err := uc.Issue(context.Background())
+
+			// Assert
+			mockRepo.AssertExpectations(t)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDifficulty, challenge.Difficulty)
+			assert.Equal(t, now.Add(time.Minute), challenge.ExpiresAt)
+			assert.NotEmpty(t, challenge.Token)
+		})
+	}
+}
+
+func TestChallengeUseCase_Issue_CachesDifficulty(t *testing.T) {
+	// Arrange
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockPlantRepository()
+	mockRepo.On("CountCreatedSince", mock.Anything, mock.Anything).Return(0, nil).Twice()
+	uc := NewChallengeUseCase([]byte("secret"), hashcash.NewMemorySpentStore(), mockRepo)
+	uc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Act: второй запрос в пределах difficultyRefresh не считает растения заново
+	for range 2 {
+		_, err := uc.Issue(ctx)
+		require.NoError(t, err)
+	}
+	now = now.Add(difficultyRefresh)
+	_, err := uc.Issue(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	mockRepo.AssertExpectations(t)
+}
+
+func TestChallengeUseCase_Verify(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	signer := hashcash.NewSigner([]byte("secret"))
+	_, token := signer.Issue(8, now.Add(time.Minute))
+	solution := hashcash.Solve(token, 8)
+	_, expired := signer.Issue(8, now)
+	_, foreign := hashcash.NewSigner([]byte("other")).Issue(8, now.Add(time.Minute))
+
+	// wrong - решение, которое не подходит к задаче.
+	wrong := "x"
+	for hashcash.Check(token, wrong, 8) {
+		wrong += "x"
+	}
+
+	tests := []struct {
+		name          string
+		spent         hashcash.SpentStore
+		token         string
+		solution      string
+		expectedError error
+	}{
+		{name: "valid solution", token: token, solution: solution},
+		{name: "missing solution", token: token, expectedError: ErrRequired},
+		{name: "missing challenge", solution: solution, expectedError: ErrRequired},
+		{name: "foreign challenge", token: foreign, solution: hashcash.Solve(foreign, 8), expectedError: ErrInvalid},
+		{name: "expired challenge", token: expired, solution: hashcash.Solve(expired, 8), expectedError: ErrExpired},
+		{name: "wrong solution", token: token, solution: wrong, expectedError: ErrUnsolved},
+		{name: "store error", spent: brokenSpentStore{}, token: token, solution: solution},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			spent := tt.spent
+			if spent == nil {
+				spent = hashcash.NewMemorySpentStore()
+			}
+			uc := NewChallengeUseCase([]byte("secret"), spent, testutil.NewMockPlantRepository())
+			uc.now = func() time.Time { return now }
+
+			// Act
+			err := uc.Verify(context.Background(), tt.token, tt.solution)
+
+			// Assert
+			switch {
+			case tt.spent != nil:
+				assert.Error(t, err)
+			case tt.expectedError != nil:
+				assert.ErrorIs(t, err, tt.expectedError)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChallengeUseCase_Verify_RejectsReplay(t *testing.T) {
+	// Arrange
+	uc := NewChallengeUseCase([]byte("secret"), hashcash.NewMemorySpentStore(), testutil.NewMockPlantRepository(),
+		WithScaling(time.Minute, 0), WithDifficulty(8, 8))
+	challenge, err := uc.Issue(context.Background())
+	require.NoError(t, err)
+	solution := hashcash.Solve(challenge.Token, challenge.Difficulty)
+
+	// Act
+	first := uc.Verify(context.Background(), challenge.Token, solution)
+	second := uc.Verify(context.Background(), challenge.Token, solution)
+
+	// Assert
+	assert.NoError(t, first)
+	assert.ErrorIs(t, second, ErrReplayed)
+}
@@ -2,41 +2,164 @@ package create
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
 )
 
 // PlantRepository определяет контракт для слоя данных.
 type PlantRepository interface {
 	Create(ctx context.Context, plant domain.Plant) (domain.Plant, error)
+	FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error)
+}
+
+// Publisher получает каждое растение сразу после того, как оно сохранено.
+// Publish не должен блокироваться: он вызывается в обработке запроса на создание.
+type Publisher interface {
+	Publish(plant domain.Plant)
+}
+
+// AuthorChecker проверяет нормализованное имя автора; запрещенное имя - domain.ErrAuthorBlocked.
+type AuthorChecker interface {
+	CheckAuthor(name string) error
 }
 
 // CreateUseCase - это конкретная реализация бизнес-логики для создания растения.
 type CreateUseCase struct {
-	repo PlantRepository
+	repo      PlantRepository
+	limits    domain.ImageLimits
+	publisher Publisher
+	authors   AuthorChecker
+	// duplicates - что делать с растением, похожим на уже посаженное.
+	duplicates duplicatesUseCase.Policy
+}
+
+// Option настраивает CreateUseCase.
+type Option func(*CreateUseCase)
+
+// WithPublisher задает получателя событий о новых растениях (например, events.PlantBus).
+func WithPublisher(p Publisher) Option {
+	return func(uc *CreateUseCase) {
+		uc.publisher = p
+	}
+}
+
+// WithAuthorChecker задает проверку имен авторов (например, moderation.Blocklist).
+func WithAuthorChecker(c AuthorChecker) Option {
+	return func(uc *CreateUseCase) {
+		uc.authors = c
+	}
+}
+
+// WithDuplicateCheck включает поиск дубликатов: новое растение сравнивается с посаженными,
+// и если найдено совпадающее или отличающееся не больше чем на maxDistance бит, действует режим mode.
+// Отрицательный maxDistance оставляет только точные совпадения.
+func WithDuplicateCheck(mode domain.DuplicateMode, maxDistance int) Option {
+	return func(uc *CreateUseCase) {
+		uc.duplicates = duplicatesUseCase.Policy{Mode: mode, MaxDistance: maxDistance}
+	}
 }
 
 // NewCreateUseCase - конструктор для CreateUseCase.
-func NewCreateUseCase(r PlantRepository) *CreateUseCase {
-	return &CreateUseCase{repo: r}
+// По умолчанию дубликаты не ищутся, но отпечаток изображения сохраняется всегда.
+func NewCreateUseCase(r PlantRepository, opts ...Option) *CreateUseCase {
+	uc := &CreateUseCase{repo: r, limits: domain.DefaultImageLimits, duplicates: duplicatesUseCase.Policy{Mode: domain.DuplicateOff}}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
-// Create - сценарий использования для создания нового растения.
+// Create - сценарий использования для создания нового растения из base64 PNG.
+// Если imageData не является корректным PNG, выровненным по сетке редактора,
+// возвращается одна из ошибок domain.ErrImage*.
+// Изображение переводится в пиксельную сетку, и сохраняется только она.
 func (uc *CreateUseCase) Create(ctx context.Context, author, imageData string) (domain.Plant, error) {
-	// Здесь в будущем могла бы быть бизнес-валидация.
-	// Например, проверка imageData на корректность формата,
-	// или проверка имени автора на наличие в черном списке.
+	img, err := domain.DecodeImage(imageData, uc.limits)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	grid, err := domain.GridFromImage(img, uc.limits.CellSize)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	return uc.create(ctx, author, grid)
+}
+
+// CreateFromGrid - сценарий использования для создания нового растения из пиксельной сетки.
+// Некорректная сетка приводит к ошибке domain.ErrGridInvalid.
+func (uc *CreateUseCase) CreateFromGrid(ctx context.Context, author string, grid domain.Grid) (domain.Plant, error) {
+	if err := grid.Validate(uc.limits); err != nil {
+		return domain.Plant{}, err
+	}
+
+	return uc.create(ctx, author, grid)
+}
+
+// create сохраняет растение с уже проверенной сеткой.
+// Возвращенное растение содержит OwnerToken: с ним автор сможет изменить или удалить растение.
+// Если в контексте есть вошедший пользователь, растение записывается на него; анонимная посадка тоже разрешена.
+// Имя автора сохраняется нормализованным (domain.NormalizeAuthor) и должно пройти проверку AuthorChecker.
+// Похожее на уже посаженное растение в режиме DuplicateReject отклоняется с domain.ErrDuplicate,
+// а в режиме DuplicateFlag сажается в статусе StatusPending и ждет модератора.
+func (uc *CreateUseCase) create(ctx context.Context, author string, grid domain.Grid) (domain.Plant, error) {
+	author, err := checkAuthor(uc.authors, author)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	token, err := domain.NewOwnerToken()
+	if err != nil {
+		return domain.Plant{}, err
+	}
 
 	plant := domain.Plant{
-		Author:    author,
-		ImageData: imageData,
-		CreatedAt: time.Now().UTC(),
+		Author:         author,
+		Grid:           &grid,
+		OwnerTokenHash: domain.HashOwnerToken(token),
+		Fingerprint:    grid.Fingerprint(),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if plant.Status, err = uc.duplicates.Check(ctx, uc.repo, plant.Fingerprint, 0); err != nil {
+		return domain.Plant{}, err
+	}
+	if u, ok := userDomain.FromContext(ctx); ok {
+		plant.UserID = u.ID
 	}
 
 	createdPlant, err := uc.repo.Create(ctx, plant)
 	if err != nil {
 		return domain.Plant{}, err
 	}
+	logger.FromContext(ctx).Info("plant created",
+		slog.Int("plant_id", createdPlant.ID), slog.String("status", string(createdPlant.Status)))
+
+	// Вставка выполняется одним запросом, так что здесь растение уже закоммичено и видно другим.
+	// Растение, ждущее модератора, в ленту не попадает.
+	if uc.publisher != nil && plant.Status != domain.StatusPending {
+		uc.publisher.Publish(createdPlant)
+	}
+
+	createdPlant.OwnerToken = token
 	return createdPlant, nil
 }
+
+// checkAuthor нормализует имя автора и проверяет его, если задана проверка.
+func checkAuthor(checker AuthorChecker, author string) (string, error) {
+	author, err := domain.NormalizeAuthor(author)
+	if err != nil {
+		return "", err
+	}
+	if checker != nil {
+		if err := checker.CheckAuthor(author); err != nil {
+			return "", err
+		}
+	}
+	return author, nil
+}
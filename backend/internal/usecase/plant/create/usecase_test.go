@@ -2,54 +2,85 @@ package create
 
 import (
 	"context"
+	"image/color"
 	"testing"
 	"time"
 
 	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
 	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateUseCase_Create(t *testing.T) {
+	imageData := testutil.GenerateImageData(1)
+
 	tests := []struct {
 		name          string
 		author        string
 		imageData     string
 		mockSetup     func(*testutil.MockPlantRepository)
 		expectedError bool
+		expectedErr   error
 		expectedPlant domain.Plant
 	}{
 		{
 			name:      "successful plant creation",
 			author:    "test_author",
-			imageData: "base64_image_data",
+			imageData: imageData,
 			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
-				expectedPlant := domain.Plant{
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
+					// Сохраняется только сетка 16x16, исходный PNG не хранится
+					return plant.Author == "test_author" && plant.ImageData == "" &&
+						plant.Grid != nil && plant.Grid.Width == 16 && plant.Grid.Height == 16 &&
+						len(plant.OwnerTokenHash) == 64 && plant.OwnerToken == ""
+				})).Return(domain.Plant{
 					ID:        1,
 					Author:    "test_author",
-					ImageData: "base64_image_data",
+					Grid:      &domain.Grid{Width: 16, Height: 16},
 					CreatedAt: time.Now().UTC(),
-				}
-				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
-					return plant.Author == "test_author" && plant.ImageData == "base64_image_data"
-				})).Return(expectedPlant, nil)
+				}, nil)
 			},
 			expectedError: false,
 			expectedPlant: domain.Plant{
-				ID:        1,
-				Author:    "test_author",
-				ImageData: "base64_image_data",
+				ID:     1,
+				Author: "test_author",
 			},
 		},
 		{
 			name:      "repository error",
 			author:    "test_author",
-			imageData: "base64_image_data",
+			imageData: imageData,
 			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
 				mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.Plant{}, assert.AnError)
 			},
 			expectedError: true,
+			expectedErr:   assert.AnError,
+		},
+		{
+			name:          "image is not base64",
+			author:        "test_author",
+			imageData:     "base64_image_data",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: true,
+			expectedErr:   domain.ErrImageEncoding,
+		},
+		{
+			name:          "image is not a png",
+			author:        "test_author",
+			imageData:     "R0lGODlhAQABAAAAACw=",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: true,
+			expectedErr:   domain.ErrImageFormat,
+		},
+		{
+			name:          "image is not aligned to the grid",
+			author:        "test_author",
+			imageData:     "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: true,
+			expectedErr:   domain.ErrImageGrid,
 		},
 	}
 
@@ -65,21 +96,292 @@ func TestCreateUseCase_Create(t *testing.T) {
 
 			// Assert
 			if tt.expectedError {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedErr)
 				assert.Empty(t, result)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedPlant.Author, result.Author)
 				assert.Equal(t, tt.expectedPlant.ImageData, result.ImageData)
+				assert.NotNil(t, result.Grid)
 				assert.NotZero(t, result.ID)
 				assert.NotZero(t, result.CreatedAt)
+				assert.NotEmpty(t, result.OwnerToken)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateUseCase_OwnerToken(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+	var storedHash string
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.Plant{ID: 1}, nil).Run(func(args mock.Arguments) {
+		storedHash = args.Get(1).(domain.Plant).OwnerTokenHash
+	})
+	useCase := NewCreateUseCase(mockRepo)
+
+	// Act
+	result, err := useCase.Create(context.Background(), "author", testutil.GenerateImageData(1))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HashOwnerToken(result.OwnerToken), storedHash, "only the hash of the returned token is stored")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUseCase_UserID(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected int
+	}{
+		{name: "anonymous", ctx: context.Background(), expected: 0},
+		{name: "logged in", ctx: userDomain.WithUser(context.Background(), userDomain.User{ID: 42}), expected: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
+				return plant.UserID == tt.expected
+			})).Return(domain.Plant{ID: 1, UserID: tt.expected}, nil)
+			useCase := NewCreateUseCase(mockRepo)
+
+			// Act
+			result, err := useCase.Create(tt.ctx, "author", testutil.GenerateImageData(1))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.UserID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateUseCase_CreateFromGrid(t *testing.T) {
+	validGrid := domain.Grid{
+		Width:   2,
+		Height:  2,
+		Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+		Pixels:  []uint8{0, 1, 1, 0},
+	}
+
+	tests := []struct {
+		name          string
+		grid          domain.Grid
+		mockSetup     func(*testutil.MockPlantRepository)
+		expectedError error
+	}{
+		{
+			name: "successful plant creation",
+			grid: validGrid,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
+					return plant.Author == "grid_author" && plant.Grid != nil && plant.Grid.Width == 2
+				})).Return(domain.Plant{ID: 1, Author: "grid_author", Grid: &validGrid, CreatedAt: time.Now().UTC()}, nil)
+			},
+		},
+		{
+			name:          "invalid grid",
+			grid:          domain.Grid{Width: 2, Height: 2, Palette: []color.NRGBA{{}}, Pixels: []uint8{0}},
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: domain.ErrGridInvalid,
+		},
+		{
+			name: "repository error",
+			grid: validGrid,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewCreateUseCase(mockRepo)
+
+			// Act
+			result, err := useCase.CreateFromGrid(context.Background(), "grid_author", tt.grid)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotZero(t, result.ID)
+				assert.Equal(t, &validGrid, result.Grid)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateUseCase_Duplicates(t *testing.T) {
+	grid := domain.Grid{
+		Width:   2,
+		Height:  2,
+		Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+		Pixels:  []uint8{0, 1, 1, 0},
+	}
+	fingerprint := grid.Fingerprint()
+	duplicate := domain.Duplicate{PlantID: 3, Distance: 2}
+
+	tests := []struct {
+		name           string
+		mode           domain.DuplicateMode
+		found          error
+		expectedStatus domain.Status
+		expectedError  error
+	}{
+		{name: "check disabled", mode: domain.DuplicateOff},
+		{name: "no duplicate", mode: domain.DuplicateReject, found: domain.ErrNotFound},
+		{name: "duplicate rejected", mode: domain.DuplicateReject, expectedError: domain.ErrDuplicate},
+		{name: "duplicate flagged", mode: domain.DuplicateFlag, expectedStatus: domain.StatusPending},
+		{name: "lookup error", mode: domain.DuplicateFlag, found: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			if tt.mode != domain.DuplicateOff {
+				mockRepo.On("FindDuplicate", mock.Anything, fingerprint, 4, 0).Return(duplicate, tt.found)
+			}
+			if tt.expectedError == nil {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
+					return plant.Fingerprint == fingerprint && plant.Status == tt.expectedStatus
+				})).Return(domain.Plant{ID: 1, Status: tt.expectedStatus}, nil)
+			}
+			useCase := NewCreateUseCase(mockRepo, WithDuplicateCheck(tt.mode, 4))
+
+			// Act
+			result, err := useCase.CreateFromGrid(context.Background(), "author", grid)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, result.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// MockAuthorChecker - мок для AuthorChecker
+type MockAuthorChecker struct {
+	mock.Mock
+}
+
+func (m *MockAuthorChecker) CheckAuthor(name string) error {
+	return m.Called(name).Error(0)
+}
+
+func TestCreateUseCase_Author(t *testing.T) {
+	grid := domain.Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{A: 255}}, Pixels: []uint8{0}}
+
+	tests := []struct {
+		name           string
+		author         string
+		checked        string
+		checkErr       error
+		expectedAuthor string
+		expectedError  error
+	}{
+		{name: "stored normalized", author: "  Lazy\u200b   Gardener ", checked: "Lazy Gardener", expectedAuthor: "Lazy Gardener"},
+		{name: "fullwidth folded", author: "\uff21nna", checked: "Anna", expectedAuthor: "Anna"},
+		{name: "empty after normalization", author: " \u200b\t", expectedError: domain.ErrAuthorEmpty},
+		{name: "blocked", author: "admin", checked: "admin", checkErr: domain.ErrAuthorBlocked, expectedError: domain.ErrAuthorBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			checker := &MockAuthorChecker{}
+			if tt.checked != "" {
+				checker.On("CheckAuthor", tt.checked).Return(tt.checkErr)
+			}
+			if tt.expectedError == nil {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(plant domain.Plant) bool {
+					return plant.Author == tt.expectedAuthor
+				})).Return(domain.Plant{ID: 1, Author: tt.expectedAuthor}, nil)
 			}
+			useCase := NewCreateUseCase(mockRepo, WithAuthorChecker(checker))
+
+			// Act
+			result, err := useCase.CreateFromGrid(context.Background(), tt.author, grid)
 
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAuthor, result.Author)
+			}
+			checker.AssertExpectations(t)
 			mockRepo.AssertExpectations(t)
 		})
 	}
 }
 
+// MockPublisher - мок для Publisher
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(plant domain.Plant) {
+	m.Called(plant)
+}
+
+func TestCreateUseCase_Publish(t *testing.T) {
+	grid := domain.Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{A: 255}}, Pixels: []uint8{0}}
+	created := domain.Plant{ID: 7, Author: "author", Grid: &grid, CreatedAt: time.Now()}
+
+	t.Run("published after successful create", func(t *testing.T) {
+		// Arrange
+		mockRepo := testutil.NewMockPlantRepository()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(created, nil)
+		publisher := &MockPublisher{}
+		publisher.On("Publish", created).Return()
+		useCase := NewCreateUseCase(mockRepo, WithPublisher(publisher))
+
+		// Act
+		_, err := useCase.CreateFromGrid(context.Background(), "author", grid)
+
+		// Assert
+		assert.NoError(t, err)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("not published when create fails", func(t *testing.T) {
+		// Arrange
+		mockRepo := testutil.NewMockPlantRepository()
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.Plant{}, assert.AnError)
+		publisher := &MockPublisher{}
+		useCase := NewCreateUseCase(mockRepo, WithPublisher(publisher))
+
+		// Act
+		_, err := useCase.CreateFromGrid(context.Background(), "author", grid)
+
+		// Assert
+		assert.Error(t, err)
+		publisher.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+}
+
 func TestNewCreateUseCase(t *testing.T) {
 	mockRepo := testutil.NewMockPlantRepository()
 	useCase := NewCreateUseCase(mockRepo)
@@ -0,0 +1,50 @@
+package duplicates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// Finder ищет растение, похожее на изображение с отпечатком fp.
+type Finder interface {
+	// FindDuplicate не учитывает растение excludeID; 0 означает, что исключать нечего.
+	FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error)
+}
+
+// Policy - что делать с изображением, похожим на уже посаженное: при посадке и при смене изображения
+// действует одно и то же правило, иначе дубликат можно было бы получить изменением уникального растения.
+type Policy struct {
+	Mode domain.DuplicateMode
+	// MaxDistance - на сколько бит могут отличаться отпечатки похожих изображений; отрицательный - только точные совпадения.
+	MaxDistance int
+}
+
+// Check ищет растение, похожее на изображение с отпечатком fp, кроме самого растения excludeID,
+// и возвращает статус, который должно получить растение с этим изображением. Пустой статус означает,
+// что статус не меняется. В режиме DuplicateReject похожее растение дает domain.ErrDuplicate,
+// в режиме DuplicateFlag - StatusPending.
+func (p Policy) Check(ctx context.Context, finder Finder, fp domain.Fingerprint, excludeID int) (domain.Status, error) {
+	if p.Mode == domain.DuplicateOff || p.Mode == "" {
+		return "", nil
+	}
+
+	duplicate, err := finder.FindDuplicate(ctx, fp, p.MaxDistance, excludeID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if p.Mode == domain.DuplicateReject {
+		return "", fmt.Errorf("%w: plant %d is %d bits away", domain.ErrDuplicate, duplicate.PlantID, duplicate.Distance)
+	}
+	logger.FromContext(ctx).Info("plant flagged as duplicate",
+		slog.Int("duplicate_of", duplicate.PlantID), slog.Int("distance", duplicate.Distance))
+	return domain.StatusPending, nil
+}
@@ -0,0 +1,120 @@
+package duplicates
+
+import (
+	"context"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// DefaultBackfillBatch - сколько растений за раз читает Backfill.
+const DefaultBackfillBatch = 200
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	ListFingerprints(ctx context.Context) ([]domain.FingerprintedPlant, error)
+	ListWithoutFingerprint(ctx context.Context, afterID, limit int) ([]domain.Plant, error)
+	SetFingerprint(ctx context.Context, id int, fp domain.Fingerprint) error
+}
+
+// BackfillResult - итог дозаполнения отпечатков.
+type BackfillResult struct {
+	Updated int
+	// Skipped - растения, изображение которых не удалось разобрать; они остаются без отпечатка.
+	Skipped int
+}
+
+// DuplicatesUseCase - это конкретная реализация бизнес-логики поиска похожих растений для модераторов.
+type DuplicatesUseCase struct {
+	repo        PlantRepository
+	limits      domain.ImageLimits
+	maxDistance int
+}
+
+// Option настраивает DuplicatesUseCase.
+type Option func(*DuplicatesUseCase)
+
+// WithMaxDistance задает порог похожести по умолчанию - тот же, что при посадке.
+func WithMaxDistance(d int) Option {
+	return func(uc *DuplicatesUseCase) {
+		uc.maxDistance = d
+	}
+}
+
+// NewDuplicatesUseCase - конструктор для DuplicatesUseCase.
+func NewDuplicatesUseCase(r PlantRepository, opts ...Option) *DuplicatesUseCase {
+	uc := &DuplicatesUseCase{repo: r, limits: domain.DefaultImageLimits, maxDistance: domain.DefaultDuplicateDistance}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Clusters - сценарий использования для списка групп похожих растений (см. domain.ClusterDuplicates).
+// Отрицательный maxDistance означает порог из настроек. Возвращается не больше limit групп, самые большие первыми.
+func (uc *DuplicatesUseCase) Clusters(ctx context.Context, maxDistance, limit int) ([]domain.DuplicateCluster, error) {
+	if limit <= 0 {
+		return []domain.DuplicateCluster{}, nil
+	}
+	if maxDistance < 0 {
+		maxDistance = uc.maxDistance
+	}
+
+	plants, err := uc.repo.ListFingerprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := domain.ClusterDuplicates(plants, maxDistance)
+	return clusters[:min(limit, len(clusters))], nil
+}
+
+// Backfill вычисляет отпечатки растений, посаженных до их появления, пачками по batch штук.
+// У старых растений без сетки она строится из PNG так же, как при создании.
+func (uc *DuplicatesUseCase) Backfill(ctx context.Context, batch int) (BackfillResult, error) {
+	if batch <= 0 {
+		batch = DefaultBackfillBatch
+	}
+
+	var result BackfillResult
+	afterID := 0
+	for {
+		plants, err := uc.repo.ListWithoutFingerprint(ctx, afterID, batch)
+		if err != nil {
+			return result, err
+		}
+
+		for _, p := range plants {
+			afterID = p.ID
+			grid, ok := uc.grid(p)
+			if !ok {
+				result.Skipped++
+				continue
+			}
+			if err := uc.repo.SetFingerprint(ctx, p.ID, grid.Fingerprint()); err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+
+		if len(plants) < batch {
+			return result, nil
+		}
+	}
+}
+
+// grid возвращает сетку растения; false - если ее нет и PNG не проходит проверку.
+func (uc *DuplicatesUseCase) grid(p domain.Plant) (domain.Grid, bool) {
+	if p.Grid != nil {
+		return *p.Grid, true
+	}
+
+	img, err := domain.DecodeImage(p.ImageData, uc.limits)
+	if err != nil {
+		return domain.Grid{}, false
+	}
+	grid, err := domain.GridFromImage(img, uc.limits.CellSize)
+	if err != nil {
+		return domain.Grid{}, false
+	}
+	return grid, true
+}
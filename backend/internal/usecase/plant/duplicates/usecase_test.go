@@ -0,0 +1,104 @@
+package duplicates
+
+import (
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicatesUseCase_Clusters(t *testing.T) {
+	now := time.Now()
+	plants := []domain.FingerprintedPlant{
+		{ID: 1, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "a"}},
+		{ID: 2, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "a"}},
+		{ID: 3, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "b", AHash: 0xFF00, DHash: 0xFF00}},
+		{ID: 4, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "c", AHash: 0xFF01, DHash: 0xFF00}},
+		{ID: 5, CreatedAt: now, Fingerprint: domain.Fingerprint{ContentHash: "d", AHash: 0xFF01, DHash: 0xFF00}},
+	}
+
+	tests := []struct {
+		name          string
+		distance      int
+		limit         int
+		repoErr       error
+		expectedSizes []int
+		expectedError error
+	}{
+		{name: "all clusters", distance: 1, limit: 10, expectedSizes: []int{3, 2}},
+		{name: "exact only", distance: 0, limit: 10, expectedSizes: []int{2, 2}},
+		{name: "configured distance", distance: -1, limit: 10, expectedSizes: []int{3, 2}},
+		{name: "limited", distance: 1, limit: 1, expectedSizes: []int{3}},
+		{name: "zero limit", distance: 1, limit: 0, expectedSizes: []int{}},
+		{name: "repository error", distance: 1, limit: 10, repoErr: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			if tt.limit > 0 {
+				mockRepo.On("ListFingerprints", mock.Anything).Return(plants, tt.repoErr)
+			}
+			useCase := NewDuplicatesUseCase(mockRepo, WithMaxDistance(1))
+
+			// Act
+			clusters, err := useCase.Clusters(context.Background(), tt.distance, tt.limit)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			sizes := make([]int, len(clusters))
+			for i, c := range clusters {
+				sizes[i] = len(c.Plants)
+			}
+			assert.Equal(t, tt.expectedSizes, sizes)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDuplicatesUseCase_Backfill(t *testing.T) {
+	// Arrange
+	grid := domain.Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{A: 255}}, Pixels: []uint8{0}}
+	legacy := testutil.GenerateImageData(1)
+
+	mockRepo := testutil.NewMockPlantRepository()
+	mockRepo.On("ListWithoutFingerprint", mock.Anything, 0, 2).Return([]domain.Plant{
+		{ID: 1, Grid: &grid},
+		{ID: 2, ImageData: legacy},
+	}, nil)
+	mockRepo.On("ListWithoutFingerprint", mock.Anything, 2, 2).Return([]domain.Plant{
+		{ID: 5, ImageData: "broken"},
+	}, nil)
+	mockRepo.On("SetFingerprint", mock.Anything, 1, grid.Fingerprint()).Return(nil)
+	mockRepo.On("SetFingerprint", mock.Anything, 2, mock.MatchedBy(func(fp domain.Fingerprint) bool {
+		return !fp.IsZero()
+	})).Return(nil)
+	useCase := NewDuplicatesUseCase(mockRepo)
+
+	// Act
+	result, err := useCase.Backfill(context.Background(), 2)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, BackfillResult{Updated: 2, Skipped: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewDuplicatesUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewDuplicatesUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,33 @@
+package get_by_id
+
+import (
+	"context"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+}
+
+// GetByIDUseCase - это конкретная реализация бизнес-логики для получения растения по идентификатору.
+type GetByIDUseCase struct {
+	repo PlantRepository
+}
+
+// NewGetByIDUseCase - конструктор для GetByIDUseCase.
+func NewGetByIDUseCase(r PlantRepository) *GetByIDUseCase {
+	return &GetByIDUseCase{repo: r}
+}
+
+// GetByID - сценарий использования для получения одного растения.
+// Если растения нет, возвращается domain.ErrNotFound.
+func (uc *GetByIDUseCase) GetByID(ctx context.Context, id int) (domain.Plant, error) {
+	plant, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	return plant, nil
+}
@@ -0,0 +1,77 @@
+package get_by_id
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetByIDUseCase_GetByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            int
+		mockSetup     func(*testutil.MockPlantRepository)
+		expectedError error
+	}{
+		{
+			name: "successful get",
+			id:   1,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).
+					Return(domain.Plant{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()}, nil)
+			},
+		},
+		{
+			name: "plant not found",
+			id:   2,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 2).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name: "repository error",
+			id:   3,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 3).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewGetByIDUseCase(mockRepo)
+
+			// Act
+			result, err := useCase.GetByID(context.Background(), tt.id)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, result.ID)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewGetByIDUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewGetByIDUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,50 @@
+package get_image
+
+import (
+	"context"
+	"errors"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+}
+
+// Image - PNG растения вместе с версией изображения (domain.Plant.ImageVersion).
+type Image struct {
+	PNG     []byte
+	Version int
+}
+
+// GetImageUseCase - это конкретная реализация бизнес-логики для получения изображения растения.
+type GetImageUseCase struct {
+	repo PlantRepository
+}
+
+// NewGetImageUseCase - конструктор для GetImageUseCase.
+func NewGetImageUseCase(r PlantRepository) *GetImageUseCase {
+	return &GetImageUseCase{repo: r}
+}
+
+// GetImage - сценарий использования для получения PNG растения, увеличенного в scale раз.
+// Если растения нет, возвращается domain.ErrNotFound, если увеличенное изображение слишком велико -
+// domain.ErrImageDimensions вида cerror.Invalid: это ошибка параметра запроса, а не изображения.
+// Остальные ошибки построения PNG означают испорченные данные в хранилище и считаются внутренними.
+func (uc *GetImageUseCase) GetImage(ctx context.Context, id, scale int) (Image, error) {
+	plant, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return Image{}, err
+	}
+
+	raw, err := plant.PNG(scale)
+	if errors.Is(err, domain.ErrImageDimensions) {
+		return Image{}, cerror.Wrap(cerror.Invalid, err)
+	}
+	if err != nil {
+		return Image{}, cerror.Wrap(cerror.Internal, err)
+	}
+	return Image{PNG: raw, Version: plant.ImageVersion}, nil
+}
@@ -0,0 +1,133 @@
+package get_image
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetImageUseCase_GetImage(t *testing.T) {
+	gridPlant := domain.Plant{
+		ID:     1,
+		Author: "grid_author",
+		Grid: &domain.Grid{
+			Width:   2,
+			Height:  1,
+			Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+			Pixels:  []uint8{0, 1},
+		},
+		ImageVersion: 3,
+		CreatedAt:    time.Now(),
+	}
+	legacyPlant := domain.Plant{
+		ID:        2,
+		Author:    "legacy_author",
+		ImageData: testutil.GenerateImageData(1),
+		CreatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		id             int
+		scale          int
+		mockSetup      func(*testutil.MockPlantRepository)
+		expectedError  error
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{
+			name:  "grid plant at native size",
+			id:    1,
+			scale: 1,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(gridPlant, nil)
+			},
+			expectedWidth:  2,
+			expectedHeight: 1,
+		},
+		{
+			name:  "grid plant upscaled",
+			id:    1,
+			scale: 10,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(gridPlant, nil)
+			},
+			expectedWidth:  20,
+			expectedHeight: 10,
+		},
+		{
+			name:  "legacy png plant upscaled",
+			id:    2,
+			scale: 2,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 2).Return(legacyPlant, nil)
+			},
+			expectedWidth:  320,
+			expectedHeight: 320,
+		},
+		{
+			name:  "scale too large for legacy png",
+			id:    2,
+			scale: 32,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 2).Return(legacyPlant, nil)
+			},
+			expectedError: domain.ErrImageDimensions,
+		},
+		{
+			name:  "plant not found",
+			id:    3,
+			scale: 1,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 3).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewGetImageUseCase(mockRepo)
+
+			// Act
+			result, err := useCase.GetImage(context.Background(), tt.id, tt.scale)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, result.PNG)
+			} else {
+				require.NoError(t, err)
+				cfg, err := png.DecodeConfig(bytes.NewReader(result.PNG))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedWidth, cfg.Width)
+				assert.Equal(t, tt.expectedHeight, cfg.Height)
+				if tt.id == 1 {
+					assert.Equal(t, 3, result.Version)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewGetImageUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewGetImageUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
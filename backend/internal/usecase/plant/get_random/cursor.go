@@ -0,0 +1,49 @@
+package get_random
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrInvalidCursor - курсор не был выдан сервером, поврежден или относится к другому зерну.
+var ErrInvalidCursor = cerror.New(cerror.Invalid, "invalid cursor")
+
+// Cursor - позиция в перемешанном порядке: зерно порядка и последнее выданное растение.
+type Cursor struct {
+	Seed   int64
+	LastID int
+}
+
+// EncodeCursor превращает позицию в непрозрачную для клиента строку.
+func EncodeCursor(c Cursor) string {
+	raw := fmt.Sprintf("%d:%d", c.Seed, c.LastID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor разбирает строку, полученную из EncodeCursor.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	seedStr, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	seed, err := strconv.ParseInt(seedStr, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{Seed: seed, LastID: id}, nil
+}
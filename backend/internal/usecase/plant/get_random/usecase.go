@@ -8,7 +8,15 @@ import (
 
 // PlantRepository определяет контракт для слоя данных.
 type PlantRepository interface {
-	GetRandom(ctx context.Context, count int) ([]domain.Plant, error)
+	GetRandom(ctx context.Context, filter domain.RandomFilter) ([]domain.Plant, error)
+}
+
+// Page - набор случайных растений.
+// NextCursor заполняется только для детерминированной выборки (с зерном), если в ней есть еще растения.
+type Page struct {
+	Plants     []domain.Plant
+	Seed       *int64
+	NextCursor string
 }
 
 // GetRandomUseCase - это конкретная реализация бизнес-логики для получения случайных растений.
@@ -22,11 +30,48 @@ func NewGetRandomUseCase(r PlantRepository) *GetRandomUseCase {
 }
 
 // GetRandom - сценарий использования для получения случайных растений.
-func (uc *GetRandomUseCase) GetRandom(ctx context.Context, count int) ([]domain.Plant, error) {
-	plants, err := uc.repo.GetRandom(ctx, count)
+// cursor - значение NextCursor предыдущей страницы или пустая строка. Курсор несет в себе зерно,
+// поэтому filter.Seed можно не передавать; если же зерно передано и не совпадает с курсором,
+// возвращается ErrInvalidCursor.
+func (uc *GetRandomUseCase) GetRandom(ctx context.Context, filter domain.RandomFilter, cursor string) (Page, error) {
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		if filter.Seed != nil && *filter.Seed != after.Seed {
+			return Page{}, ErrInvalidCursor
+		}
+		filter.Seed = &after.Seed
+		filter.AfterID = after.LastID
+	}
+
+	count := filter.Count
+	if count <= 0 {
+		return Page{Plants: []domain.Plant{}, Seed: filter.Seed}, nil
+	}
+
+	if filter.Seed == nil {
+		plants, err := uc.repo.GetRandom(ctx, filter)
+		if err != nil {
+			return Page{}, err
+		}
+		return Page{Plants: plants}, nil
+	}
+
+	// Запрашиваем на одно растение больше, чтобы понять, есть ли следующая страница.
+	filter.Count = count + 1
+
+	plants, err := uc.repo.GetRandom(ctx, filter)
 	if err != nil {
-		return nil, err
+		return Page{}, err
+	}
+
+	page := Page{Plants: plants, Seed: filter.Seed}
+	if len(plants) > count {
+		page.Plants = plants[:count]
+		page.NextCursor = EncodeCursor(Cursor{Seed: *filter.Seed, LastID: page.Plants[count-1].ID})
 	}
 
-	return plants, nil
+	return page, nil
 }
@@ -9,45 +9,95 @@ import (
 	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetRandomUseCase_GetRandom(t *testing.T) {
+	seed := int64(42)
+	otherSeed := int64(7)
+	plants := []domain.Plant{
+		{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
+		{ID: 2, Author: "author2", ImageData: "data2", CreatedAt: time.Now()},
+		{ID: 3, Author: "author3", ImageData: "data3", CreatedAt: time.Now()},
+	}
+
 	tests := []struct {
-		name          string
-		count         int
-		mockSetup     func(*testutil.MockPlantRepository)
-		expectedError bool
-		expectedCount int
+		name               string
+		filter             domain.RandomFilter
+		cursor             string
+		mockSetup          func(*testutil.MockPlantRepository)
+		expectedError      error
+		expectedCount      int
+		expectedSeed       *int64
+		expectedNextCursor string
 	}{
 		{
-			name:  "successful get random plants",
-			count: 5,
+			name:   "successful get random plants",
+			filter: domain.RandomFilter{Count: 5},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5}).Return(plants, nil)
+			},
+			expectedCount: 3,
+		},
+		{
+			name:   "exclude is passed to repository",
+			filter: domain.RandomFilter{Count: 5, Exclude: []int{4, 5}},
 			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
-				expectedPlants := []domain.Plant{
-					{ID: 1, Author: "author1", ImageData: "data1", CreatedAt: time.Now()},
-					{ID: 2, Author: "author2", ImageData: "data2", CreatedAt: time.Now()},
-					{ID: 3, Author: "author3", ImageData: "data3", CreatedAt: time.Now()},
-				}
-				mockRepo.On("GetRandom", mock.Anything, 5).Return(expectedPlants, nil)
+				mockRepo.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 5, Exclude: []int{4, 5}}).
+					Return(plants, nil)
 			},
-			expectedError: false,
 			expectedCount: 3,
 		},
 		{
-			name:  "repository error",
-			count: 3,
+			name:   "seeded first page with more plants",
+			filter: domain.RandomFilter{Count: 2, Seed: &seed},
 			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
-				mockRepo.On("GetRandom", mock.Anything, 3).Return([]domain.Plant{}, assert.AnError)
+				mockRepo.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 3, Seed: &seed}).Return(plants, nil)
 			},
-			expectedError: true,
+			expectedCount:      2,
+			expectedSeed:       &seed,
+			expectedNextCursor: EncodeCursor(Cursor{Seed: seed, LastID: 2}),
 		},
 		{
-			name:  "empty result",
-			count: 10,
+			name:   "seeded last page from cursor",
+			filter: domain.RandomFilter{Count: 2},
+			cursor: EncodeCursor(Cursor{Seed: seed, LastID: 2}),
 			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
-				mockRepo.On("GetRandom", mock.Anything, 10).Return([]domain.Plant{}, nil)
+				mockRepo.On("GetRandom", mock.Anything, mock.MatchedBy(func(f domain.RandomFilter) bool {
+					return f.Count == 3 && f.Seed != nil && *f.Seed == seed && f.AfterID == 2
+				})).Return(plants[2:], nil)
+			},
+			expectedCount: 1,
+			expectedSeed:  &seed,
+		},
+		{
+			name:          "cursor for another seed",
+			filter:        domain.RandomFilter{Count: 2, Seed: &otherSeed},
+			cursor:        EncodeCursor(Cursor{Seed: seed, LastID: 2}),
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:          "invalid cursor",
+			filter:        domain.RandomFilter{Count: 2},
+			cursor:        "definitely-not-a-cursor",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:   "repository error",
+			filter: domain.RandomFilter{Count: 3},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 3}).Return([]domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:   "empty result",
+			filter: domain.RandomFilter{Count: 10},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetRandom", mock.Anything, domain.RandomFilter{Count: 10}).Return([]domain.Plant{}, nil)
 			},
-			expectedError: false,
 			expectedCount: 0,
 		},
 	}
@@ -60,15 +110,17 @@ func TestGetRandomUseCase_GetRandom(t *testing.T) {
 			useCase := NewGetRandomUseCase(mockRepo)
 
 			// Act
-			result, err := useCase.GetRandom(context.Background(), tt.count)
+			page, err := useCase.GetRandom(context.Background(), tt.filter, tt.cursor)
 
 			// Assert
-			if tt.expectedError {
-				assert.Error(t, err)
-				assert.Nil(t, result)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, page.Plants)
 			} else {
-				assert.NoError(t, err)
-				assert.Len(t, result, tt.expectedCount)
+				require.NoError(t, err)
+				assert.Len(t, page.Plants, tt.expectedCount)
+				assert.Equal(t, tt.expectedSeed, page.Seed)
+				assert.Equal(t, tt.expectedNextCursor, page.NextCursor)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -76,6 +128,24 @@ func TestGetRandomUseCase_GetRandom(t *testing.T) {
 	}
 }
 
+func TestCursor_RoundTrip(t *testing.T) {
+	original := Cursor{Seed: -9876543210, LastID: 42}
+
+	decoded, err := DecodeCursor(EncodeCursor(original))
+
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "!!!", "bm8tY29sb24", "YWJjOjE", "MTIzOmFiYw", "MTIzOi0x"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := DecodeCursor(s)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
 func TestNewGetRandomUseCase(t *testing.T) {
 	mockRepo := testutil.NewMockPlantRepository()
 	useCase := NewGetRandomUseCase(mockRepo)
@@ -0,0 +1,47 @@
+package list
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrInvalidCursor - курсор не был выдан сервером или поврежден.
+var ErrInvalidCursor = cerror.New(cerror.Invalid, "invalid cursor")
+
+// EncodeCursor превращает позицию в списке в непрозрачную для клиента строку.
+// Время хранится в микросекундах - с такой точностью PostgreSQL хранит created_at.
+func EncodeCursor(c domain.Cursor) string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixMicro(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor разбирает строку, полученную из EncodeCursor.
+func DecodeCursor(s string) (domain.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.Cursor{}, ErrInvalidCursor
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return domain.Cursor{}, ErrInvalidCursor
+	}
+
+	usec, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return domain.Cursor{}, ErrInvalidCursor
+	}
+	plantID, err := strconv.Atoi(id)
+	if err != nil || plantID <= 0 {
+		return domain.Cursor{}, ErrInvalidCursor
+	}
+
+	return domain.Cursor{CreatedAt: time.UnixMicro(usec).UTC(), ID: plantID}, nil
+}
@@ -0,0 +1,64 @@
+package list
+
+import (
+	"context"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	List(ctx context.Context, filter domain.ListFilter) ([]domain.Plant, error)
+}
+
+// Page - страница списка растений.
+// NextCursor пуст, если это последняя страница.
+type Page struct {
+	Plants     []domain.Plant
+	NextCursor string
+}
+
+// ListUseCase - это конкретная реализация бизнес-логики для постраничного обхода леса.
+type ListUseCase struct {
+	repo PlantRepository
+}
+
+// NewListUseCase - конструктор для ListUseCase.
+func NewListUseCase(r PlantRepository) *ListUseCase {
+	return &ListUseCase{repo: r}
+}
+
+// List - сценарий использования для получения страницы растений.
+// cursor - значение NextCursor предыдущей страницы или пустая строка для первой страницы.
+// Некорректный курсор приводит к ошибке ErrInvalidCursor.
+func (uc *ListUseCase) List(ctx context.Context, filter domain.ListFilter, cursor string) (Page, error) {
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		filter.After = &after
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		return Page{Plants: []domain.Plant{}}, nil
+	}
+
+	// Запрашиваем на одно растение больше, чтобы понять, есть ли следующая страница.
+	filter.Limit = limit + 1
+
+	plants, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{Plants: plants}
+	if len(plants) > limit {
+		page.Plants = plants[:limit]
+		last := page.Plants[limit-1]
+		page.NextCursor = EncodeCursor(domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
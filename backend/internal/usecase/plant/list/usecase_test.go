@@ -0,0 +1,128 @@
+package list
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUseCase_List(t *testing.T) {
+	base := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	plants := []domain.Plant{
+		{ID: 3, Author: "author", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: 2, Author: "author", CreatedAt: base.Add(time.Minute)},
+		{ID: 1, Author: "author", CreatedAt: base},
+	}
+	cursor := EncodeCursor(domain.Cursor{CreatedAt: base.Add(2 * time.Minute), ID: 3})
+
+	tests := []struct {
+		name               string
+		filter             domain.ListFilter
+		cursor             string
+		mockSetup          func(*testutil.MockPlantRepository)
+		expectedError      error
+		expectedIDs        []int
+		expectedNextCursor string
+	}{
+		{
+			name:   "first page with next page available",
+			filter: domain.ListFilter{Author: "author", Limit: 2},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("List", mock.Anything, domain.ListFilter{Author: "author", Limit: 3}).Return(plants, nil)
+			},
+			expectedIDs:        []int{3, 2},
+			expectedNextCursor: EncodeCursor(domain.Cursor{CreatedAt: base.Add(time.Minute), ID: 2}),
+		},
+		{
+			name:   "last page",
+			filter: domain.ListFilter{Limit: 2},
+			cursor: cursor,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("List", mock.Anything, mock.MatchedBy(func(f domain.ListFilter) bool {
+					return f.Limit == 3 && f.After != nil && f.After.ID == 3 && f.After.CreatedAt.Equal(base.Add(2*time.Minute))
+				})).Return(plants[1:], nil)
+			},
+			expectedIDs: []int{2, 1},
+		},
+		{
+			name:          "invalid cursor",
+			filter:        domain.ListFilter{Limit: 2},
+			cursor:        "definitely-not-a-cursor",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:   "repository error",
+			filter: domain.ListFilter{Limit: 2},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("List", mock.Anything, mock.Anything).Return([]domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:        "zero limit",
+			filter:      domain.ListFilter{Limit: 0},
+			mockSetup:   func(mockRepo *testutil.MockPlantRepository) {},
+			expectedIDs: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewListUseCase(mockRepo)
+
+			// Act
+			page, err := useCase.List(context.Background(), tt.filter, tt.cursor)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				ids := make([]int, 0, len(page.Plants))
+				for _, p := range page.Plants {
+					ids = append(ids, p.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+				assert.Equal(t, tt.expectedNextCursor, page.NextCursor)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	original := domain.Cursor{CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 123456000, time.UTC), ID: 42}
+
+	decoded, err := DecodeCursor(EncodeCursor(original))
+
+	require.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "!!!", "bm8tY29sb24", "YWJjOjE", "MTIzOmFiYw", "MTIzOi0x"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := DecodeCursor(s)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestNewListUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewListUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,81 @@
+package moderate
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrActorRequired - действие модерации нельзя выполнить анонимно.
+var ErrActorRequired = cerror.New(cerror.Invalid, "moderation actor is required")
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByIDAnyStatus(ctx context.Context, id int) (domain.Plant, error)
+	Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error)
+	ModerationLog(ctx context.Context, plantID int) ([]domain.ModerationEvent, error)
+	ResolveReports(ctx context.Context, plantID int, at time.Time) (int, error)
+}
+
+// ModerateUseCase - это конкретная реализация бизнес-логики модерации растений.
+type ModerateUseCase struct {
+	repo PlantRepository
+}
+
+// NewModerateUseCase - конструктор для ModerateUseCase.
+func NewModerateUseCase(r PlantRepository) *ModerateUseCase {
+	return &ModerateUseCase{repo: r}
+}
+
+// Moderate - сценарий использования для скрытия, восстановления, удаления и окончательного удаления растения.
+// Каждое действие записывается в журнал с именем модератора и временем.
+// Если растения нет, возвращается domain.ErrNotFound, если действие неприменимо к текущему статусу -
+// domain.ErrInvalidTransition.
+// Решение модератора считается рассмотрением жалоб, поэтому открытые жалобы на растение закрываются.
+func (uc *ModerateUseCase) Moderate(ctx context.Context, id int, action domain.ModerationAction, actor, reason string) (domain.Plant, error) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return domain.Plant{}, ErrActorRequired
+	}
+
+	plant, err := uc.repo.GetByIDAnyStatus(ctx, id)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	next, err := domain.NextStatus(plant.Status, action)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	now := time.Now().UTC()
+	plant, err = uc.repo.Moderate(ctx, domain.ModerationEvent{
+		PlantID:    id,
+		Action:     action,
+		Actor:      actor,
+		Reason:     strings.TrimSpace(reason),
+		FromStatus: plant.Status,
+		ToStatus:   next,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return domain.Plant{}, err
+	}
+
+	// Жалобы на окончательно удаленное растение удаляются вместе с ним.
+	if action != domain.ActionPurge {
+		if _, err := uc.repo.ResolveReports(ctx, id, now); err != nil {
+			return domain.Plant{}, err
+		}
+	}
+	return plant, nil
+}
+
+// Log - сценарий использования для получения журнала модерации растения.
+func (uc *ModerateUseCase) Log(ctx context.Context, id int) ([]domain.ModerationEvent, error) {
+	return uc.repo.ModerationLog(ctx, id)
+}
@@ -0,0 +1,145 @@
+package moderate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerateUseCase_Moderate(t *testing.T) {
+	visiblePlant := domain.Plant{ID: 1, Author: "author", Status: domain.StatusVisible, CreatedAt: time.Now()}
+	hiddenPlant := domain.Plant{ID: 1, Author: "author", Status: domain.StatusHidden, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		action         domain.ModerationAction
+		actor          string
+		mockSetup      func(*testutil.MockPlantRepository)
+		expectedError  error
+		expectedStatus domain.Status
+	}{
+		{
+			name:   "hide visible plant",
+			action: domain.ActionHide,
+			actor:  " moderator ",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("Moderate", mock.Anything, mock.MatchedBy(func(e domain.ModerationEvent) bool {
+					return e.PlantID == 1 && e.Action == domain.ActionHide && e.Actor == "moderator" &&
+						e.Reason == "spam" && e.FromStatus == domain.StatusVisible &&
+						e.ToStatus == domain.StatusHidden && !e.CreatedAt.IsZero()
+				})).Return(hiddenPlant, nil)
+				mockRepo.On("ResolveReports", mock.Anything, 1, mock.Anything).Return(2, nil)
+			},
+			expectedStatus: domain.StatusHidden,
+		},
+		{
+			name:   "purge keeps reports to cascade",
+			action: domain.ActionPurge,
+			actor:  "moderator",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(hiddenPlant, nil)
+				mockRepo.On("Moderate", mock.Anything, mock.Anything).Return(hiddenPlant, nil)
+			},
+			expectedStatus: domain.StatusHidden,
+		},
+		{
+			name:   "resolve reports error",
+			action: domain.ActionRestore,
+			actor:  "moderator",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(hiddenPlant, nil)
+				mockRepo.On("Moderate", mock.Anything, mock.Anything).Return(visiblePlant, nil)
+				mockRepo.On("ResolveReports", mock.Anything, 1, mock.Anything).Return(0, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:   "hide already hidden plant",
+			action: domain.ActionHide,
+			actor:  "moderator",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(hiddenPlant, nil)
+			},
+			expectedError: domain.ErrInvalidTransition,
+		},
+		{
+			name:   "plant not found",
+			action: domain.ActionDelete,
+			actor:  "moderator",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:          "anonymous actor",
+			action:        domain.ActionHide,
+			actor:         "  ",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: ErrActorRequired,
+		},
+		{
+			name:   "repository error",
+			action: domain.ActionPurge,
+			actor:  "moderator",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("Moderate", mock.Anything, mock.Anything).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewModerateUseCase(mockRepo)
+
+			// Act
+			plant, err := useCase.Moderate(context.Background(), 1, tt.action, tt.actor, " spam ")
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, plant.Status)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerateUseCase_Log(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+	events := []domain.ModerationEvent{{ID: 1, PlantID: 3, Action: domain.ActionHide, Actor: "moderator"}}
+	mockRepo.On("ModerationLog", mock.Anything, 3).Return(events, nil)
+	useCase := NewModerateUseCase(mockRepo)
+
+	// Act
+	result, err := useCase.Log(context.Background(), 3)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, events, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewModerateUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewModerateUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,56 @@
+package remove
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+)
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByIDAnyStatus(ctx context.Context, id int) (domain.Plant, error)
+	Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error)
+}
+
+// RemoveUseCase - это конкретная реализация бизнес-логики для удаления растения его автором.
+type RemoveUseCase struct {
+	repo PlantRepository
+}
+
+// NewRemoveUseCase - конструктор для RemoveUseCase.
+func NewRemoveUseCase(r PlantRepository) *RemoveUseCase {
+	return &RemoveUseCase{repo: r}
+}
+
+// Remove - сценарий использования для удаления растения по токену владельца.
+// Удаление мягкое (статус deleted) и записывается в журнал модерации от имени domain.OwnerActor,
+// так что модераторы видят, кто убрал растение. Скрытое модератором растение автор тоже может удалить.
+// Если растения нет или оно уже удалено, возвращается domain.ErrNotFound, с чужим токеном - domain.ErrForbidden.
+func (uc *RemoveUseCase) Remove(ctx context.Context, id int, token string) error {
+	plant, err := uc.repo.GetByIDAnyStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	if plant.Status == domain.StatusDeleted {
+		return domain.ErrNotFound
+	}
+	if !plant.IsOwner(token) {
+		return domain.ErrForbidden
+	}
+
+	_, err = uc.repo.Moderate(ctx, domain.ModerationEvent{
+		PlantID:    id,
+		Action:     domain.ActionDelete,
+		Actor:      domain.OwnerActor,
+		FromStatus: plant.Status,
+		ToStatus:   domain.StatusDeleted,
+		CreatedAt:  time.Now().UTC(),
+	})
+	// Растение успели удалить или изменить параллельно: для автора это то же, что его уже нет.
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		return domain.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package remove
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoveUseCase_Remove(t *testing.T) {
+	token := "owner-token"
+	owned := func(status domain.Status) domain.Plant {
+		return domain.Plant{ID: 1, Status: status, OwnerTokenHash: domain.HashOwnerToken(token)}
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		mockSetup     func(*testutil.MockPlantRepository)
+		expectedError error
+	}{
+		{
+			name:  "owner deletes visible plant",
+			token: token,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(owned(domain.StatusVisible), nil)
+				mockRepo.On("Moderate", mock.Anything, mock.MatchedBy(func(e domain.ModerationEvent) bool {
+					return e.PlantID == 1 && e.Action == domain.ActionDelete && e.Actor == domain.OwnerActor &&
+						e.FromStatus == domain.StatusVisible && e.ToStatus == domain.StatusDeleted
+				})).Return(owned(domain.StatusDeleted), nil)
+			},
+		},
+		{
+			name:  "owner deletes hidden plant",
+			token: token,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(owned(domain.StatusHidden), nil)
+				mockRepo.On("Moderate", mock.Anything, mock.MatchedBy(func(e domain.ModerationEvent) bool {
+					return e.FromStatus == domain.StatusHidden
+				})).Return(owned(domain.StatusDeleted), nil)
+			},
+		},
+		{
+			name:  "already deleted",
+			token: token,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(owned(domain.StatusDeleted), nil)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:  "deleted concurrently",
+			token: token,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(owned(domain.StatusVisible), nil)
+				mockRepo.On("Moderate", mock.Anything, mock.Anything).Return(domain.Plant{}, domain.ErrInvalidTransition)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:  "wrong token",
+			token: "someone-else",
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(owned(domain.StatusVisible), nil)
+			},
+			expectedError: domain.ErrForbidden,
+		},
+		{
+			name:  "repository error",
+			token: token,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByIDAnyStatus", mock.Anything, 1).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewRemoveUseCase(mockRepo)
+
+			// Act
+			err := useCase.Remove(context.Background(), 1, tt.token)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewRemoveUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewRemoveUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// DefaultThreshold - сколько открытых жалоб скрывает растение, если порог не задан.
+const DefaultThreshold = 3
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+	CreateReport(ctx context.Context, report domain.Report) (int, error)
+	Moderate(ctx context.Context, event domain.ModerationEvent) (domain.Plant, error)
+	ReportQueue(ctx context.Context, limit int) ([]domain.ReportSummary, error)
+	ResolveReports(ctx context.Context, plantID int, at time.Time) (int, error)
+}
+
+// ReportUseCase - это конкретная реализация бизнес-логики жалоб на растения.
+type ReportUseCase struct {
+	repo      PlantRepository
+	threshold int
+	secret    []byte
+}
+
+// Option настраивает ReportUseCase.
+type Option func(*ReportUseCase)
+
+// WithThreshold задает число открытых жалоб, после которого растение скрывается до решения модератора.
+// Значение меньше 1 отключает автоматическое скрытие.
+func WithThreshold(threshold int) Option {
+	return func(uc *ReportUseCase) {
+		uc.threshold = threshold
+	}
+}
+
+// WithReporterSecret задает ключ HMAC, которым хэшируется идентификатор посетителя.
+// Без ключа хэш IP-адреса легко подобрать перебором, поэтому пустой ключ заменяется случайным (см. NewReportUseCase).
+func WithReporterSecret(secret string) Option {
+	return func(uc *ReportUseCase) {
+		uc.secret = []byte(secret)
+	}
+}
+
+// NewReportUseCase - конструктор для ReportUseCase.
+// Если ключ HMAC не задан, создается случайный: хэши остаются стойкими к перебору,
+// но повторная жалоба после перезапуска или на другой реплике уже не распознается.
+func NewReportUseCase(r PlantRepository, opts ...Option) *ReportUseCase {
+	uc := &ReportUseCase{repo: r, threshold: DefaultThreshold}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	if len(uc.secret) == 0 {
+		uc.secret = make([]byte, 32)
+		rand.Read(uc.secret)
+	}
+	return uc
+}
+
+// Report - сценарий использования для жалобы посетителя на растение.
+// reporter - идентификатор посетителя (адрес или сессия); сохраняется только его хэш.
+// Повторная жалоба того же посетителя возвращает domain.ErrAlreadyReported.
+// Когда число открытых жалоб достигает порога, растение скрывается от имени domain.ReportsActor.
+func (uc *ReportUseCase) Report(ctx context.Context, id int, reason domain.ReportReason, reporter string) error {
+	if _, err := domain.ParseReportReason(string(reason)); err != nil {
+		return err
+	}
+
+	// Жаловаться можно только на растения, которые видно в лесу.
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	open, err := uc.repo.CreateReport(ctx, domain.Report{
+		PlantID:      id,
+		Reason:       reason,
+		ReporterHash: uc.hashReporter(reporter),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if uc.threshold < 1 || open < uc.threshold {
+		return nil
+	}
+
+	_, err = uc.repo.Moderate(ctx, domain.ModerationEvent{
+		PlantID:    id,
+		Action:     domain.ActionHide,
+		Actor:      domain.ReportsActor,
+		Reason:     fmt.Sprintf("%d open reports", open),
+		FromStatus: domain.StatusVisible,
+		ToStatus:   domain.StatusHidden,
+		CreatedAt:  now,
+	})
+	// Растение уже скрыли параллельная жалоба или модератор - жалоба все равно принята.
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("plant hidden by reports", slog.Int("open_reports", open))
+	return nil
+}
+
+// Queue - сценарий использования для очереди модерации: растения с открытыми жалобами.
+func (uc *ReportUseCase) Queue(ctx context.Context, limit int) ([]domain.ReportSummary, error) {
+	if limit <= 0 {
+		return []domain.ReportSummary{}, nil
+	}
+	return uc.repo.ReportQueue(ctx, limit)
+}
+
+// Resolve - сценарий использования для закрытия жалоб без изменения статуса растения,
+// например если скрытое по жалобам растение решено оставить скрытым. Возвращает число закрытых жалоб.
+func (uc *ReportUseCase) Resolve(ctx context.Context, id int) (int, error) {
+	return uc.repo.ResolveReports(ctx, id, time.Now().UTC())
+}
+
+// hashReporter превращает идентификатор посетителя в HMAC-SHA256.
+func (uc *ReportUseCase) hashReporter(reporter string) string {
+	mac := hmac.New(sha256.New, uc.secret)
+	mac.Write([]byte(reporter))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,187 @@
+package report
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportUseCase_Report(t *testing.T) {
+	visiblePlant := domain.Plant{ID: 1, Author: "author", Status: domain.StatusVisible}
+	hideEvent := mock.MatchedBy(func(e domain.ModerationEvent) bool {
+		return e.PlantID == 1 && e.Action == domain.ActionHide && e.Actor == domain.ReportsActor &&
+			e.FromStatus == domain.StatusVisible && e.ToStatus == domain.StatusHidden
+	})
+
+	tests := []struct {
+		name          string
+		reason        domain.ReportReason
+		mockSetup     func(*testutil.MockPlantRepository)
+		expectedError error
+	}{
+		{
+			name:   "below threshold",
+			reason: domain.ReasonSpam,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("CreateReport", mock.Anything, mock.MatchedBy(func(r domain.Report) bool {
+					return r.PlantID == 1 && r.Reason == domain.ReasonSpam && len(r.ReporterHash) == 64 &&
+						r.ReporterHash != "ip:203.0.113.7"
+				})).Return(1, nil)
+			},
+		},
+		{
+			name:   "threshold reached hides plant",
+			reason: domain.ReasonOffensive,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("CreateReport", mock.Anything, mock.Anything).Return(2, nil)
+				mockRepo.On("Moderate", mock.Anything, hideEvent).Return(visiblePlant, nil)
+			},
+		},
+		{
+			name:   "plant already hidden concurrently",
+			reason: domain.ReasonOffensive,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("CreateReport", mock.Anything, mock.Anything).Return(3, nil)
+				mockRepo.On("Moderate", mock.Anything, hideEvent).Return(domain.Plant{}, domain.ErrInvalidTransition)
+			},
+		},
+		{
+			name:   "hide error",
+			reason: domain.ReasonOffensive,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("CreateReport", mock.Anything, mock.Anything).Return(2, nil)
+				mockRepo.On("Moderate", mock.Anything, hideEvent).Return(domain.Plant{}, assert.AnError)
+			},
+			expectedError: assert.AnError,
+		},
+		{
+			name:   "duplicate report",
+			reason: domain.ReasonSpam,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(visiblePlant, nil)
+				mockRepo.On("CreateReport", mock.Anything, mock.Anything).Return(0, domain.ErrAlreadyReported)
+			},
+			expectedError: domain.ErrAlreadyReported,
+		},
+		{
+			name:   "plant not visible",
+			reason: domain.ReasonSpam,
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:          "invalid reason",
+			reason:        "boring",
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: domain.ErrInvalidReportReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewReportUseCase(mockRepo, WithThreshold(2), WithReporterSecret("secret"))
+
+			// Act
+			err := useCase.Report(context.Background(), 1, tt.reason, "ip:203.0.113.7")
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportUseCase_ReportWithoutThreshold(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+	mockRepo.On("GetByID", mock.Anything, 1).Return(domain.Plant{ID: 1}, nil)
+	mockRepo.On("CreateReport", mock.Anything, mock.Anything).Return(100, nil)
+	useCase := NewReportUseCase(mockRepo, WithThreshold(0))
+
+	// Act
+	err := useCase.Report(context.Background(), 1, domain.ReasonSpam, "ip:203.0.113.7")
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Moderate", mock.Anything, mock.Anything)
+}
+
+func TestReportUseCase_HashReporter(t *testing.T) {
+	a := NewReportUseCase(nil, WithReporterSecret("a"))
+	b := NewReportUseCase(nil, WithReporterSecret("b"))
+
+	assert.Equal(t, a.hashReporter("ip:203.0.113.7"), a.hashReporter("ip:203.0.113.7"))
+	assert.NotEqual(t, a.hashReporter("ip:203.0.113.7"), a.hashReporter("ip:203.0.113.8"))
+	assert.NotEqual(t, a.hashReporter("ip:203.0.113.7"), b.hashReporter("ip:203.0.113.7"))
+
+	// Без ключа хэш не должен совпадать с хэшем без секрета, который легко подобрать перебором
+	unkeyed := hmac.New(sha256.New, nil)
+	unkeyed.Write([]byte("ip:203.0.113.7"))
+	c := NewReportUseCase(nil, WithReporterSecret(""))
+	assert.NotEqual(t, hex.EncodeToString(unkeyed.Sum(nil)), c.hashReporter("ip:203.0.113.7"))
+	assert.NotEqual(t, c.hashReporter("ip:203.0.113.7"), NewReportUseCase(nil).hashReporter("ip:203.0.113.7"))
+}
+
+func TestReportUseCase_Queue(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+	queue := []domain.ReportSummary{{PlantID: 1, Count: 3}}
+	mockRepo.On("ReportQueue", mock.Anything, 50).Return(queue, nil)
+	useCase := NewReportUseCase(mockRepo)
+
+	// Act
+	result, err := useCase.Queue(context.Background(), 50)
+	empty, emptyErr := useCase.Queue(context.Background(), 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, queue, result)
+	assert.NoError(t, emptyErr)
+	assert.Empty(t, empty)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_Resolve(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockPlantRepository()
+	mockRepo.On("ResolveReports", mock.Anything, 1, mock.Anything).Return(4, nil)
+	useCase := NewReportUseCase(mockRepo)
+
+	// Act
+	resolved, err := useCase.Resolve(context.Background(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 4, resolved)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewReportUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewReportUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+	assert.Equal(t, DefaultThreshold, useCase.threshold)
+}
@@ -0,0 +1,32 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+)
+
+// Broker определяет контракт для источника событий о новых растениях.
+type Broker interface {
+	Subscribe() (*events.Subscription, error)
+}
+
+// StreamUseCase - это конкретная реализация бизнес-логики для наблюдения за новыми растениями.
+type StreamUseCase struct {
+	broker Broker
+}
+
+// NewStreamUseCase - конструктор для StreamUseCase.
+func NewStreamUseCase(b Broker) *StreamUseCase {
+	return &StreamUseCase{broker: b}
+}
+
+// Subscribe - сценарий использования для подписки на новые растения.
+// Подписку нужно закрыть, когда клиент отключился. Если сервер останавливается,
+// возвращается events.ErrBusClosed.
+func (uc *StreamUseCase) Subscribe(ctx context.Context) (*events.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return uc.broker.Subscribe()
+}
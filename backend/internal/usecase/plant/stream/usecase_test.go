@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamUseCase_Subscribe(t *testing.T) {
+	t.Run("receives published plants", func(t *testing.T) {
+		// Arrange
+		bus := events.NewPlantBus(1)
+		useCase := NewStreamUseCase(bus)
+
+		// Act
+		sub, err := useCase.Subscribe(context.Background())
+		require.NoError(t, err)
+		defer sub.Close()
+		bus.Publish(domain.Plant{ID: 5})
+
+		// Assert
+		assert.Equal(t, 5, (<-sub.C).ID)
+	})
+
+	t.Run("bus closed", func(t *testing.T) {
+		bus := events.NewPlantBus(1)
+		bus.Close()
+
+		_, err := NewStreamUseCase(bus).Subscribe(context.Background())
+
+		assert.ErrorIs(t, err, events.ErrBusClosed)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewStreamUseCase(events.NewPlantBus(1)).Subscribe(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestNewStreamUseCase(t *testing.T) {
+	bus := events.NewPlantBus(1)
+	useCase := NewStreamUseCase(bus)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, bus, useCase.broker)
+}
@@ -0,0 +1,123 @@
+package update
+
+import (
+	"context"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	duplicatesUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/plant/duplicates"
+)
+
+// PlantRepository определяет контракт для слоя данных.
+type PlantRepository interface {
+	GetByID(ctx context.Context, id int) (domain.Plant, error)
+	Update(ctx context.Context, id int, update domain.PlantUpdate) (domain.Plant, error)
+	FindDuplicate(ctx context.Context, fp domain.Fingerprint, maxDistance, excludeID int) (domain.Duplicate, error)
+}
+
+// Request - изменения, которые запросил автор. Пустые поля не меняются;
+// изображение передается либо как base64 PNG (ImageData), либо как сетка (Grid).
+type Request struct {
+	Author    *string
+	ImageData string
+	Grid      *domain.Grid
+}
+
+// AuthorChecker проверяет нормализованное имя автора; запрещенное имя - domain.ErrAuthorBlocked.
+type AuthorChecker interface {
+	CheckAuthor(name string) error
+}
+
+// UpdateUseCase - это конкретная реализация бизнес-логики для изменения растения его автором.
+type UpdateUseCase struct {
+	repo    PlantRepository
+	limits  domain.ImageLimits
+	authors AuthorChecker
+	// duplicates - что делать, если новое изображение похоже на другое растение.
+	duplicates duplicatesUseCase.Policy
+}
+
+// Option настраивает UpdateUseCase.
+type Option func(*UpdateUseCase)
+
+// WithAuthorChecker задает проверку новых имен авторов - ту же, что при посадке.
+func WithAuthorChecker(c AuthorChecker) Option {
+	return func(uc *UpdateUseCase) {
+		uc.authors = c
+	}
+}
+
+// WithDuplicateCheck задает поиск дубликатов для нового изображения - тот же, что при посадке.
+// Само изменяемое растение с новым изображением не сравнивается.
+func WithDuplicateCheck(mode domain.DuplicateMode, maxDistance int) Option {
+	return func(uc *UpdateUseCase) {
+		uc.duplicates = duplicatesUseCase.Policy{Mode: mode, MaxDistance: maxDistance}
+	}
+}
+
+// NewUpdateUseCase - конструктор для UpdateUseCase.
+// По умолчанию дубликаты не ищутся.
+func NewUpdateUseCase(r PlantRepository, opts ...Option) *UpdateUseCase {
+	uc := &UpdateUseCase{repo: r, limits: domain.DefaultImageLimits, duplicates: duplicatesUseCase.Policy{Mode: domain.DuplicateOff}}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Update - сценарий использования для изменения имени автора или изображения растения.
+// Изменять можно только видимые растения и только с токеном владельца, выданным при создании:
+// без растения возвращается domain.ErrNotFound, с чужим токеном - domain.ErrForbidden.
+// Имя и изображение проверяются так же, как при создании (ошибки domain.ErrAuthor*, domain.ErrImage* и domain.ErrGridInvalid).
+// Новое изображение, похожее на другое растение, в режиме DuplicateReject отклоняется с domain.ErrDuplicate,
+// а в режиме DuplicateFlag растение уходит в статус StatusPending и ждет модератора.
+func (uc *UpdateUseCase) Update(ctx context.Context, id int, token string, req Request) (domain.Plant, error) {
+	if req.Author == nil && req.ImageData == "" && req.Grid == nil {
+		return domain.Plant{}, domain.ErrEmptyUpdate
+	}
+
+	plant, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Plant{}, err
+	}
+	if !plant.IsOwner(token) {
+		return domain.Plant{}, domain.ErrForbidden
+	}
+
+	update := domain.PlantUpdate{Author: req.Author, Grid: req.Grid}
+	if req.Author != nil {
+		author, err := domain.NormalizeAuthor(*req.Author)
+		if err != nil {
+			return domain.Plant{}, err
+		}
+		if uc.authors != nil {
+			if err := uc.authors.CheckAuthor(author); err != nil {
+				return domain.Plant{}, err
+			}
+		}
+		update.Author = &author
+	}
+	switch {
+	case req.Grid != nil:
+		if err := req.Grid.Validate(uc.limits); err != nil {
+			return domain.Plant{}, err
+		}
+	case req.ImageData != "":
+		img, err := domain.DecodeImage(req.ImageData, uc.limits)
+		if err != nil {
+			return domain.Plant{}, err
+		}
+		grid, err := domain.GridFromImage(img, uc.limits.CellSize)
+		if err != nil {
+			return domain.Plant{}, err
+		}
+		update.Grid = &grid
+	}
+	if update.Grid != nil {
+		update.Fingerprint = update.Grid.Fingerprint()
+		if update.Status, err = uc.duplicates.Check(ctx, uc.repo, update.Fingerprint, id); err != nil {
+			return domain.Plant{}, err
+		}
+	}
+
+	return uc.repo.Update(ctx, id, update)
+}
@@ -0,0 +1,232 @@
+package update
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	domain "github.com/heartmarshall/digital-forest/backend/internal/domain/plant"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUseCase_Update(t *testing.T) {
+	token := "owner-token"
+	owned := domain.Plant{ID: 1, Author: "old", Status: domain.StatusVisible, OwnerTokenHash: domain.HashOwnerToken(token)}
+	newAuthor := "new"
+	grid := domain.Grid{Width: 1, Height: 1, Palette: []color.NRGBA{{R: 255, A: 255}}, Pixels: []uint8{0}}
+
+	tests := []struct {
+		name          string
+		token         string
+		req           Request
+		mockSetup     func(*testutil.MockPlantRepository)
+		expectedError error
+	}{
+		{
+			name:  "rename",
+			token: token,
+			req:   Request{Author: &newAuthor},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+				mockRepo.On("Update", mock.Anything, 1, domain.PlantUpdate{Author: &newAuthor}).
+					Return(domain.Plant{ID: 1, Author: newAuthor}, nil)
+			},
+		},
+		{
+			name:  "replace image with grid",
+			token: token,
+			req:   Request{Grid: &grid},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+				mockRepo.On("Update", mock.Anything, 1, domain.PlantUpdate{Grid: &grid, Fingerprint: grid.Fingerprint()}).
+					Return(domain.Plant{ID: 1, ImageVersion: 2}, nil)
+			},
+		},
+		{
+			name:  "replace image with png",
+			token: token,
+			req:   Request{ImageData: testutil.GenerateImageData(1)},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+				mockRepo.On("Update", mock.Anything, 1, mock.MatchedBy(func(u domain.PlantUpdate) bool {
+					return u.Author == nil && u.Grid != nil && u.Grid.Width == 16 && u.Fingerprint == u.Grid.Fingerprint()
+				})).Return(domain.Plant{ID: 1, ImageVersion: 2}, nil)
+			},
+		},
+		{
+			name:  "invalid png",
+			token: token,
+			req:   Request{ImageData: "not-base64"},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			},
+			expectedError: domain.ErrImageEncoding,
+		},
+		{
+			name:  "invalid grid",
+			token: token,
+			req:   Request{Grid: &domain.Grid{Width: 2, Height: 2}},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			},
+			expectedError: domain.ErrGridInvalid,
+		},
+		{
+			name:  "wrong token",
+			token: "someone-else",
+			req:   Request{Author: &newAuthor},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			},
+			expectedError: domain.ErrForbidden,
+		},
+		{
+			name:  "plant not found",
+			token: token,
+			req:   Request{Author: &newAuthor},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(domain.Plant{}, domain.ErrNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:  "rename normalized",
+			token: token,
+			req:   Request{Author: ptr("  new\u00a0 ")},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+				mockRepo.On("Update", mock.Anything, 1, domain.PlantUpdate{Author: &newAuthor}).
+					Return(domain.Plant{ID: 1, Author: newAuthor}, nil)
+			},
+		},
+		{
+			name:  "blank author",
+			token: token,
+			req:   Request{Author: ptr(" \u200b ")},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			},
+			expectedError: domain.ErrAuthorEmpty,
+		},
+		{
+			name:  "blocked author",
+			token: token,
+			req:   Request{Author: ptr("Admin")},
+			mockSetup: func(mockRepo *testutil.MockPlantRepository) {
+				mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			},
+			expectedError: domain.ErrAuthorBlocked,
+		},
+		{
+			name:          "nothing to update",
+			token:         token,
+			mockSetup:     func(mockRepo *testutil.MockPlantRepository) {},
+			expectedError: domain.ErrEmptyUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockPlantRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewUpdateUseCase(mockRepo, WithAuthorChecker(blockedNames{"Admin"}))
+
+			// Act
+			plant, err := useCase.Update(context.Background(), 1, tt.token, tt.req)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 1, plant.ID)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateUseCase_Duplicates(t *testing.T) {
+	token := "owner-token"
+	owned := domain.Plant{ID: 1, Status: domain.StatusVisible, OwnerTokenHash: domain.HashOwnerToken(token)}
+	grid := domain.Grid{
+		Width:   2,
+		Height:  2,
+		Palette: []color.NRGBA{{}, {R: 34, G: 139, B: 34, A: 255}},
+		Pixels:  []uint8{0, 1, 1, 0},
+	}
+	fingerprint := grid.Fingerprint()
+	duplicate := domain.Duplicate{PlantID: 3, Exact: true}
+
+	tests := []struct {
+		name           string
+		mode           domain.DuplicateMode
+		found          error
+		expectedStatus domain.Status
+		expectedError  error
+	}{
+		{name: "check disabled", mode: domain.DuplicateOff},
+		{name: "no duplicate", mode: domain.DuplicateReject, found: domain.ErrNotFound},
+		{name: "duplicate rejected", mode: domain.DuplicateReject, expectedError: domain.ErrDuplicate},
+		{name: "duplicate flagged", mode: domain.DuplicateFlag, expectedStatus: domain.StatusPending},
+		{name: "lookup error", mode: domain.DuplicateFlag, found: assert.AnError, expectedError: assert.AnError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: изменяемое растение само с собой не сравнивается
+			mockRepo := testutil.NewMockPlantRepository()
+			mockRepo.On("GetByID", mock.Anything, 1).Return(owned, nil)
+			if tt.mode != domain.DuplicateOff {
+				mockRepo.On("FindDuplicate", mock.Anything, fingerprint, 4, 1).Return(duplicate, tt.found)
+			}
+			if tt.expectedError == nil {
+				mockRepo.On("Update", mock.Anything, 1, domain.PlantUpdate{Grid: &grid, Fingerprint: fingerprint, Status: tt.expectedStatus}).
+					Return(domain.Plant{ID: 1, Status: tt.expectedStatus}, nil)
+			}
+			useCase := NewUpdateUseCase(mockRepo, WithDuplicateCheck(tt.mode, 4))
+
+			// Act
+			plant, err := useCase.Update(context.Background(), 1, token, Request{Grid: &grid})
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, plant)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, plant.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// blockedNames - AuthorChecker, запрещающий перечисленные имена.
+type blockedNames []string
+
+func (b blockedNames) CheckAuthor(name string) error {
+	for _, blocked := range b {
+		if name == blocked {
+			return domain.ErrAuthorBlocked
+		}
+	}
+	return nil
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestNewUpdateUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockPlantRepository()
+	useCase := NewUpdateUseCase(mockRepo)
+
+	assert.NotNil(t, useCase)
+	assert.Equal(t, mockRepo, useCase.repo)
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+)
+
+// UserRepository определяет контракт для слоя данных.
+type UserRepository interface {
+	CreateUser(ctx context.Context, u userDomain.User) (userDomain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (userDomain.User, error)
+	CreateSession(ctx context.Context, s userDomain.Session) error
+	GetSession(ctx context.Context, tokenHash string) (userDomain.Session, userDomain.User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+}
+
+// Login - результат успешного входа.
+type Login struct {
+	User    userDomain.User
+	Session userDomain.Session
+	// Token - токен сессии для cookie. В базе хранится только его хэш.
+	Token string
+}
+
+// AuthUseCase - это конкретная реализация регистрации, входа и серверных сессий.
+type AuthUseCase struct {
+	repo UserRepository
+	ttl  time.Duration
+}
+
+// Option настраивает AuthUseCase.
+type Option func(*AuthUseCase)
+
+// WithSessionTTL задает время жизни сессии. Значение меньше или равное нулю оставляет userDomain.DefaultSessionTTL.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(uc *AuthUseCase) {
+		if ttl > 0 {
+			uc.ttl = ttl
+		}
+	}
+}
+
+// NewAuthUseCase - конструктор для AuthUseCase.
+func NewAuthUseCase(r UserRepository, opts ...Option) *AuthUseCase {
+	uc := &AuthUseCase{repo: r, ttl: userDomain.DefaultSessionTTL}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Register - сценарий использования для регистрации пользователя.
+// Некорректное имя приводит к userDomain.ErrInvalidUsername, занятое - к userDomain.ErrUsernameTaken.
+func (uc *AuthUseCase) Register(ctx context.Context, username, password string) (userDomain.User, error) {
+	username, err := userDomain.NormalizeUsername(username)
+	if err != nil {
+		return userDomain.User{}, err
+	}
+
+	hash, err := userDomain.HashPassword(password)
+	if err != nil {
+		return userDomain.User{}, err
+	}
+
+	return uc.repo.CreateUser(ctx, userDomain.User{
+		Username:     username,
+		PasswordHash: hash,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// Login - сценарий использования для входа: проверяет пароль и открывает новую сессию.
+// Неизвестное имя и неверный пароль одинаково возвращают userDomain.ErrInvalidCredentials
+// и занимают одинаковое время.
+func (uc *AuthUseCase) Login(ctx context.Context, username, password string) (Login, error) {
+	u, err := uc.repo.GetUserByUsername(ctx, username)
+	if errors.Is(err, userDomain.ErrNotFound) {
+		userDomain.CheckNoPassword(password)
+		return Login{}, userDomain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return Login{}, err
+	}
+	if u.PasswordHash == "" {
+		// Пользователи, созданные входом через провайдера OpenID Connect, пароля не имеют.
+		userDomain.CheckNoPassword(password)
+		return Login{}, userDomain.ErrInvalidCredentials
+	}
+	if !userDomain.CheckPassword(u.PasswordHash, password) {
+		return Login{}, userDomain.ErrInvalidCredentials
+	}
+
+	session, token, err := userDomain.NewSession(u.ID, time.Now().UTC(), uc.ttl)
+	if err != nil {
+		return Login{}, err
+	}
+	if err := uc.repo.CreateSession(ctx, session); err != nil {
+		return Login{}, err
+	}
+
+	return Login{User: u, Session: session, Token: token}, nil
+}
+
+// Logout - сценарий использования для выхода: сессия удаляется на сервере.
+func (uc *AuthUseCase) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return uc.repo.DeleteSession(ctx, userDomain.HashSessionToken(token))
+}
+
+// Authenticate возвращает действующую сессию по токену из cookie вместе с ее пользователем.
+// Если сессии нет или она истекла, возвращается userDomain.ErrSessionNotFound.
+func (uc *AuthUseCase) Authenticate(ctx context.Context, token string) (userDomain.Session, userDomain.User, error) {
+	if token == "" {
+		return userDomain.Session{}, userDomain.User{}, userDomain.ErrSessionNotFound
+	}
+	return uc.repo.GetSession(ctx, userDomain.HashSessionToken(token))
+}
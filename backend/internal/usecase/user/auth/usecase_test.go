@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthUseCase_Register(t *testing.T) {
+	tests := []struct {
+		name          string
+		username      string
+		mockSetup     func(*testutil.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:     "successful registration",
+			username: " Alice ",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u userDomain.User) bool {
+					return u.Username == "Alice" && userDomain.CheckPassword(u.PasswordHash, "correct horse")
+				})).Return(userDomain.User{ID: 1, Username: "Alice"}, nil)
+			},
+		},
+		{
+			name:          "invalid username",
+			username:      "a b",
+			mockSetup:     func(mockRepo *testutil.MockUserRepository) {},
+			expectedError: userDomain.ErrInvalidUsername,
+		},
+		{
+			name:     "username taken",
+			username: "alice",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("CreateUser", mock.Anything, mock.Anything).Return(userDomain.User{}, userDomain.ErrUsernameTaken)
+			},
+			expectedError: userDomain.ErrUsernameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockUserRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewAuthUseCase(mockRepo)
+
+			// Act
+			u, err := useCase.Register(context.Background(), tt.username, "correct horse")
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, u.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_Login(t *testing.T) {
+	hash, err := userDomain.HashPassword("correct horse")
+	require.NoError(t, err)
+	alice := userDomain.User{ID: 7, Username: "alice", PasswordHash: hash}
+
+	tests := []struct {
+		name          string
+		password      string
+		mockSetup     func(*testutil.MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:     "successful login",
+			password: "correct horse",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(alice, nil)
+				mockRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s userDomain.Session) bool {
+					return s.UserID == 7 && s.CSRFToken != "" && s.ExpiresAt.Sub(s.CreatedAt) == time.Hour
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "wrong password",
+			password: "wrong horse",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(alice, nil)
+			},
+			expectedError: userDomain.ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			password: "correct horse",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(userDomain.User{}, userDomain.ErrNotFound)
+			},
+			expectedError: userDomain.ErrInvalidCredentials,
+		},
+		{
+			name:     "user without password",
+			password: "",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(userDomain.User{ID: 7, Username: "alice"}, nil)
+			},
+			expectedError: userDomain.ErrInvalidCredentials,
+		},
+		{
+			name:     "session error",
+			password: "correct horse",
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("GetUserByUsername", mock.Anything, "alice").Return(alice, nil)
+				mockRepo.On("CreateSession", mock.Anything, mock.Anything).Return(errors.New("db down"))
+			},
+			expectedError: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockUserRepository()
+			tt.mockSetup(mockRepo)
+			useCase := NewAuthUseCase(mockRepo, WithSessionTTL(time.Hour))
+
+			// Act
+			login, err := useCase.Login(context.Background(), "alice", tt.password)
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 7, login.User.ID)
+				assert.Equal(t, userDomain.HashSessionToken(login.Token), login.Session.TokenHash)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthUseCase_Authenticate(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockUserRepository()
+	session := userDomain.Session{TokenHash: userDomain.HashSessionToken("token"), UserID: 7}
+	mockRepo.On("GetSession", mock.Anything, session.TokenHash).Return(session, userDomain.User{ID: 7}, nil)
+	useCase := NewAuthUseCase(mockRepo)
+
+	// Act
+	found, u, err := useCase.Authenticate(context.Background(), "token")
+	_, _, emptyErr := useCase.Authenticate(context.Background(), "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, session, found)
+	assert.Equal(t, 7, u.ID)
+	assert.ErrorIs(t, emptyErr, userDomain.ErrSessionNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockUserRepository()
+	mockRepo.On("DeleteSession", mock.Anything, userDomain.HashSessionToken("token")).Return(nil)
+	useCase := NewAuthUseCase(mockRepo)
+
+	// Act & Assert
+	assert.NoError(t, useCase.Logout(context.Background(), "token"))
+	assert.NoError(t, useCase.Logout(context.Background(), ""), "no cookie - nothing to delete")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewAuthUseCase(t *testing.T) {
+	mockRepo := testutil.NewMockUserRepository()
+
+	assert.Equal(t, userDomain.DefaultSessionTTL, NewAuthUseCase(mockRepo).ttl)
+	assert.Equal(t, userDomain.DefaultSessionTTL, NewAuthUseCase(mockRepo, WithSessionTTL(0)).ttl)
+	assert.Equal(t, time.Hour, NewAuthUseCase(mockRepo, WithSessionTTL(time.Hour)).ttl)
+}
@@ -0,0 +1,244 @@
+package oidc_login
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/oidc"
+	authUseCase "github.com/heartmarshall/digital-forest/backend/internal/usecase/user/auth"
+	"github.com/heartmarshall/digital-forest/backend/pkg/cerror"
+)
+
+// ErrUnknownProvider - провайдер с таким именем не настроен.
+var ErrUnknownProvider = cerror.New(cerror.NotFound, "unknown oidc provider")
+
+// ErrLoginFailed - провайдер не подтвердил вход: код не обменялся, ID-токен или nonce не прошли проверку.
+var ErrLoginFailed = cerror.New(cerror.Unauthorized, "oidc login failed")
+
+// usernameAttempts - сколько имен с числовыми суффиксами пробуется для нового пользователя,
+// если имя из ID-токена уже занято.
+const usernameAttempts = 5
+
+// UserRepository определяет контракт для слоя данных.
+type UserRepository interface {
+	CreateUser(ctx context.Context, u userDomain.User) (userDomain.User, error)
+	GetUserByID(ctx context.Context, id int) (userDomain.User, error)
+	CreateSession(ctx context.Context, s userDomain.Session) error
+	GetIdentity(ctx context.Context, issuer, subject string) (userDomain.Identity, error)
+	CreateIdentity(ctx context.Context, id userDomain.Identity) error
+	CreateLoginState(ctx context.Context, s userDomain.LoginState) error
+	ConsumeLoginState(ctx context.Context, state string) (userDomain.LoginState, error)
+}
+
+// Provider - провайдер OpenID Connect (см. oidc.Provider).
+type Provider interface {
+	Name() string
+	AuthCodeURL(state, nonce, verifier string) string
+	Exchange(ctx context.Context, code, verifier string) (oidc.Claims, error)
+}
+
+// OIDCLoginUseCase - это конкретная реализация входа через внешних провайдеров OpenID Connect.
+type OIDCLoginUseCase struct {
+	repo      UserRepository
+	providers map[string]Provider
+	ttl       time.Duration
+}
+
+// Option настраивает OIDCLoginUseCase.
+type Option func(*OIDCLoginUseCase)
+
+// WithSessionTTL задает время жизни сессии. Значение меньше или равное нулю оставляет userDomain.DefaultSessionTTL.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(uc *OIDCLoginUseCase) {
+		if ttl > 0 {
+			uc.ttl = ttl
+		}
+	}
+}
+
+// NewOIDCLoginUseCase - конструктор для OIDCLoginUseCase.
+func NewOIDCLoginUseCase(r UserRepository, providers []Provider, opts ...Option) *OIDCLoginUseCase {
+	uc := &OIDCLoginUseCase{
+		repo:      r,
+		providers: make(map[string]Provider, len(providers)),
+		ttl:       userDomain.DefaultSessionTTL,
+	}
+	for _, p := range providers {
+		uc.providers[p.Name()] = p
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Providers возвращает имена настроенных провайдеров по алфавиту.
+func (uc *OIDCLoginUseCase) Providers() []string {
+	names := make([]string, 0, len(uc.providers))
+	for name := range uc.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Begin - начало входа через провайдера. Возвращает адрес провайдера и state,
+// который нужно привязать к браузеру, чтобы callback нельзя было подсунуть чужому посетителю.
+// linkUserID - уже вошедший пользователь, к которому привязывается учетная запись провайдера, или 0.
+func (uc *OIDCLoginUseCase) Begin(ctx context.Context, provider string, linkUserID int) (string, string, error) {
+	p, ok := uc.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	s := userDomain.LoginState{
+		State:        rand.Text(),
+		Provider:     provider,
+		Nonce:        rand.Text(),
+		CodeVerifier: oauth2.GenerateVerifier(),
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().UTC().Add(userDomain.LoginStateTTL),
+	}
+	if err := uc.repo.CreateLoginState(ctx, s); err != nil {
+		return "", "", err
+	}
+
+	return p.AuthCodeURL(s.State, s.Nonce, s.CodeVerifier), s.State, nil
+}
+
+// Complete - завершение входа по коду, с которым провайдер вернул браузер.
+// Учетная запись провайдера ищется по (iss, sub). Если она еще не привязана, то привязывается
+// к пользователю, начавшему вход, а при анонимном входе для нее создается новый пользователь без пароля.
+func (uc *OIDCLoginUseCase) Complete(ctx context.Context, provider, state, code string) (authUseCase.Login, error) {
+	s, err := uc.repo.ConsumeLoginState(ctx, state)
+	if err != nil {
+		return authUseCase.Login{}, err
+	}
+	if s.Provider != provider {
+		return authUseCase.Login{}, userDomain.ErrLoginStateNotFound
+	}
+	p, ok := uc.providers[provider]
+	if !ok {
+		return authUseCase.Login{}, ErrUnknownProvider
+	}
+
+	claims, err := p.Exchange(ctx, code, s.CodeVerifier)
+	if err != nil {
+		return authUseCase.Login{}, fmt.Errorf("%w: %v", ErrLoginFailed, err)
+	}
+	// nonce защищает от повторного использования ID-токена, выданного для другого входа.
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(s.Nonce)) != 1 {
+		return authUseCase.Login{}, fmt.Errorf("%w: nonce mismatch", ErrLoginFailed)
+	}
+
+	u, err := uc.resolveUser(ctx, s.LinkUserID, claims)
+	if err != nil {
+		return authUseCase.Login{}, err
+	}
+
+	session, token, err := userDomain.NewSession(u.ID, time.Now().UTC(), uc.ttl)
+	if err != nil {
+		return authUseCase.Login{}, err
+	}
+	if err := uc.repo.CreateSession(ctx, session); err != nil {
+		return authUseCase.Login{}, err
+	}
+
+	return authUseCase.Login{User: u, Session: session, Token: token}, nil
+}
+
+// resolveUser находит или создает пользователя для учетной записи провайдера.
+func (uc *OIDCLoginUseCase) resolveUser(ctx context.Context, linkUserID int, claims oidc.Claims) (userDomain.User, error) {
+	identity, err := uc.repo.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	switch {
+	case err == nil:
+		if linkUserID != 0 && identity.UserID != linkUserID {
+			return userDomain.User{}, userDomain.ErrIdentityLinked
+		}
+		return uc.repo.GetUserByID(ctx, identity.UserID)
+	case !errors.Is(err, userDomain.ErrIdentityNotFound):
+		return userDomain.User{}, err
+	}
+
+	userID := linkUserID
+	if userID == 0 {
+		u, err := uc.createUser(ctx, claims)
+		if err != nil {
+			return userDomain.User{}, err
+		}
+		userID = u.ID
+	}
+
+	err = uc.repo.CreateIdentity(ctx, userDomain.Identity{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	})
+	if errors.Is(err, userDomain.ErrIdentityLinked) && linkUserID == 0 {
+		// Параллельный первый вход той же учетной записи успел создать своего пользователя - входим в него.
+		return uc.resolveUser(ctx, 0, claims)
+	}
+	if err != nil {
+		return userDomain.User{}, err
+	}
+	return uc.repo.GetUserByID(ctx, userID)
+}
+
+// createUser создает пользователя без пароля с именем из ID-токена.
+// Если имя занято, к нему добавляется случайный числовой суффикс.
+func (uc *OIDCLoginUseCase) createUser(ctx context.Context, claims oidc.Claims) (userDomain.User, error) {
+	base := suggestUsername(claims)
+	for attempt := 0; attempt < usernameAttempts; attempt++ {
+		username := base
+		if attempt > 0 {
+			suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+			if err != nil {
+				return userDomain.User{}, err
+			}
+			username = fmt.Sprintf("%s-%04d", base, suffix.Int64())
+		}
+
+		u, err := uc.repo.CreateUser(ctx, userDomain.User{Username: username, CreatedAt: time.Now().UTC()})
+		if !errors.Is(err, userDomain.ErrUsernameTaken) {
+			return u, err
+		}
+	}
+	return userDomain.User{}, userDomain.ErrUsernameTaken
+}
+
+// suggestUsername выбирает имя из preferred_username или локальной части email,
+// оставляя только символы, допустимые в именах пользователей.
+func suggestUsername(claims oidc.Claims) string {
+	candidate := claims.PreferredUsername
+	if candidate == "" {
+		candidate, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range candidate {
+		if r < 128 && (r == '_' || r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	// Оставляем место для суффикса "-NNNN" в пределах 32 символов.
+	name := b.String()
+	if len(name) > 27 {
+		name = name[:27]
+	}
+	if _, err := userDomain.NormalizeUsername(name); err != nil {
+		return "user"
+	}
+	return name
+}
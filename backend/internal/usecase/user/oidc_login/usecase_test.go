@@ -0,0 +1,279 @@
+package oidc_login
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	userDomain "github.com/heartmarshall/digital-forest/backend/internal/domain/user"
+	"github.com/heartmarshall/digital-forest/backend/internal/oidc"
+	"github.com/heartmarshall/digital-forest/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider - провайдер, который возвращает заранее заданные утверждения.
+type fakeProvider struct {
+	name   string
+	claims oidc.Claims
+	err    error
+	// verifier - с каким PKCE verifier был вызван Exchange.
+	verifier string
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) AuthCodeURL(state, nonce, verifier string) string {
+	return "https://idp.example.com/authorize?state=" + state
+}
+
+func (p *fakeProvider) Exchange(ctx context.Context, code, verifier string) (oidc.Claims, error) {
+	p.verifier = verifier
+	return p.claims, p.err
+}
+
+func TestNewOIDCLoginUseCase(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockUserRepository()
+
+	// Act
+	useCase := NewOIDCLoginUseCase(mockRepo, []Provider{&fakeProvider{name: "zeta"}, &fakeProvider{name: "alpha"}})
+
+	// Assert
+	assert.NotNil(t, useCase)
+	assert.Equal(t, userDomain.DefaultSessionTTL, useCase.ttl)
+	assert.Equal(t, []string{"alpha", "zeta"}, useCase.Providers())
+}
+
+func TestOIDCLoginUseCase_Begin(t *testing.T) {
+	t.Run("stores login state", func(t *testing.T) {
+		// Arrange
+		mockRepo := testutil.NewMockUserRepository()
+		var stored userDomain.LoginState
+		mockRepo.On("CreateLoginState", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(userDomain.LoginState) }).
+			Return(nil)
+		useCase := NewOIDCLoginUseCase(mockRepo, []Provider{&fakeProvider{name: "google"}})
+
+		// Act
+		redirectURL, state, err := useCase.Begin(context.Background(), "google", 7)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "https://idp.example.com/authorize?state="+state, redirectURL)
+		assert.Equal(t, state, stored.State)
+		assert.Equal(t, "google", stored.Provider)
+		assert.Equal(t, 7, stored.LinkUserID)
+		assert.NotEmpty(t, stored.Nonce)
+		assert.GreaterOrEqual(t, len(stored.CodeVerifier), 43)
+		assert.WithinDuration(t, time.Now().Add(userDomain.LoginStateTTL), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		// Arrange
+		mockRepo := testutil.NewMockUserRepository()
+		useCase := NewOIDCLoginUseCase(mockRepo, nil)
+
+		// Act
+		_, _, err := useCase.Begin(context.Background(), "google", 0)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnknownProvider)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestOIDCLoginUseCase_Complete(t *testing.T) {
+	claims := oidc.Claims{
+		Issuer:            "https://idp.example.com",
+		Subject:           "sub-1",
+		Nonce:             "nonce-1",
+		PreferredUsername: "alice",
+		Email:             "alice@example.com",
+	}
+	loginState := userDomain.LoginState{
+		State:        "state-1",
+		Provider:     "google",
+		Nonce:        "nonce-1",
+		CodeVerifier: "verifier-1",
+	}
+	identity := userDomain.Identity{Issuer: claims.Issuer, Subject: claims.Subject, UserID: 7}
+	alice := userDomain.User{ID: 7, Username: "alice"}
+
+	tests := []struct {
+		name          string
+		provider      string
+		claims        oidc.Claims
+		exchangeErr   error
+		mockSetup     func(*testutil.MockUserRepository)
+		expectedUser  userDomain.User
+		expectedError error
+	}{
+		{
+			name:     "existing identity logs in",
+			provider: "google",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(loginState, nil)
+				mockRepo.On("GetIdentity", mock.Anything, claims.Issuer, claims.Subject).Return(identity, nil)
+				mockRepo.On("GetUserByID", mock.Anything, 7).Return(alice, nil)
+				mockRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s userDomain.Session) bool {
+					return s.UserID == 7
+				})).Return(nil)
+			},
+			expectedUser: alice,
+		},
+		{
+			name:     "new identity creates user",
+			provider: "google",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(loginState, nil)
+				mockRepo.On("GetIdentity", mock.Anything, claims.Issuer, claims.Subject).Return(userDomain.Identity{}, userDomain.ErrIdentityNotFound)
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u userDomain.User) bool {
+					return u.Username == "alice" && u.PasswordHash == ""
+				})).Return(userDomain.User{}, userDomain.ErrUsernameTaken).Once()
+				mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u userDomain.User) bool {
+					return len(u.Username) == len("alice-0000") && u.Username[:6] == "alice-"
+				})).Return(userDomain.User{ID: 8, Username: "alice-0042"}, nil).Once()
+				mockRepo.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(id userDomain.Identity) bool {
+					return id.Issuer == claims.Issuer && id.Subject == claims.Subject && id.UserID == 8
+				})).Return(nil)
+				mockRepo.On("GetUserByID", mock.Anything, 8).Return(userDomain.User{ID: 8, Username: "alice-0042"}, nil)
+				mockRepo.On("CreateSession", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedUser: userDomain.User{ID: 8, Username: "alice-0042"},
+		},
+		{
+			name:     "new identity links to logged in user",
+			provider: "google",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				linking := loginState
+				linking.LinkUserID = 3
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(linking, nil)
+				mockRepo.On("GetIdentity", mock.Anything, claims.Issuer, claims.Subject).Return(userDomain.Identity{}, userDomain.ErrIdentityNotFound)
+				mockRepo.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(id userDomain.Identity) bool {
+					return id.UserID == 3
+				})).Return(nil)
+				mockRepo.On("GetUserByID", mock.Anything, 3).Return(userDomain.User{ID: 3, Username: "bob"}, nil)
+				mockRepo.On("CreateSession", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedUser: userDomain.User{ID: 3, Username: "bob"},
+		},
+		{
+			name:     "identity linked to another user",
+			provider: "google",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				linking := loginState
+				linking.LinkUserID = 3
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(linking, nil)
+				mockRepo.On("GetIdentity", mock.Anything, claims.Issuer, claims.Subject).Return(identity, nil)
+			},
+			expectedError: userDomain.ErrIdentityLinked,
+		},
+		{
+			name:     "unknown state",
+			provider: "google",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(userDomain.LoginState{}, userDomain.ErrLoginStateNotFound)
+			},
+			expectedError: userDomain.ErrLoginStateNotFound,
+		},
+		{
+			name:     "state issued for another provider",
+			provider: "github",
+			claims:   claims,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(loginState, nil)
+			},
+			expectedError: userDomain.ErrLoginStateNotFound,
+		},
+		{
+			name:        "exchange rejected",
+			provider:    "google",
+			claims:      claims,
+			exchangeErr: oidc.ErrTokenRejected,
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(loginState, nil)
+			},
+			expectedError: ErrLoginFailed,
+		},
+		{
+			name:     "nonce mismatch",
+			provider: "google",
+			claims:   oidc.Claims{Issuer: claims.Issuer, Subject: claims.Subject, Nonce: "replayed"},
+			mockSetup: func(mockRepo *testutil.MockUserRepository) {
+				mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(loginState, nil)
+			},
+			expectedError: ErrLoginFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := testutil.NewMockUserRepository()
+			tt.mockSetup(mockRepo)
+			google := &fakeProvider{name: "google", claims: tt.claims, err: tt.exchangeErr}
+			useCase := NewOIDCLoginUseCase(mockRepo, []Provider{google, &fakeProvider{name: "github"}})
+
+			// Act
+			login, err := useCase.Complete(context.Background(), tt.provider, "state-1", "code-1")
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, login.User)
+				assert.NotEmpty(t, login.Token)
+				assert.Equal(t, userDomain.HashSessionToken(login.Token), login.Session.TokenHash)
+				assert.Equal(t, "verifier-1", google.verifier)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCLoginUseCase_Complete_RepositoryError(t *testing.T) {
+	// Arrange
+	mockRepo := testutil.NewMockUserRepository()
+	repoErr := errors.New("database down")
+	mockRepo.On("ConsumeLoginState", mock.Anything, "state-1").Return(userDomain.LoginState{Provider: "google", Nonce: "n"}, nil)
+	mockRepo.On("GetIdentity", mock.Anything, "iss", "sub").Return(userDomain.Identity{}, repoErr)
+	provider := &fakeProvider{name: "google", claims: oidc.Claims{Issuer: "iss", Subject: "sub", Nonce: "n"}}
+	useCase := NewOIDCLoginUseCase(mockRepo, []Provider{provider})
+
+	// Act
+	_, err := useCase.Complete(context.Background(), "google", "state-1", "code-1")
+
+	// Assert
+	assert.ErrorIs(t, err, repoErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSuggestUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   oidc.Claims
+		expected string
+	}{
+		{name: "preferred username", claims: oidc.Claims{PreferredUsername: "alice.w"}, expected: "alice.w"},
+		{name: "email local part", claims: oidc.Claims{Email: "bob_smith@example.com"}, expected: "bob_smith"},
+		{name: "invalid characters dropped", claims: oidc.Claims{PreferredUsername: "Ann Lee!"}, expected: "AnnLee"},
+		{name: "non latin falls back", claims: oidc.Claims{PreferredUsername: "Иван"}, expected: "user"},
+		{name: "too long is truncated", claims: oidc.Claims{PreferredUsername: "abcdefghijklmnopqrstuvwxyz0123456789"}, expected: "abcdefghijklmnopqrstuvwxyz0"},
+		{name: "empty falls back", claims: oidc.Claims{}, expected: "user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, suggestUsername(tt.claims))
+		})
+	}
+}
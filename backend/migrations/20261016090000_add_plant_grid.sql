@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Каноническая пиксельная сетка растения (см. domain.Grid.MarshalBinary).
+-- Для новых растений хранится только сетка, image_data остается у старых записей.
+ALTER TABLE plants ADD COLUMN grid BYTEA;
+ALTER TABLE plants ALTER COLUMN image_data DROP NOT NULL;
+ALTER TABLE plants ADD CONSTRAINT plants_image_present CHECK (image_data IS NOT NULL OR grid IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Растения, у которых есть только сетка, без нее потеряют изображение. Вместо удаления пользовательских
+-- данных откат отказывается выполняться, пока такие растения есть.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM plants WHERE image_data IS NULL) THEN
+        RAISE EXCEPTION 'cannot roll back: plants without image_data exist, they store only the grid';
+    END IF;
+END $$;
+ALTER TABLE plants DROP CONSTRAINT IF EXISTS plants_image_present;
+ALTER TABLE plants ALTER COLUMN image_data SET NOT NULL;
+ALTER TABLE plants DROP COLUMN IF EXISTS grid;
+-- +goose StatementEnd
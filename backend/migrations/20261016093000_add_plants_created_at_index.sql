@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Индексы для keyset-пагинации списка растений по (created_at, id)
+-- и для фильтрации по автору.
+CREATE INDEX IF NOT EXISTS plants_created_at_id_idx ON plants (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS plants_author_created_at_id_idx ON plants (author, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS plants_author_created_at_id_idx;
+DROP INDEX IF EXISTS plants_created_at_id_idx;
+-- +goose StatementEnd
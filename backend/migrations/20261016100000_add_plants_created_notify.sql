@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Уведомляет все экземпляры бэкенда о новом растении (см. postgres.PlantListener).
+-- В уведомлении передается только id: размер payload в NOTIFY ограничен 8000 байт.
+CREATE OR REPLACE FUNCTION notify_plant_created() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('plants_created', NEW.id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER plants_created_notify
+    AFTER INSERT ON plants
+    FOR EACH ROW EXECUTE FUNCTION notify_plant_created();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS plants_created_notify ON plants;
+DROP FUNCTION IF EXISTS notify_plant_created();
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Жизненный цикл растения (см. domain.Status). Публично видны только растения в статусе visible.
+ALTER TABLE plants ADD COLUMN status TEXT NOT NULL DEFAULT 'visible';
+ALTER TABLE plants ADD CONSTRAINT plants_status_valid CHECK (status IN ('visible', 'pending', 'hidden', 'deleted'));
+ALTER TABLE plants ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- Публичный список читает только видимые растения, модераторы - остальные статусы.
+DROP INDEX IF EXISTS plants_created_at_id_idx;
+CREATE INDEX plants_visible_created_at_id_idx ON plants (created_at DESC, id DESC) WHERE status = 'visible';
+CREATE INDEX plants_status_created_at_id_idx ON plants (status, created_at DESC, id DESC) WHERE status <> 'visible';
+
+-- Журнал модерации. Внешнего ключа на plants нет: записи о растениях, удаленных окончательно, сохраняются.
+CREATE TABLE moderation_log (
+    id BIGSERIAL PRIMARY KEY,
+    plant_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX moderation_log_plant_id_idx ON moderation_log (plant_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS moderation_log;
+DROP INDEX IF EXISTS plants_status_created_at_id_idx;
+DROP INDEX IF EXISTS plants_visible_created_at_id_idx;
+CREATE INDEX IF NOT EXISTS plants_created_at_id_idx ON plants (created_at DESC, id DESC);
+ALTER TABLE plants DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE plants DROP CONSTRAINT IF EXISTS plants_status_valid;
+ALTER TABLE plants DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Жалобы посетителей. Жалоба открыта, пока resolved_at пуст; рассмотренные жалобы остаются для истории.
+CREATE TABLE plant_reports (
+    id BIGSERIAL PRIMARY KEY,
+    plant_id INTEGER NOT NULL REFERENCES plants (id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    reporter_hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+-- Один посетитель - одна открытая жалоба на растение.
+CREATE UNIQUE INDEX plant_reports_open_reporter_idx ON plant_reports (plant_id, reporter_hash) WHERE resolved_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS plant_reports;
+-- +goose StatementEnd
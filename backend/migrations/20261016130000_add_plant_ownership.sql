@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Хэш токена владельца (сам токен отдается автору один раз). У старых растений владельца нет.
+ALTER TABLE plants ADD COLUMN owner_token_hash TEXT;
+-- Версия изображения входит в его URL: изображения кешируются как immutable, и новая версия должна получить новый адрес.
+ALTER TABLE plants ADD COLUMN image_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE plants ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE plants DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE plants DROP COLUMN IF EXISTS image_version;
+ALTER TABLE plants DROP COLUMN IF EXISTS owner_token_hash;
+-- +goose StatementEnd
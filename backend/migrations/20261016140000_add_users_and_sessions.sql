@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- Имена различаются без учета регистра: Alice и alice - один и тот же пользователь.
+CREATE UNIQUE INDEX users_username_lower_idx ON users (lower(username));
+
+-- Серверные сессии. В cookie лежит токен, в таблице - только его хэш.
+CREATE TABLE sessions (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    csrf_token TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+
+-- Анонимные растения остаются без пользователя; удаление пользователя не удаляет его растения.
+ALTER TABLE plants ADD COLUMN user_id INTEGER REFERENCES users (id) ON DELETE SET NULL;
+CREATE INDEX plants_user_id_created_at_idx ON plants (user_id, created_at DESC, id DESC) WHERE user_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS plants_user_id_created_at_idx;
+ALTER TABLE plants DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
+-- +goose StatementEnd
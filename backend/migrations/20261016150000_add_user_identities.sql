@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Учетные записи внешних провайдеров OpenID Connect, привязанные к пользователям.
+CREATE TABLE user_identities (
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject)
+);
+CREATE INDEX user_identities_user_id_idx ON user_identities (user_id);
+
+-- Незавершенные входы через провайдера: state, nonce и PKCE verifier живут до возвращения браузера.
+CREATE TABLE oidc_login_states (
+    state TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    link_user_id INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX oidc_login_states_expires_at_idx ON oidc_login_states (expires_at);
+
+-- У пользователей, пришедших через провайдера, пароля нет.
+ALTER TABLE users ALTER COLUMN password_hash SET DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN password_hash DROP DEFAULT;
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Корзины токенов ограничителя частоты запросов, общие для всех реплик.
+-- Наполнившиеся корзины (full_at в прошлом) ничем не отличаются от отсутствующих и периодически удаляются.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    full_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX rate_limit_buckets_full_at_idx ON rate_limit_buckets (full_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limit_buckets;
+-- +goose StatementEnd
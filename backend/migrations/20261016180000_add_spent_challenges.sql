@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Использованные задачи proof-of-work. Строка нужна только до expires_at: после него токен отклоняется и так.
+CREATE UNLOGGED TABLE spent_challenges (
+    id TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX spent_challenges_expires_at_idx ON spent_challenges (expires_at);
+
+-- Сложность задач зависит от числа растений, посаженных за последние минуты, во всех статусах.
+CREATE INDEX plants_created_at_idx ON plants (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS plants_created_at_idx;
+DROP TABLE IF EXISTS spent_challenges;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ответы на запросы с заголовком Idempotency-Key. Ключ хранится только в виде хэша,
+-- а ответ зашифрован ключом: в нем ownerToken, который сама база не должна знать.
+-- status IS NULL - первый запрос еще выполняется.
+CREATE TABLE idempotency_keys (
+    key_hash TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    status INTEGER,
+    body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
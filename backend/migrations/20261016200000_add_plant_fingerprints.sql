@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Отпечаток изображения для поиска дубликатов: точный SHA-256 и перцептивные aHash и dHash.
+-- 64-битные хэши хранятся как BIGINT с тем же набором битов; расстояние - bit_count((a # b)::bit(64)).
+-- У растений, посаженных раньше, колонки пусты до запуска "app fingerprints backfill".
+ALTER TABLE plants
+    ADD COLUMN content_hash TEXT,
+    ADD COLUMN ahash BIGINT,
+    ADD COLUMN dhash BIGINT;
+CREATE INDEX plants_content_hash_idx ON plants (content_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS plants_content_hash_idx;
+ALTER TABLE plants
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS ahash,
+    DROP COLUMN IF EXISTS dhash;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+// Package migrations встраивает в бинарник миграции схемы базы в формате goose.
+// Применяет их пакет migrate: при старте сервиса (postgres.migrate_on_start) или командой "app migrate".
+package migrations
+
+import "embed"
+
+// FS - файлы миграций *.sql; имя файла начинается с версии миграции.
+//
+//go:embed *.sql
+var FS embed.FS
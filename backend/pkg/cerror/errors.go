@@ -1 +1,153 @@
+// Package cerror описывает ошибки приложения: вид ошибки определяет ответ клиенту,
+// а сообщение ошибки, если она не внутренняя, уходит ему в поле detail (см. Respond).
 package cerror
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Kind - вид ошибки. От него зависят HTTP-статус, type и title ответа.
+// Kind сам реализует error, поэтому errors.Is(err, cerror.NotFound) проверяет вид ошибки в цепочке.
+type Kind int
+
+const (
+	// Internal - сбой сервера; сообщение такой ошибки клиенту не показывается. Вид ошибок без Kind.
+	Internal Kind = iota
+	// Invalid - запрос некорректен: параметры, тело или отдельные поля (см. Error.Fields).
+	Invalid
+	// Unauthorized - запрос требует входа или действующего ключа.
+	Unauthorized
+	// Forbidden - у клиента нет прав на действие.
+	Forbidden
+	// NotFound - запрошенного объекта нет.
+	NotFound
+	// Conflict - действие противоречит текущему состоянию объекта.
+	Conflict
+	// TooLarge - данные запроса больше допустимого.
+	TooLarge
+	// Unprocessable - запрос корректен по форме, но его содержимое нельзя принять.
+	Unprocessable
+	// RateLimited - клиент превысил лимит запросов.
+	RateLimited
+	// Unavailable - нужная часть сервиса временно недоступна.
+	Unavailable
+)
+
+// kindInfo - как вид ошибки выглядит в ответе.
+type kindInfo struct {
+	status int
+	slug   string
+	title  string
+}
+
+var kinds = map[Kind]kindInfo{
+	Internal:      {http.StatusInternalServerError, "internal", "Internal server error"},
+	Invalid:       {http.StatusBadRequest, "invalid", "Invalid request"},
+	Unauthorized:  {http.StatusUnauthorized, "unauthorized", "Unauthorized"},
+	Forbidden:     {http.StatusForbidden, "forbidden", "Forbidden"},
+	NotFound:      {http.StatusNotFound, "not-found", "Not found"},
+	Conflict:      {http.StatusConflict, "conflict", "Conflict"},
+	TooLarge:      {http.StatusRequestEntityTooLarge, "too-large", "Request is too large"},
+	Unprocessable: {http.StatusUnprocessableEntity, "unprocessable", "Request cannot be processed"},
+	RateLimited:   {http.StatusTooManyRequests, "rate-limited", "Too many requests"},
+	Unavailable:   {http.StatusServiceUnavailable, "unavailable", "Service unavailable"},
+}
+
+func (k Kind) info() kindInfo {
+	if info, ok := kinds[k]; ok {
+		return info
+	}
+	return kinds[Internal]
+}
+
+// Status возвращает HTTP-статус ответа для ошибки этого вида.
+func (k Kind) Status() int {
+	return k.info().status
+}
+
+// Title возвращает краткое описание вида ошибки.
+func (k Kind) Title() string {
+	return k.info().title
+}
+
+// Type возвращает идентификатор вида ошибки для поля type ответа, например "/problems/not-found".
+func (k Kind) Type() string {
+	return "/problems/" + k.info().slug
+}
+
+// Error позволяет использовать Kind как цель errors.Is.
+func (k Kind) Error() string {
+	return k.Title()
+}
+
+// Error - ошибка приложения определенного вида.
+type Error struct {
+	Kind Kind
+	// Detail - сообщение об ошибке. Для Wrap может быть пустым, тогда сообщением служит причина.
+	Detail string
+	// Fields - ошибки отдельных полей запроса: имя поля -> сообщение,
+	// как в ответе Validator.ValidateStruct.
+	Fields map[string]string
+	// Err - причина ошибки.
+	Err error
+}
+
+// New создает ошибку вида kind с сообщением detail.
+func New(kind Kind, detail string) *Error {
+	return &Error{Kind: kind, Detail: detail}
+}
+
+// Wrap присваивает ошибке err вид kind, сохраняя ее сообщение и цепочку для errors.Is и errors.As.
+// Так use case уточняет вид чужой ошибки, например недопустимый размер изображения в параметре запроса.
+func Wrap(kind Kind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// Validation создает ошибку Invalid с ошибками полей, например из Validator.ValidateStruct.
+func Validation(fields map[string]string) *Error {
+	return &Error{Kind: Invalid, Detail: "request has invalid fields", Fields: fields}
+}
+
+// WithField возвращает копию ошибки с сообщением об ошибке поля name; исходная ошибка не меняется.
+func (e *Error) WithField(name, message string) *Error {
+	c := *e
+	c.Fields = maps.Clone(e.Fields)
+	if c.Fields == nil {
+		c.Fields = make(map[string]string, 1)
+	}
+	c.Fields[name] = message
+	return &c
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Detail
+	case e.Detail == "":
+		return e.Err.Error()
+	default:
+		return e.Detail + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap возвращает причину ошибки.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сообщает, что ошибка имеет вид target, если target - Kind.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// KindOf возвращает вид первой ошибки Error в цепочке err или Internal, если ее там нет.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return Internal
+}
@@ -0,0 +1,100 @@
+package cerror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	notFound := New(NotFound, "plant not found")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected Kind
+	}{
+		{name: "error", err: notFound, expected: NotFound},
+		{name: "wrapped with fmt", err: fmt.Errorf("get plant: %w", notFound), expected: NotFound},
+		{name: "wrap overrides kind", err: Wrap(Invalid, notFound), expected: Invalid},
+		{name: "plain error", err: errors.New("connection refused"), expected: Internal},
+		{name: "nil", err: nil, expected: Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, KindOf(tt.err))
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	sentinel := New(Conflict, "plant already reported")
+	err := fmt.Errorf("report: %w", sentinel)
+
+	assert.ErrorIs(t, err, sentinel)
+	assert.ErrorIs(t, err, Conflict)
+	assert.NotErrorIs(t, err, NotFound)
+	assert.NotErrorIs(t, err, New(Conflict, "plant already reported"))
+
+	// Wrap меняет вид, но цепочка до исходной ошибки сохраняется
+	wrapped := Wrap(Invalid, err)
+	assert.ErrorIs(t, wrapped, Invalid)
+	assert.ErrorIs(t, wrapped, sentinel)
+	assert.Equal(t, "report: plant already reported", wrapped.Error())
+}
+
+func TestError_Error(t *testing.T) {
+	cause := errors.New("unexpected EOF")
+
+	tests := []struct {
+		name     string
+		err      *Error
+		expected string
+	}{
+		{name: "detail", err: New(Invalid, "bad cursor"), expected: "bad cursor"},
+		{name: "cause only", err: Wrap(Internal, cause), expected: "unexpected EOF"},
+		{name: "detail and cause", err: &Error{Kind: Internal, Detail: "decode png", Err: cause}, expected: "decode png: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.Error())
+		})
+	}
+}
+
+func TestError_WithField(t *testing.T) {
+	base := New(Invalid, "author is not allowed")
+
+	withAuthor := base.WithField("author", "is not allowed")
+	withBoth := withAuthor.WithField("grid", "is required")
+
+	assert.Nil(t, base.Fields)
+	assert.Equal(t, map[string]string{"author": "is not allowed"}, withAuthor.Fields)
+	assert.Equal(t, map[string]string{"author": "is not allowed", "grid": "is required"}, withBoth.Fields)
+}
+
+func TestKind_Response(t *testing.T) {
+	tests := []struct {
+		kind           Kind
+		expectedStatus int
+		expectedType   string
+	}{
+		{kind: Internal, expectedStatus: http.StatusInternalServerError, expectedType: "/problems/internal"},
+		{kind: Invalid, expectedStatus: http.StatusBadRequest, expectedType: "/problems/invalid"},
+		{kind: NotFound, expectedStatus: http.StatusNotFound, expectedType: "/problems/not-found"},
+		{kind: RateLimited, expectedStatus: http.StatusTooManyRequests, expectedType: "/problems/rate-limited"},
+		{kind: Kind(100), expectedStatus: http.StatusInternalServerError, expectedType: "/problems/internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedType, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, tt.kind.Status())
+			assert.Equal(t, tt.expectedType, tt.kind.Type())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package cerror
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/heartmarshall/digital-forest/backend/pkg/logger"
+)
+
+// ContentType - тип содержимого ответа с ошибкой (RFC 7807).
+const ContentType = "application/problem+json"
+
+// Problem - тело ответа с ошибкой в формате RFC 7807.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance - путь запроса, на который получена ошибка.
+	Instance string `json:"instance,omitempty"`
+	// Fields - ошибки отдельных полей запроса.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ProblemOf описывает ошибку err для клиента.
+// Сообщение внутренней ошибки не раскрывается: в detail попадает только то, что задано для нее в New.
+func ProblemOf(err error, instance string) Problem {
+	kind := KindOf(err)
+	p := Problem{
+		Type:     kind.Type(),
+		Title:    kind.Title(),
+		Status:   kind.Status(),
+		Instance: instance,
+		Fields:   fieldsOf(err),
+	}
+
+	var e *Error
+	switch {
+	case kind != Internal:
+		p.Detail = err.Error()
+	case errors.As(err, &e):
+		p.Detail = e.Detail
+	}
+	return p
+}
+
+// fieldsOf возвращает ошибки полей первой ошибки Error в цепочке, у которой они есть.
+func fieldsOf(err error) map[string]string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*Error); ok && len(e.Fields) > 0 {
+			return e.Fields
+		}
+	}
+	return nil
+}
+
+// Respond отправляет ошибку err в формате application/problem+json.
+// Внутренние ошибки записываются в логгер запроса (см. logger.FromContext), клиент видит только общий ответ.
+func Respond(w http.ResponseWriter, r *http.Request, err error) {
+	p := ProblemOf(err, r.URL.Path)
+	if p.Status == http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error("request failed", slog.Any("error", err))
+	}
+
+	body, marshalErr := json.Marshal(p)
+	if marshalErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
+
+// WriteJSON отправляет успешный ответ payload в формате application/json с кодом status.
+// Ошибки отправляются через Respond.
+func WriteJSON(w http.ResponseWriter, status int, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,121 @@
+package cerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Problem
+	}{
+		{
+			name: "not found",
+			err:  fmt.Errorf("get plant 7: %w", New(NotFound, "plant not found")),
+			expected: Problem{
+				Type: "/problems/not-found", Title: "Not found", Status: http.StatusNotFound,
+				Detail: "get plant 7: plant not found", Instance: "/v1/plants/7",
+			},
+		},
+		{
+			name: "validation",
+			err:  Validation(map[string]string{"Author": "field 'Author' is required"}),
+			expected: Problem{
+				Type: "/problems/invalid", Title: "Invalid request", Status: http.StatusBadRequest,
+				Detail: "request has invalid fields", Instance: "/v1/plants/7",
+				Fields: map[string]string{"Author": "field 'Author' is required"},
+			},
+		},
+		{
+			name: "fields of wrapped error",
+			err:  Wrap(Invalid, fmt.Errorf("check: %w", New(Invalid, "bad").WithField("author", "is not allowed"))),
+			expected: Problem{
+				Type: "/problems/invalid", Title: "Invalid request", Status: http.StatusBadRequest,
+				Detail: "check: bad", Instance: "/v1/plants/7",
+				Fields: map[string]string{"author": "is not allowed"},
+			},
+		},
+		{
+			name: "internal message is hidden",
+			err:  errors.New("pq: password authentication failed"),
+			expected: Problem{
+				Type: "/problems/internal", Title: "Internal server error", Status: http.StatusInternalServerError,
+				Instance: "/v1/plants/7",
+			},
+		},
+		{
+			name: "internal detail is shown without cause",
+			err:  &Error{Detail: "storage failure", Err: errors.New("disk full")},
+			expected: Problem{
+				Type: "/problems/internal", Title: "Internal server error", Status: http.StatusInternalServerError,
+				Detail: "storage failure", Instance: "/v1/plants/7",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ProblemOf(tt.err, "/v1/plants/7"))
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	// Arrange
+	req := httptest.NewRequest(http.MethodDelete, "/v1/plants/3", nil)
+	rr := httptest.NewRecorder()
+
+	// Act
+	Respond(rr, req, New(Forbidden, "plant belongs to another user"))
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, ContentType, rr.Header().Get("Content-Type"))
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+	assert.Equal(t, Problem{
+		Type: "/problems/forbidden", Title: "Forbidden", Status: http.StatusForbidden,
+		Detail: "plant belongs to another user", Instance: "/v1/plants/3",
+	}, problem)
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		payload        any
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "payload", payload: map[string]int{"id": 1}, expectedStatus: http.StatusCreated, expectedBody: `{"id":1}`},
+		{name: "unencodable payload", payload: make(chan int), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rr := httptest.NewRecorder()
+
+			// Act
+			WriteJSON(rr, http.StatusCreated, tt.payload)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody == "" {
+				assert.Empty(t, rr.Body.String())
+				return
+			}
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package logger создает логгер slog по настройкам и передает логгер запроса через context.
+// Use cases и репозитории берут его через FromContext и пишут записи с атрибутами запроса:
+// request_id, маршрутом, идентификатором растения.
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Форматы записей для New.
+const (
+	// FormatJSON - по записи JSON на строку, для сбора логов.
+	FormatJSON = "json"
+	// FormatText - key=value, для чтения в терминале при разработке.
+	FormatText = "text"
+)
+
+// New создает логгер, пишущий в w записи уровня level (debug, info, warn, error) и выше в формате format.
+// Пустые level и format означают info и FormatJSON.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("unknown level %q", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+type loggerKey struct{}
+
+// WithContext возвращает контекст с логгером l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext возвращает логгер из ctx или slog.Default(), если его там нет.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// With возвращает контекст, логгер которого дополнен атрибутами args (см. slog.Logger.With).
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
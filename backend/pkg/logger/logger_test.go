@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		level         string
+		format        string
+		expectedErr   bool
+		expectedDebug bool
+		expectedJSON  bool
+	}{
+		{name: "defaults", expectedJSON: true},
+		{name: "json debug", level: "debug", format: "json", expectedDebug: true, expectedJSON: true},
+		{name: "text warn", level: "WARN", format: "text"},
+		{name: "unknown level", level: "verbose", expectedErr: true},
+		{name: "unknown format", format: "xml", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var out bytes.Buffer
+
+			// Act
+			l, err := New(&out, tt.level, tt.format)
+
+			// Assert
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDebug, l.Enabled(context.Background(), slog.LevelDebug))
+			assert.True(t, l.Enabled(context.Background(), slog.LevelError))
+
+			l.Error("boom")
+			assert.Equal(t, tt.expectedJSON, json.Valid(out.Bytes()))
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&out, nil))
+
+	// Act
+	ctx := With(WithContext(context.Background(), base), "request_id", "abc")
+	FromContext(ctx).Info("plant created")
+
+	// Assert
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "abc", record["request_id"])
+	assert.Equal(t, "plant created", record["msg"])
+	// Без логгера в контексте используется логгер по умолчанию
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+}
@@ -27,22 +27,38 @@ export const fetchRandomPlants = async (count = 15) => {
   }
 };
 
+// Сколько раз повторять посадку, если ответ не дошел из-за сети.
+const CREATE_RETRIES = 2;
+
 /**
  * Отправляет данные нового растения на сервер.
+ * Все попытки идут с одним Idempotency-Key, поэтому повтор после обрыва связи
+ * получает исходный ответ, а не сажает второе растение.
  * @param {string} author - Имя автора.
  * @param {string} imageData - Данные изображения в base64.
  * @returns {Promise<Object>} - Промис, который разрешается созданным объектом растения.
  */
 export const createPlant = async (author, imageData) => {
-  try {
-    // Отправляем POST-запрос на /plants
-    const response = await axios.post(`${API_BASE_URL}/plants`, {
-      author: author,
-      imageData: imageData
-    });
-    return response.data;
-  } catch (error) {
-    console.error("Ошибка при создании растения:", error);
-    throw error;
+  const idempotencyKey = crypto.randomUUID();
+  for (let attempt = 0; ; attempt++) {
+    try {
+      // Отправляем POST-запрос на /plants
+      const response = await axios.post(`${API_BASE_URL}/plants`, {
+        author: author,
+        imageData: imageData
+      }, {
+        headers: { 'Idempotency-Key': idempotencyKey }
+      });
+      return response.data;
+    } catch (error) {
+      // Повторяем только запросы, на которые не пришло ответа; 409 значит, что первая попытка еще выполняется.
+      const retriable = !error.response || error.response.status === 409;
+      if (retriable && attempt < CREATE_RETRIES) {
+        await new Promise((resolve) => setTimeout(resolve, 1000 * (attempt + 1)));
+        continue;
+      }
+      console.error("Ошибка при создании растения:", error);
+      throw error;
+    }
   }
 };
\ No newline at end of file